@@ -35,6 +35,10 @@ func CmdMain() {
 			}
 			klog.Fatal(server.ListenAndServe())
 		}()
+		if config.ProbePort != 0 {
+			pinger.StartProbeServer(config)
+		}
+		go pinger.StartConnectivityChecks(config)
 	}
 	e := pinger.NewExporter(config)
 	pinger.StartPinger(config, e)
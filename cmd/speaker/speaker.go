@@ -22,6 +22,7 @@ func CmdMain() {
 		klog.Fatalf("failed to parse config %v", err)
 	}
 
+	speaker.InitSpeakerMetrics()
 	stopCh := signals.SetupSignalHandler()
 	ctl := speaker.NewController(config)
 
@@ -17,6 +17,7 @@ LS_DNAT_MOD_DL_DST=${LS_DNAT_MOD_DL_DST:-true}
 ENABLE_EXTERNAL_VPC=${ENABLE_EXTERNAL_VPC:-true}
 CNI_CONFIG_PRIORITY=${CNI_CONFIG_PRIORITY:-01}
 ENABLE_LB_SVC=${ENABLE_LB_SVC:-false}
+LB_SVC_MODE=${LB_SVC_MODE:-pod}
 ENABLE_KEEP_VM_IP=${ENABLE_KEEP_VM_IP:-true}
 # exchange link names of OVS bridge and the provider nic
 # in the default provider-network
@@ -320,6 +321,10 @@ spec:
                   type: string
                 sessionAffinity:
                   type: string
+                sessionAffinityTimeout:
+                  type: integer
+                  minimum: 1
+                  maximum: 86400
                 ports:
                   items:
                     properties:
@@ -331,16 +336,54 @@ spec:
                         maximum: 65535
                       protocol:
                         type: string
+                        enum:
+                          - TCP
+                          - UDP
+                          - SCTP
                       targetPort:
                         type: integer
                         minimum: 1
                         maximum: 65535
+                      targetProtocol:
+                        type: string
+                        enum:
+                          - TCP
+                          - UDP
+                          - SCTP
                     type: object
                   type: array
                 selector:
                   items:
                     type: string
                   type: array
+                backends:
+                  items:
+                    properties:
+                      ip:
+                        type: string
+                      weight:
+                        type: integer
+                        minimum: 1
+                        maximum: 100
+                    required:
+                      - ip
+                    type: object
+                  type: array
+                healthCheck:
+                  type: object
+                  properties:
+                    interval:
+                      type: integer
+                      minimum: 1
+                    timeout:
+                      type: integer
+                      minimum: 1
+                    successCount:
+                      type: integer
+                      minimum: 1
+                    failureCount:
+                      type: integer
+                      minimum: 1
             status:
               type: object
               properties:
@@ -348,6 +391,32 @@ spec:
                   type: string
                 service:
                   type: string
+                backendHealth:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      address:
+                        type: string
+                      port:
+                        type: integer
+                      protocol:
+                        type: string
+                      status:
+                        type: string
+                backends:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      vip:
+                        type: string
+                      protocol:
+                        type: string
+                      backends:
+                        type: array
+                        items:
+                          type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -395,6 +464,10 @@ spec:
                   type: string
                 sessionAffinity:
                   type: string
+                sessionAffinityTimeout:
+                  type: integer
+                  minimum: 1
+                  maximum: 86400
                 ports:
                   items:
                     properties:
@@ -406,16 +479,54 @@ spec:
                         maximum: 65535
                       protocol:
                         type: string
+                        enum:
+                          - TCP
+                          - UDP
+                          - SCTP
                       targetPort:
                         type: integer
                         minimum: 1
                         maximum: 65535
+                      targetProtocol:
+                        type: string
+                        enum:
+                          - TCP
+                          - UDP
+                          - SCTP
                     type: object
                   type: array
                 selector:
                   items:
                     type: string
                   type: array
+                backends:
+                  items:
+                    properties:
+                      ip:
+                        type: string
+                      weight:
+                        type: integer
+                        minimum: 1
+                        maximum: 100
+                    required:
+                      - ip
+                    type: object
+                  type: array
+                healthCheck:
+                  type: object
+                  properties:
+                    interval:
+                      type: integer
+                      minimum: 1
+                    timeout:
+                      type: integer
+                      minimum: 1
+                    successCount:
+                      type: integer
+                      minimum: 1
+                    failureCount:
+                      type: integer
+                      minimum: 1
             status:
               type: object
               properties:
@@ -423,6 +534,32 @@ spec:
                   type: string
                 service:
                   type: string
+                backendHealth:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      address:
+                        type: string
+                      port:
+                        type: integer
+                      protocol:
+                        type: string
+                      status:
+                        type: string
+                backends:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      vip:
+                        type: string
+                      protocol:
+                        type: string
+                      backends:
+                        type: array
+                        items:
+                          type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -927,6 +1064,23 @@ spec:
                   type: string
                 udpSessionLoadBalancer:
                   type: string
+                sctpLoadBalancer:
+                  type: string
+                sctpSessionLoadBalancer:
+                  type: string
+                learnedRoutes:
+                  items:
+                    properties:
+                      node:
+                        type: string
+                      source:
+                        type: string
+                      cidr:
+                        type: string
+                      nextHopIP:
+                        type: string
+                    type: object
+                  type: array
               type: object
           type: object
       served: true
@@ -1520,12 +1674,25 @@ spec:
                         type: string
                       remoteSecurityGroup:
                         type: string
+                      remoteAddressSet:
+                        type: string
+                        pattern: '^[a-zA-Z_.][a-zA-Z_.0-9]*$'
                       portRangeMin:
                         type: integer
                       portRangeMax:
                         type: integer
+                      ports:
+                        type: array
+                        items:
+                          type: integer
+                      icmpType:
+                        type: integer
+                      icmpCode:
+                        type: integer
                       policy:
                         type: string
+                      stateless:
+                        type: boolean
                 egressRules:
                   type: array
                   items:
@@ -1543,14 +1710,67 @@ spec:
                         type: string
                       remoteSecurityGroup:
                         type: string
+                      remoteAddressSet:
+                        type: string
+                        pattern: '^[a-zA-Z_.][a-zA-Z_.0-9]*$'
                       portRangeMin:
                         type: integer
                       portRangeMax:
                         type: integer
+                      ports:
+                        type: array
+                        items:
+                          type: integer
+                      icmpType:
+                        type: integer
+                      icmpCode:
+                        type: integer
                       policy:
                         type: string
+                      stateless:
+                        type: boolean
                 allowSameGroupTraffic:
                   type: boolean
+                podSelector:
+                  type: object
+                  properties:
+                    matchLabels:
+                      type: object
+                      additionalProperties:
+                        type: string
+                    matchExpressions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          key:
+                            type: string
+                          operator:
+                            type: string
+                          values:
+                            type: array
+                            items:
+                              type: string
+                namespaceSelector:
+                  type: object
+                  properties:
+                    matchLabels:
+                      type: object
+                      additionalProperties:
+                        type: string
+                    matchExpressions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          key:
+                            type: string
+                          operator:
+                            type: string
+                          values:
+                            type: array
+                            items:
+                              type: string
             status:
               type: object
               properties:
@@ -1566,6 +1786,8 @@ spec:
                   type: boolean
                 egressLastSyncSuccess:
                   type: boolean
+                boundPorts:
+                  type: integer
       subresources:
         status: {}
   conversion:
@@ -1601,6 +1823,337 @@ spec:
     kind: HtbQos
     shortNames:
       - htbqos
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: load-balancer-ip-pools.kubeovn.io
+spec:
+  group: kubeovn.io
+  names:
+    plural: load-balancer-ip-pools
+    singular: load-balancer-ip-pool
+    shortNames:
+      - lbippool
+    kind: LoadBalancerIPPool
+    listKind: LoadBalancerIPPoolList
+  scope: Cluster
+  versions:
+    - additionalPrinterColumns:
+        - jsonPath: .spec.cidrBlock
+          name: CIDR
+          type: string
+        - jsonPath: .status.v4availableIPs
+          name: V4Available
+          type: number
+        - jsonPath: .status.v4usingIPs
+          name: V4Used
+          type: number
+        - jsonPath: .status.v6availableIPs
+          name: V6Available
+          type: number
+        - jsonPath: .status.v6usingIPs
+          name: V6Used
+          type: number
+      name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - cidrBlock
+              properties:
+                cidrBlock:
+                  type: string
+                excludeIps:
+                  type: array
+                  items:
+                    type: string
+                namespaces:
+                  type: array
+                  items:
+                    type: string
+            status:
+              type: object
+              properties:
+                v4availableIPs:
+                  type: number
+                v4usingIPs:
+                  type: number
+                v6availableIPs:
+                  type: number
+                v6usingIPs:
+                  type: number
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: bgp-peers.kubeovn.io
+spec:
+  group: kubeovn.io
+  names:
+    plural: bgp-peers
+    singular: bgp-peer
+    shortNames:
+      - bgppeer
+    kind: BgpPeer
+    listKind: BgpPeerList
+  scope: Cluster
+  versions:
+    - additionalPrinterColumns:
+        - jsonPath: .spec.neighborAddress
+          name: Neighbor
+          type: string
+        - jsonPath: .spec.neighborAs
+          name: AS
+          type: integer
+        - jsonPath: .metadata.creationTimestamp
+          name: Age
+          type: date
+      name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - neighborAddress
+                - neighborAs
+              properties:
+                nodeSelector:
+                  type: object
+                  properties:
+                    matchLabels:
+                      type: object
+                      additionalProperties:
+                        type: string
+                    matchExpressions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          key:
+                            type: string
+                          operator:
+                            type: string
+                          values:
+                            type: array
+                            items:
+                              type: string
+                neighborAddress:
+                  type: string
+                neighborAs:
+                  type: integer
+                  minimum: 1
+                  maximum: 4294967295
+                passwordSecret:
+                  type: object
+                  required:
+                    - namespace
+                    - name
+                    - key
+                  properties:
+                    namespace:
+                      type: string
+                    name:
+                      type: string
+                    key:
+                      type: string
+                holdTime:
+                  type: integer
+                  minimum: 3
+                  maximum: 65535
+                ebgpMultihop:
+                  type: integer
+                  minimum: 1
+                  maximum: 255
+                gracefulRestart:
+                  type: object
+                  properties:
+                    restartTime:
+                      type: integer
+                      minimum: 1
+                      maximum: 4095
+                    deferralTime:
+                      type: integer
+                      minimum: 1
+                      maximum: 64800
+                passiveMode:
+                  type: boolean
+                addressFamilies:
+                  type: array
+                  items:
+                    type: string
+                    enum:
+                      - IPv4
+                      - IPv6
+                      - L2VPN-EVPN
+                vpc:
+                  type: string
+                importPrefixes:
+                  type: array
+                  items:
+                    type: object
+                    required:
+                      - prefix
+                    properties:
+                      prefix:
+                        type: string
+                      ge:
+                        type: integer
+                        minimum: 0
+                        maximum: 128
+                      le:
+                        type: integer
+                        minimum: 0
+                        maximum: 128
+            status:
+              type: object
+              properties:
+                sessions:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      node:
+                        type: string
+                      state:
+                        type: string
+                      receivedPrefixes:
+                        type: integer
+                      advertisedPrefixes:
+                        type: integer
+                      lastUpdateTime:
+                        type: string
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: connectivity-checks.kubeovn.io
+spec:
+  group: kubeovn.io
+  names:
+    plural: connectivity-checks
+    singular: connectivity-check
+    shortNames:
+      - connectivitycheck
+      - cc
+    kind: ConnectivityCheck
+    listKind: ConnectivityCheckList
+  scope: Namespaced
+  versions:
+    - additionalPrinterColumns:
+        - jsonPath: .spec.protocol
+          name: Protocol
+          type: string
+        - jsonPath: .spec.port
+          name: Port
+          type: integer
+        - jsonPath: .metadata.creationTimestamp
+          name: Age
+          type: date
+      name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - source
+                - destination
+                - protocol
+              properties:
+                source:
+                  type: object
+                  properties:
+                    podSelector:
+                      type: object
+                      properties:
+                        matchLabels:
+                          type: object
+                          additionalProperties:
+                            type: string
+                        matchExpressions:
+                          type: array
+                          items:
+                            type: object
+                            properties:
+                              key:
+                                type: string
+                              operator:
+                                type: string
+                              values:
+                                type: array
+                                items:
+                                  type: string
+                    node:
+                      type: string
+                destination:
+                  type: object
+                  properties:
+                    pod:
+                      type: string
+                    service:
+                      type: string
+                    cidr:
+                      type: string
+                    fqdn:
+                      type: string
+                protocol:
+                  type: string
+                  enum:
+                    - ICMP
+                    - TCP
+                    - UDP
+                    - HTTP
+                port:
+                  type: integer
+                  minimum: 1
+                  maximum: 65535
+                path:
+                  type: string
+            status:
+              type: object
+              properties:
+                results:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      source:
+                        type: string
+                      node:
+                        type: string
+                      destination:
+                        type: string
+                      success:
+                        type: boolean
+                      latency:
+                        type: string
+                      hopCount:
+                        type: integer
+                      message:
+                        type: string
+                      observedGeneration:
+                        type: integer
+                      lastUpdateTime:
+                        type: string
 EOF
 
 if $DPDK; then
@@ -1650,6 +2203,12 @@ rules:
       - switch-lb-rules/status
       - vpc-dnses
       - vpc-dnses/status
+      - load-balancer-ip-pools
+      - load-balancer-ip-pools/status
+      - bgp-peers
+      - bgp-peers/status
+      - connectivity-checks
+      - connectivity-checks/status
     verbs:
       - "*"
   - apiGroups:
@@ -1659,6 +2218,7 @@ rules:
       - pods/exec
       - namespaces
       - nodes
+      - nodes/status
       - configmaps
     verbs:
       - create
@@ -1699,6 +2259,14 @@ rules:
       - get
       - list
       - watch
+  - apiGroups:
+      - discovery.k8s.io
+    resources:
+      - endpointslices
+    verbs:
+      - get
+      - list
+      - watch
   - apiGroups:
       - ""
     resources:
@@ -2151,6 +2719,12 @@ rules:
       - vpc-dnses/status
       - switch-lb-rules
       - switch-lb-rules/status
+      - load-balancer-ip-pools
+      - load-balancer-ip-pools/status
+      - bgp-peers
+      - bgp-peers/status
+      - connectivity-checks
+      - connectivity-checks/status
     verbs:
       - "*"
   - apiGroups:
@@ -2160,6 +2734,7 @@ rules:
       - pods/exec
       - namespaces
       - nodes
+      - nodes/status
       - configmaps
     verbs:
       - create
@@ -2190,6 +2765,14 @@ rules:
       - get
       - list
       - watch
+  - apiGroups:
+      - discovery.k8s.io
+    resources:
+      - endpointslices
+    verbs:
+      - get
+      - list
+      - watch
   - apiGroups:
       - ""
     resources:
@@ -2832,6 +3415,7 @@ spec:
           - --log_file=/var/log/kube-ovn/kube-ovn-controller.log
           - --log_file_max_size=0
           - --enable-lb-svc=$ENABLE_LB_SVC
+          - --lb-svc-mode=$LB_SVC_MODE
           - --keep-vm-ip=$ENABLE_KEEP_VM_IP
           env:
             - name: ENABLE_SSL
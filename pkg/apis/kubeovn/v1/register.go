@@ -61,6 +61,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&SwitchLBRuleList{},
 		&VpcDns{},
 		&VpcDnsList{},
+		&LoadBalancerIPPool{},
+		&LoadBalancerIPPoolList{},
+		&BgpPeer{},
+		&BgpPeerList{},
+		&ConnectivityCheck{},
+		&ConnectivityCheckList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
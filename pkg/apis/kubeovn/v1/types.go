@@ -19,8 +19,9 @@ const (
 type SgRemoteType string
 
 const (
-	SgRemoteTypeAddress SgRemoteType = "address"
-	SgRemoteTypeSg      SgRemoteType = "securityGroup"
+	SgRemoteTypeAddress    SgRemoteType = "address"
+	SgRemoteTypeSg         SgRemoteType = "securityGroup"
+	SgRemoteTypeAddressSet SgRemoteType = "addressSet"
 )
 
 type SgProtocol string
@@ -30,6 +31,7 @@ const (
 	ProtocolICMP SgProtocol = "icmp"
 	ProtocolTCP  SgProtocol = "tcp"
 	ProtocolUDP  SgProtocol = "udp"
+	ProtocolSCTP SgProtocol = "sctp"
 )
 
 type SgPolicy string
@@ -392,16 +394,31 @@ type VpcStatus struct {
 	// +patchStrategy=merge
 	Conditions []VpcCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 
-	Standby                bool     `json:"standby"`
-	Default                bool     `json:"default"`
-	DefaultLogicalSwitch   string   `json:"defaultLogicalSwitch"`
-	Router                 string   `json:"router"`
-	TcpLoadBalancer        string   `json:"tcpLoadBalancer"`
-	UdpLoadBalancer        string   `json:"udpLoadBalancer"`
-	TcpSessionLoadBalancer string   `json:"tcpSessionLoadBalancer"`
-	UdpSessionLoadBalancer string   `json:"udpSessionLoadBalancer"`
-	Subnets                []string `json:"subnets"`
-	VpcPeerings            []string `json:"vpcPeerings"`
+	Standby                 bool     `json:"standby"`
+	Default                 bool     `json:"default"`
+	DefaultLogicalSwitch    string   `json:"defaultLogicalSwitch"`
+	Router                  string   `json:"router"`
+	TcpLoadBalancer         string   `json:"tcpLoadBalancer"`
+	UdpLoadBalancer         string   `json:"udpLoadBalancer"`
+	TcpSessionLoadBalancer  string   `json:"tcpSessionLoadBalancer"`
+	UdpSessionLoadBalancer  string   `json:"udpSessionLoadBalancer"`
+	SctpLoadBalancer        string   `json:"sctpLoadBalancer,omitempty"`
+	SctpSessionLoadBalancer string   `json:"sctpSessionLoadBalancer,omitempty"`
+	Subnets                 []string `json:"subnets"`
+	VpcPeerings             []string `json:"vpcPeerings"`
+	// LearnedRoutes are routes learned by the speakers, which are installed
+	// as static routes of the vpc router
+	// +optional
+	LearnedRoutes []*LearnedRoute `json:"learnedRoutes,omitempty"`
+}
+
+type LearnedRoute struct {
+	// Node is the node of the speaker which learned the route
+	Node string `json:"node"`
+	// Source is where the route is learned from, e.g. bgp-peer
+	Source    string `json:"source,omitempty"`
+	CIDR      string `json:"cidr"`
+	NextHopIP string `json:"nextHopIP"`
 }
 
 // Condition describes the state of an object at a certain point.
@@ -744,6 +761,11 @@ type SecurityGroupSpec struct {
 	IngressRules          []*SgRule `json:"ingressRules,omitempty"`
 	EgressRules           []*SgRule `json:"egressRules,omitempty"`
 	AllowSameGroupTraffic bool      `json:"allowSameGroupTraffic,omitempty"`
+
+	// PodSelector and NamespaceSelector bind the ports of the selected pods to the security group,
+	// a nil selector selects everything as long as the other one is set
+	PodSelector       *metav1.LabelSelector `json:"podSelector,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
 type SecurityGroupStatus struct {
@@ -753,6 +775,7 @@ type SecurityGroupStatus struct {
 	EgressMd5              string `json:"egressMd5"`
 	IngressLastSyncSuccess bool   `json:"ingressLastSyncSuccess"`
 	EgressLastSyncSuccess  bool   `json:"egressLastSyncSuccess"`
+	BoundPorts             int    `json:"boundPorts"`
 }
 
 type SgRule struct {
@@ -762,9 +785,16 @@ type SgRule struct {
 	RemoteType          SgRemoteType `json:"remoteType"`
 	RemoteAddress       string       `json:"remoteAddress,omitempty"`
 	RemoteSecurityGroup string       `json:"remoteSecurityGroup,omitempty"`
+	RemoteAddressSet    string       `json:"remoteAddressSet,omitempty"`
 	PortRangeMin        int          `json:"portRangeMin,omitempty"`
 	PortRangeMax        int          `json:"portRangeMax,omitempty"`
-	Policy              SgPolicy     `json:"policy"`
+	// Ports takes precedence over PortRangeMin and PortRangeMax when set
+	Ports    []int    `json:"ports,omitempty"`
+	IcmpType *int     `json:"icmpType,omitempty"`
+	IcmpCode *int     `json:"icmpCode,omitempty"`
+	Policy   SgPolicy `json:"policy"`
+	// Stateless makes allow rules use OVN allow-stateless action which skips conntrack
+	Stateless bool `json:"stateless,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -933,14 +963,55 @@ type SlrPort struct {
 	Port       int32  `json:"port"`
 	TargetPort int32  `json:"targetPort,omitempty"`
 	Protocol   string `json:"protocol"`
+	// TargetProtocol is the protocol of the backends, it must be the same as Protocol
+	// as ovn load balancers do not translate protocols
+	TargetProtocol string `json:"targetProtocol,omitempty"`
+}
+
+// SlrBackend is a static backend of the rule, for example a vm or an external appliance
+type SlrBackend struct {
+	IP string `json:"ip"`
+	// Weight is the relative share of connections the backend receives, defaults to 1
+	Weight int32 `json:"weight,omitempty"`
 }
 
 type SwitchLBRuleSpec struct {
+	// Vip is an ipv4 or ipv6 address, or both separated by comma for dual stack
 	Vip             string    `json:"vip"`
 	Namespace       string    `json:"namespace"`
-	Selector        []string  `json:"selector"`
+	Selector        []string  `json:"selector,omitempty"`
 	SessionAffinity string    `json:"sessionAffinity,omitempty"`
 	Ports           []SlrPort `json:"ports"`
+	// Backends are static backends in addition to the pods matched by Selector
+	Backends []SlrBackend `json:"backends,omitempty"`
+
+	// SessionAffinityTimeout is the client ip affinity timeout in seconds when SessionAffinity is ClientIP,
+	// defaults to 10800
+	SessionAffinityTimeout int32           `json:"sessionAffinityTimeout,omitempty"`
+	HealthCheck            *SlrHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// SlrHealthCheck configures the ovn service monitor of the rule, options left
+// empty fall back to ovn defaults
+type SlrHealthCheck struct {
+	Interval     int `json:"interval,omitempty"`
+	Timeout      int `json:"timeout,omitempty"`
+	SuccessCount int `json:"successCount,omitempty"`
+	FailureCount int `json:"failureCount,omitempty"`
+}
+
+type SlrBackendHealth struct {
+	Address  string `json:"address"`
+	Port     int32  `json:"port"`
+	Protocol string `json:"protocol"`
+	Status   string `json:"status"`
+}
+
+// SlrVipBackends are the backends a vip of the rule is resolved to
+type SlrVipBackends struct {
+	Vip      string   `json:"vip"`
+	Protocol string   `json:"protocol"`
+	Backends []string `json:"backends,omitempty"`
 }
 
 type SwitchLBRuleStatus struct {
@@ -952,6 +1023,9 @@ type SwitchLBRuleStatus struct {
 
 	Ports   string `json:"ports" patchStrategy:"merge"`
 	Service string `json:"service" patchStrategy:"merge"`
+
+	BackendHealth []SlrBackendHealth `json:"backendHealth,omitempty"`
+	Backends      []SlrVipBackends   `json:"backends,omitempty"`
 }
 
 // +genclient
@@ -994,3 +1068,177 @@ type SwitchLBRuleCondition struct {
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+// +resourceName=load-balancer-ip-pools
+type LoadBalancerIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LoadBalancerIPPoolSpec   `json:"spec"`
+	Status LoadBalancerIPPoolStatus `json:"status,omitempty"`
+}
+
+type LoadBalancerIPPoolSpec struct {
+	CIDRBlock  string   `json:"cidrBlock"`
+	ExcludeIps []string `json:"excludeIps,omitempty"`
+	// Namespaces whose LoadBalancer services may allocate ips from the pool, empty means all namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+type LoadBalancerIPPoolStatus struct {
+	V4AvailableIPs float64 `json:"v4availableIPs"`
+	V4UsingIPs     float64 `json:"v4usingIPs"`
+	V6AvailableIPs float64 `json:"v6availableIPs"`
+	V6UsingIPs     float64 `json:"v6usingIPs"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type LoadBalancerIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []LoadBalancerIPPool `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+// +resourceName=bgp-peers
+type BgpPeer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BgpPeerSpec   `json:"spec"`
+	Status BgpPeerStatus `json:"status,omitempty"`
+}
+
+type BgpPeerSpec struct {
+	// NodeSelector selects the nodes whose speakers peer with the neighbor, empty means all nodes
+	NodeSelector    *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	NeighborAddress string                `json:"neighborAddress"`
+	NeighborAs      uint32                `json:"neighborAs"`
+	// PasswordSecret is the secret key holding the md5 password of the session
+	PasswordSecret *BgpPeerSecretRef `json:"passwordSecret,omitempty"`
+	// HoldTime in seconds, the hold time of the speaker is used if not set
+	HoldTime        int32                   `json:"holdTime,omitempty"`
+	EbgpMultihop    int32                   `json:"ebgpMultihop,omitempty"`
+	GracefulRestart *BgpPeerGracefulRestart `json:"gracefulRestart,omitempty"`
+	PassiveMode     bool                    `json:"passiveMode,omitempty"`
+	// AddressFamilies announced to the neighbor, IPv4, IPv6 or L2VPN-EVPN, defaults to the family of the neighbor address
+	AddressFamilies []string `json:"addressFamilies,omitempty"`
+	// Vpc is the custom vpc whose router learns the unicast routes received from the neighbor
+	Vpc string `json:"vpc,omitempty"`
+	// ImportPrefixes is the prefix list of routes learned into the vpc, all routes are learned if empty
+	ImportPrefixes []BgpPrefixListEntry `json:"importPrefixes,omitempty"`
+}
+
+type BgpPrefixListEntry struct {
+	Prefix string `json:"prefix"`
+	// Ge and Le are the range of the prefix length of matched routes,
+	// only routes of the prefix length of the entry match if both are not set
+	Ge int32 `json:"ge,omitempty"`
+	Le int32 `json:"le,omitempty"`
+}
+
+type BgpPeerSecretRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+type BgpPeerGracefulRestart struct {
+	// RestartTime in seconds according to RFC4724 3, maximum 4095
+	RestartTime int32 `json:"restartTime,omitempty"`
+	// DeferralTime in seconds according to RFC4724 4.1, maximum 64800
+	DeferralTime int32 `json:"deferralTime,omitempty"`
+}
+
+type BgpPeerStatus struct {
+	// Sessions are the states of the sessions established by the speakers on selected nodes
+	Sessions []BgpPeerSession `json:"sessions,omitempty"`
+}
+
+type BgpPeerSession struct {
+	Node               string      `json:"node"`
+	State              string      `json:"state"`
+	ReceivedPrefixes   uint64      `json:"receivedPrefixes"`
+	AdvertisedPrefixes uint64      `json:"advertisedPrefixes"`
+	LastUpdateTime     metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BgpPeerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BgpPeer `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resourceName=connectivity-checks
+type ConnectivityCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConnectivityCheckSpec   `json:"spec"`
+	Status ConnectivityCheckStatus `json:"status,omitempty"`
+}
+
+type ConnectivityCheckSpec struct {
+	Source      ConnectivityCheckSource      `json:"source"`
+	Destination ConnectivityCheckDestination `json:"destination"`
+	// Protocol of the check, ICMP, TCP, UDP or HTTP
+	Protocol string `json:"protocol"`
+	// Port is required by TCP, UDP and HTTP checks
+	Port int32 `json:"port,omitempty"`
+	// Path of HTTP checks
+	Path string `json:"path,omitempty"`
+}
+
+// ConnectivityCheckSource selects the pingers running the check, which are the pingers
+// on the nodes of the selected pods in the namespace of the check or on the node
+type ConnectivityCheckSource struct {
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	Node        string                `json:"node,omitempty"`
+}
+
+// ConnectivityCheckDestination is one of a pod or a service in the namespace of the check, a cidr or a fqdn
+type ConnectivityCheckDestination struct {
+	Pod     string `json:"pod,omitempty"`
+	Service string `json:"service,omitempty"`
+	CIDR    string `json:"cidr,omitempty"`
+	FQDN    string `json:"fqdn,omitempty"`
+}
+
+type ConnectivityCheckStatus struct {
+	// Results of the sources by the pingers, which run the check again when the spec changes
+	Results []ConnectivityCheckResult `json:"results,omitempty"`
+}
+
+type ConnectivityCheckResult struct {
+	// Source is the name of the pinger pod the probes are sent from
+	Source      string `json:"source"`
+	Node        string `json:"node"`
+	Destination string `json:"destination"`
+	Success     bool   `json:"success"`
+	// Latency is the average latency of succeeded probes, e.g. 1.2ms
+	Latency string `json:"latency,omitempty"`
+	// HopCount is estimated from the ttl of icmp echo replies
+	HopCount int32  `json:"hopCount,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the check the result is of
+	ObservedGeneration int64       `json:"observedGeneration"`
+	LastUpdateTime     metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ConnectivityCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ConnectivityCheck `json:"items"`
+}
@@ -22,6 +22,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -41,6 +42,352 @@ func (in *Acl) DeepCopy() *Acl {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeer) DeepCopyInto(out *BgpPeer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeer.
+func (in *BgpPeer) DeepCopy() *BgpPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BgpPeer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeerGracefulRestart) DeepCopyInto(out *BgpPeerGracefulRestart) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeerGracefulRestart.
+func (in *BgpPeerGracefulRestart) DeepCopy() *BgpPeerGracefulRestart {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeerGracefulRestart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeerList) DeepCopyInto(out *BgpPeerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BgpPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeerList.
+func (in *BgpPeerList) DeepCopy() *BgpPeerList {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BgpPeerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeerSecretRef) DeepCopyInto(out *BgpPeerSecretRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeerSecretRef.
+func (in *BgpPeerSecretRef) DeepCopy() *BgpPeerSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeerSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeerSession) DeepCopyInto(out *BgpPeerSession) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeerSession.
+func (in *BgpPeerSession) DeepCopy() *BgpPeerSession {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeerSession)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeerSpec) DeepCopyInto(out *BgpPeerSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(BgpPeerSecretRef)
+		**out = **in
+	}
+	if in.GracefulRestart != nil {
+		in, out := &in.GracefulRestart, &out.GracefulRestart
+		*out = new(BgpPeerGracefulRestart)
+		**out = **in
+	}
+	if in.AddressFamilies != nil {
+		in, out := &in.AddressFamilies, &out.AddressFamilies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImportPrefixes != nil {
+		in, out := &in.ImportPrefixes, &out.ImportPrefixes
+		*out = make([]BgpPrefixListEntry, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeerSpec.
+func (in *BgpPeerSpec) DeepCopy() *BgpPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPeerStatus) DeepCopyInto(out *BgpPeerStatus) {
+	*out = *in
+	if in.Sessions != nil {
+		in, out := &in.Sessions, &out.Sessions
+		*out = make([]BgpPeerSession, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPeerStatus.
+func (in *BgpPeerStatus) DeepCopy() *BgpPeerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPeerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BgpPrefixListEntry) DeepCopyInto(out *BgpPrefixListEntry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BgpPrefixListEntry.
+func (in *BgpPrefixListEntry) DeepCopy() *BgpPrefixListEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(BgpPrefixListEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheck) DeepCopyInto(out *ConnectivityCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheck.
+func (in *ConnectivityCheck) DeepCopy() *ConnectivityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConnectivityCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckDestination) DeepCopyInto(out *ConnectivityCheckDestination) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckDestination.
+func (in *ConnectivityCheckDestination) DeepCopy() *ConnectivityCheckDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckList) DeepCopyInto(out *ConnectivityCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ConnectivityCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckList.
+func (in *ConnectivityCheckList) DeepCopy() *ConnectivityCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConnectivityCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckResult) DeepCopyInto(out *ConnectivityCheckResult) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckResult.
+func (in *ConnectivityCheckResult) DeepCopy() *ConnectivityCheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckSource) DeepCopyInto(out *ConnectivityCheckSource) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckSource.
+func (in *ConnectivityCheckSource) DeepCopy() *ConnectivityCheckSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckSpec) DeepCopyInto(out *ConnectivityCheckSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	out.Destination = in.Destination
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckSpec.
+func (in *ConnectivityCheckSpec) DeepCopy() *ConnectivityCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckStatus) DeepCopyInto(out *ConnectivityCheckStatus) {
+	*out = *in
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ConnectivityCheckResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckStatus.
+func (in *ConnectivityCheckStatus) DeepCopy() *ConnectivityCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomInterface) DeepCopyInto(out *CustomInterface) {
 	*out = *in
@@ -701,6 +1048,125 @@ func (in *IptablesSnatRuleStatus) DeepCopy() *IptablesSnatRuleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LearnedRoute) DeepCopyInto(out *LearnedRoute) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LearnedRoute.
+func (in *LearnedRoute) DeepCopy() *LearnedRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(LearnedRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerIPPool) DeepCopyInto(out *LoadBalancerIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerIPPool.
+func (in *LoadBalancerIPPool) DeepCopy() *LoadBalancerIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadBalancerIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerIPPoolList) DeepCopyInto(out *LoadBalancerIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LoadBalancerIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerIPPoolList.
+func (in *LoadBalancerIPPoolList) DeepCopy() *LoadBalancerIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadBalancerIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerIPPoolSpec) DeepCopyInto(out *LoadBalancerIPPoolSpec) {
+	*out = *in
+	if in.ExcludeIps != nil {
+		in, out := &in.ExcludeIps, &out.ExcludeIps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerIPPoolSpec.
+func (in *LoadBalancerIPPoolSpec) DeepCopy() *LoadBalancerIPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerIPPoolStatus) DeepCopyInto(out *LoadBalancerIPPoolStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerIPPoolStatus.
+func (in *LoadBalancerIPPoolStatus) DeepCopy() *LoadBalancerIPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRoute) DeepCopyInto(out *PolicyRoute) {
 	*out = *in
@@ -928,7 +1394,7 @@ func (in *SecurityGroupSpec) DeepCopyInto(out *SecurityGroupSpec) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(SgRule)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -939,10 +1405,20 @@ func (in *SecurityGroupSpec) DeepCopyInto(out *SecurityGroupSpec) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(SgRule)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -975,6 +1451,21 @@ func (in *SecurityGroupStatus) DeepCopy() *SecurityGroupStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SgRule) DeepCopyInto(out *SgRule) {
 	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.IcmpType != nil {
+		in, out := &in.IcmpType, &out.IcmpType
+		*out = new(int)
+		**out = **in
+	}
+	if in.IcmpCode != nil {
+		in, out := &in.IcmpCode, &out.IcmpCode
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -988,6 +1479,54 @@ func (in *SgRule) DeepCopy() *SgRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlrBackend) DeepCopyInto(out *SlrBackend) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlrBackend.
+func (in *SlrBackend) DeepCopy() *SlrBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(SlrBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlrBackendHealth) DeepCopyInto(out *SlrBackendHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlrBackendHealth.
+func (in *SlrBackendHealth) DeepCopy() *SlrBackendHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(SlrBackendHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlrHealthCheck) DeepCopyInto(out *SlrHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlrHealthCheck.
+func (in *SlrHealthCheck) DeepCopy() *SlrHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SlrHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SlrPort) DeepCopyInto(out *SlrPort) {
 	*out = *in
@@ -1004,6 +1543,27 @@ func (in *SlrPort) DeepCopy() *SlrPort {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlrVipBackends) DeepCopyInto(out *SlrVipBackends) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlrVipBackends.
+func (in *SlrVipBackends) DeepCopy() *SlrVipBackends {
+	if in == nil {
+		return nil
+	}
+	out := new(SlrVipBackends)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StaticRoute) DeepCopyInto(out *StaticRoute) {
 	*out = *in
@@ -1255,6 +1815,16 @@ func (in *SwitchLBRuleSpec) DeepCopyInto(out *SwitchLBRuleSpec) {
 		*out = make([]SlrPort, len(*in))
 		copy(*out, *in)
 	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]SlrBackend, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(SlrHealthCheck)
+		**out = **in
+	}
 	return
 }
 
@@ -1278,6 +1848,18 @@ func (in *SwitchLBRuleStatus) DeepCopyInto(out *SwitchLBRuleStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BackendHealth != nil {
+		in, out := &in.BackendHealth, &out.BackendHealth
+		*out = make([]SlrBackendHealth, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]SlrVipBackends, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1926,6 +2508,17 @@ func (in *VpcStatus) DeepCopyInto(out *VpcStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LearnedRoutes != nil {
+		in, out := &in.LearnedRoutes, &out.LearnedRoutes
+		*out = make([]*LearnedRoute, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(LearnedRoute)
+				**out = **in
+			}
+		}
+	}
 	return
 }
 
@@ -0,0 +1,184 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	scheme "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// BgpPeersGetter has a method to return a BgpPeerInterface.
+// A group's client should implement this interface.
+type BgpPeersGetter interface {
+	BgpPeers() BgpPeerInterface
+}
+
+// BgpPeerInterface has methods to work with BgpPeer resources.
+type BgpPeerInterface interface {
+	Create(ctx context.Context, bgpPeer *v1.BgpPeer, opts metav1.CreateOptions) (*v1.BgpPeer, error)
+	Update(ctx context.Context, bgpPeer *v1.BgpPeer, opts metav1.UpdateOptions) (*v1.BgpPeer, error)
+	UpdateStatus(ctx context.Context, bgpPeer *v1.BgpPeer, opts metav1.UpdateOptions) (*v1.BgpPeer, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.BgpPeer, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.BgpPeerList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.BgpPeer, err error)
+	BgpPeerExpansion
+}
+
+// bgpPeers implements BgpPeerInterface
+type bgpPeers struct {
+	client rest.Interface
+}
+
+// newBgpPeers returns a BgpPeers
+func newBgpPeers(c *KubeovnV1Client) *bgpPeers {
+	return &bgpPeers{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the bgpPeer, and returns the corresponding bgpPeer object, and an error if there is any.
+func (c *bgpPeers) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.BgpPeer, err error) {
+	result = &v1.BgpPeer{}
+	err = c.client.Get().
+		Resource("bgp-peers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of BgpPeers that match those selectors.
+func (c *bgpPeers) List(ctx context.Context, opts metav1.ListOptions) (result *v1.BgpPeerList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.BgpPeerList{}
+	err = c.client.Get().
+		Resource("bgp-peers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested bgpPeers.
+func (c *bgpPeers) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("bgp-peers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a bgpPeer and creates it.  Returns the server's representation of the bgpPeer, and an error, if there is any.
+func (c *bgpPeers) Create(ctx context.Context, bgpPeer *v1.BgpPeer, opts metav1.CreateOptions) (result *v1.BgpPeer, err error) {
+	result = &v1.BgpPeer{}
+	err = c.client.Post().
+		Resource("bgp-peers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(bgpPeer).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a bgpPeer and updates it. Returns the server's representation of the bgpPeer, and an error, if there is any.
+func (c *bgpPeers) Update(ctx context.Context, bgpPeer *v1.BgpPeer, opts metav1.UpdateOptions) (result *v1.BgpPeer, err error) {
+	result = &v1.BgpPeer{}
+	err = c.client.Put().
+		Resource("bgp-peers").
+		Name(bgpPeer.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(bgpPeer).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *bgpPeers) UpdateStatus(ctx context.Context, bgpPeer *v1.BgpPeer, opts metav1.UpdateOptions) (result *v1.BgpPeer, err error) {
+	result = &v1.BgpPeer{}
+	err = c.client.Put().
+		Resource("bgp-peers").
+		Name(bgpPeer.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(bgpPeer).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the bgpPeer and deletes it. Returns an error if one occurs.
+func (c *bgpPeers) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("bgp-peers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *bgpPeers) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("bgp-peers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched bgpPeer.
+func (c *bgpPeers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.BgpPeer, err error) {
+	result = &v1.BgpPeer{}
+	err = c.client.Patch(pt).
+		Resource("bgp-peers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
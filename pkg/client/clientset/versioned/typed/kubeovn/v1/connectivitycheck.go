@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	scheme "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ConnectivityChecksGetter has a method to return a ConnectivityCheckInterface.
+// A group's client should implement this interface.
+type ConnectivityChecksGetter interface {
+	ConnectivityChecks(namespace string) ConnectivityCheckInterface
+}
+
+// ConnectivityCheckInterface has methods to work with ConnectivityCheck resources.
+type ConnectivityCheckInterface interface {
+	Create(ctx context.Context, connectivityCheck *v1.ConnectivityCheck, opts metav1.CreateOptions) (*v1.ConnectivityCheck, error)
+	Update(ctx context.Context, connectivityCheck *v1.ConnectivityCheck, opts metav1.UpdateOptions) (*v1.ConnectivityCheck, error)
+	UpdateStatus(ctx context.Context, connectivityCheck *v1.ConnectivityCheck, opts metav1.UpdateOptions) (*v1.ConnectivityCheck, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ConnectivityCheck, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ConnectivityCheckList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ConnectivityCheck, err error)
+	ConnectivityCheckExpansion
+}
+
+// connectivityChecks implements ConnectivityCheckInterface
+type connectivityChecks struct {
+	client rest.Interface
+	ns     string
+}
+
+// newConnectivityChecks returns a ConnectivityChecks
+func newConnectivityChecks(c *KubeovnV1Client, namespace string) *connectivityChecks {
+	return &connectivityChecks{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the connectivityCheck, and returns the corresponding connectivityCheck object, and an error if there is any.
+func (c *connectivityChecks) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ConnectivityCheck, err error) {
+	result = &v1.ConnectivityCheck{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ConnectivityChecks that match those selectors.
+func (c *connectivityChecks) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ConnectivityCheckList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ConnectivityCheckList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested connectivityChecks.
+func (c *connectivityChecks) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a connectivityCheck and creates it.  Returns the server's representation of the connectivityCheck, and an error, if there is any.
+func (c *connectivityChecks) Create(ctx context.Context, connectivityCheck *v1.ConnectivityCheck, opts metav1.CreateOptions) (result *v1.ConnectivityCheck, err error) {
+	result = &v1.ConnectivityCheck{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(connectivityCheck).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a connectivityCheck and updates it. Returns the server's representation of the connectivityCheck, and an error, if there is any.
+func (c *connectivityChecks) Update(ctx context.Context, connectivityCheck *v1.ConnectivityCheck, opts metav1.UpdateOptions) (result *v1.ConnectivityCheck, err error) {
+	result = &v1.ConnectivityCheck{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		Name(connectivityCheck.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(connectivityCheck).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *connectivityChecks) UpdateStatus(ctx context.Context, connectivityCheck *v1.ConnectivityCheck, opts metav1.UpdateOptions) (result *v1.ConnectivityCheck, err error) {
+	result = &v1.ConnectivityCheck{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		Name(connectivityCheck.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(connectivityCheck).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the connectivityCheck and deletes it. Returns an error if one occurs.
+func (c *connectivityChecks) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *connectivityChecks) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched connectivityCheck.
+func (c *connectivityChecks) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ConnectivityCheck, err error) {
+	result = &v1.ConnectivityCheck{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("connectivity-checks").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
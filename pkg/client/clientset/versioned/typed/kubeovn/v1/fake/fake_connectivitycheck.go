@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeConnectivityChecks implements ConnectivityCheckInterface
+type FakeConnectivityChecks struct {
+	Fake *FakeKubeovnV1
+	ns   string
+}
+
+var connectivitychecksResource = schema.GroupVersionResource{Group: "kubeovn.io", Version: "v1", Resource: "connectivity-checks"}
+
+var connectivitychecksKind = schema.GroupVersionKind{Group: "kubeovn.io", Version: "v1", Kind: "ConnectivityCheck"}
+
+// Get takes name of the connectivityCheck, and returns the corresponding connectivityCheck object, and an error if there is any.
+func (c *FakeConnectivityChecks) Get(ctx context.Context, name string, options v1.GetOptions) (result *kubeovnv1.ConnectivityCheck, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(connectivitychecksResource, c.ns, name), &kubeovnv1.ConnectivityCheck{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.ConnectivityCheck), err
+}
+
+// List takes label and field selectors, and returns the list of ConnectivityChecks that match those selectors.
+func (c *FakeConnectivityChecks) List(ctx context.Context, opts v1.ListOptions) (result *kubeovnv1.ConnectivityCheckList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(connectivitychecksResource, connectivitychecksKind, c.ns, opts), &kubeovnv1.ConnectivityCheckList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &kubeovnv1.ConnectivityCheckList{ListMeta: obj.(*kubeovnv1.ConnectivityCheckList).ListMeta}
+	for _, item := range obj.(*kubeovnv1.ConnectivityCheckList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested connectivityChecks.
+func (c *FakeConnectivityChecks) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(connectivitychecksResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a connectivityCheck and creates it.  Returns the server's representation of the connectivityCheck, and an error, if there is any.
+func (c *FakeConnectivityChecks) Create(ctx context.Context, connectivityCheck *kubeovnv1.ConnectivityCheck, opts v1.CreateOptions) (result *kubeovnv1.ConnectivityCheck, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(connectivitychecksResource, c.ns, connectivityCheck), &kubeovnv1.ConnectivityCheck{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.ConnectivityCheck), err
+}
+
+// Update takes the representation of a connectivityCheck and updates it. Returns the server's representation of the connectivityCheck, and an error, if there is any.
+func (c *FakeConnectivityChecks) Update(ctx context.Context, connectivityCheck *kubeovnv1.ConnectivityCheck, opts v1.UpdateOptions) (result *kubeovnv1.ConnectivityCheck, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(connectivitychecksResource, c.ns, connectivityCheck), &kubeovnv1.ConnectivityCheck{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.ConnectivityCheck), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeConnectivityChecks) UpdateStatus(ctx context.Context, connectivityCheck *kubeovnv1.ConnectivityCheck, opts v1.UpdateOptions) (*kubeovnv1.ConnectivityCheck, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(connectivitychecksResource, "status", c.ns, connectivityCheck), &kubeovnv1.ConnectivityCheck{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.ConnectivityCheck), err
+}
+
+// Delete takes name of the connectivityCheck and deletes it. Returns an error if one occurs.
+func (c *FakeConnectivityChecks) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(connectivitychecksResource, c.ns, name, opts), &kubeovnv1.ConnectivityCheck{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeConnectivityChecks) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(connectivitychecksResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &kubeovnv1.ConnectivityCheckList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched connectivityCheck.
+func (c *FakeConnectivityChecks) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *kubeovnv1.ConnectivityCheck, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(connectivitychecksResource, c.ns, name, pt, data, subresources...), &kubeovnv1.ConnectivityCheck{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.ConnectivityCheck), err
+}
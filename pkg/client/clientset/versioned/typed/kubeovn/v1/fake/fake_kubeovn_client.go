@@ -28,6 +28,14 @@ type FakeKubeovnV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeKubeovnV1) BgpPeers() v1.BgpPeerInterface {
+	return &FakeBgpPeers{c}
+}
+
+func (c *FakeKubeovnV1) ConnectivityChecks(namespace string) v1.ConnectivityCheckInterface {
+	return &FakeConnectivityChecks{c, namespace}
+}
+
 func (c *FakeKubeovnV1) HtbQoses() v1.HtbQosInterface {
 	return &FakeHtbQoses{c}
 }
@@ -52,6 +60,10 @@ func (c *FakeKubeovnV1) IptablesSnatRules() v1.IptablesSnatRuleInterface {
 	return &FakeIptablesSnatRules{c}
 }
 
+func (c *FakeKubeovnV1) LoadBalancerIPPools() v1.LoadBalancerIPPoolInterface {
+	return &FakeLoadBalancerIPPools{c}
+}
+
 func (c *FakeKubeovnV1) ProviderNetworks() v1.ProviderNetworkInterface {
 	return &FakeProviderNetworks{c}
 }
@@ -0,0 +1,133 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeLoadBalancerIPPools implements LoadBalancerIPPoolInterface
+type FakeLoadBalancerIPPools struct {
+	Fake *FakeKubeovnV1
+}
+
+var loadbalancerippoolsResource = schema.GroupVersionResource{Group: "kubeovn.io", Version: "v1", Resource: "load-balancer-ip-pools"}
+
+var loadbalancerippoolsKind = schema.GroupVersionKind{Group: "kubeovn.io", Version: "v1", Kind: "LoadBalancerIPPool"}
+
+// Get takes name of the loadBalancerIPPool, and returns the corresponding loadBalancerIPPool object, and an error if there is any.
+func (c *FakeLoadBalancerIPPools) Get(ctx context.Context, name string, options v1.GetOptions) (result *kubeovnv1.LoadBalancerIPPool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(loadbalancerippoolsResource, name), &kubeovnv1.LoadBalancerIPPool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.LoadBalancerIPPool), err
+}
+
+// List takes label and field selectors, and returns the list of LoadBalancerIPPools that match those selectors.
+func (c *FakeLoadBalancerIPPools) List(ctx context.Context, opts v1.ListOptions) (result *kubeovnv1.LoadBalancerIPPoolList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(loadbalancerippoolsResource, loadbalancerippoolsKind, opts), &kubeovnv1.LoadBalancerIPPoolList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &kubeovnv1.LoadBalancerIPPoolList{ListMeta: obj.(*kubeovnv1.LoadBalancerIPPoolList).ListMeta}
+	for _, item := range obj.(*kubeovnv1.LoadBalancerIPPoolList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested loadBalancerIPPools.
+func (c *FakeLoadBalancerIPPools) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(loadbalancerippoolsResource, opts))
+}
+
+// Create takes the representation of a loadBalancerIPPool and creates it.  Returns the server's representation of the loadBalancerIPPool, and an error, if there is any.
+func (c *FakeLoadBalancerIPPools) Create(ctx context.Context, loadBalancerIPPool *kubeovnv1.LoadBalancerIPPool, opts v1.CreateOptions) (result *kubeovnv1.LoadBalancerIPPool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(loadbalancerippoolsResource, loadBalancerIPPool), &kubeovnv1.LoadBalancerIPPool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.LoadBalancerIPPool), err
+}
+
+// Update takes the representation of a loadBalancerIPPool and updates it. Returns the server's representation of the loadBalancerIPPool, and an error, if there is any.
+func (c *FakeLoadBalancerIPPools) Update(ctx context.Context, loadBalancerIPPool *kubeovnv1.LoadBalancerIPPool, opts v1.UpdateOptions) (result *kubeovnv1.LoadBalancerIPPool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(loadbalancerippoolsResource, loadBalancerIPPool), &kubeovnv1.LoadBalancerIPPool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.LoadBalancerIPPool), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeLoadBalancerIPPools) UpdateStatus(ctx context.Context, loadBalancerIPPool *kubeovnv1.LoadBalancerIPPool, opts v1.UpdateOptions) (*kubeovnv1.LoadBalancerIPPool, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(loadbalancerippoolsResource, "status", loadBalancerIPPool), &kubeovnv1.LoadBalancerIPPool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.LoadBalancerIPPool), err
+}
+
+// Delete takes name of the loadBalancerIPPool and deletes it. Returns an error if one occurs.
+func (c *FakeLoadBalancerIPPools) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(loadbalancerippoolsResource, name, opts), &kubeovnv1.LoadBalancerIPPool{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeLoadBalancerIPPools) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(loadbalancerippoolsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &kubeovnv1.LoadBalancerIPPoolList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched loadBalancerIPPool.
+func (c *FakeLoadBalancerIPPools) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *kubeovnv1.LoadBalancerIPPool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(loadbalancerippoolsResource, name, pt, data, subresources...), &kubeovnv1.LoadBalancerIPPool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubeovnv1.LoadBalancerIPPool), err
+}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1
 
+type BgpPeerExpansion interface{}
+
+type ConnectivityCheckExpansion interface{}
+
 type HtbQosExpansion interface{}
 
 type IPExpansion interface{}
@@ -30,6 +34,8 @@ type IptablesFIPRuleExpansion interface{}
 
 type IptablesSnatRuleExpansion interface{}
 
+type LoadBalancerIPPoolExpansion interface{}
+
 type ProviderNetworkExpansion interface{}
 
 type SecurityGroupExpansion interface{}
@@ -28,12 +28,15 @@ import (
 
 type KubeovnV1Interface interface {
 	RESTClient() rest.Interface
+	BgpPeersGetter
+	ConnectivityChecksGetter
 	HtbQosesGetter
 	IPsGetter
 	IptablesDnatRulesGetter
 	IptablesEIPsGetter
 	IptablesFIPRulesGetter
 	IptablesSnatRulesGetter
+	LoadBalancerIPPoolsGetter
 	ProviderNetworksGetter
 	SecurityGroupsGetter
 	SubnetsGetter
@@ -50,6 +53,14 @@ type KubeovnV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KubeovnV1Client) BgpPeers() BgpPeerInterface {
+	return newBgpPeers(c)
+}
+
+func (c *KubeovnV1Client) ConnectivityChecks(namespace string) ConnectivityCheckInterface {
+	return newConnectivityChecks(c, namespace)
+}
+
 func (c *KubeovnV1Client) HtbQoses() HtbQosInterface {
 	return newHtbQoses(c)
 }
@@ -74,6 +85,10 @@ func (c *KubeovnV1Client) IptablesSnatRules() IptablesSnatRuleInterface {
 	return newIptablesSnatRules(c)
 }
 
+func (c *KubeovnV1Client) LoadBalancerIPPools() LoadBalancerIPPoolInterface {
+	return newLoadBalancerIPPools(c)
+}
+
 func (c *KubeovnV1Client) ProviderNetworks() ProviderNetworkInterface {
 	return newProviderNetworks(c)
 }
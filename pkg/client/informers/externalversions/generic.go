@@ -53,6 +53,10 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=kubeovn.io, Version=v1
+	case v1.SchemeGroupVersion.WithResource("bgp-peers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().BgpPeers().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("connectivity-checks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().ConnectivityChecks().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("htbqoses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().HtbQoses().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("ips"):
@@ -65,6 +69,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().IptablesFIPRules().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("iptables-snat-rules"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().IptablesSnatRules().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("load-balancer-ip-pools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().LoadBalancerIPPools().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("provider-networks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().ProviderNetworks().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("security-groups"):
@@ -24,6 +24,10 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// BgpPeers returns a BgpPeerInformer.
+	BgpPeers() BgpPeerInformer
+	// ConnectivityChecks returns a ConnectivityCheckInformer.
+	ConnectivityChecks() ConnectivityCheckInformer
 	// HtbQoses returns a HtbQosInformer.
 	HtbQoses() HtbQosInformer
 	// IPs returns a IPInformer.
@@ -36,6 +40,8 @@ type Interface interface {
 	IptablesFIPRules() IptablesFIPRuleInformer
 	// IptablesSnatRules returns a IptablesSnatRuleInformer.
 	IptablesSnatRules() IptablesSnatRuleInformer
+	// LoadBalancerIPPools returns a LoadBalancerIPPoolInformer.
+	LoadBalancerIPPools() LoadBalancerIPPoolInformer
 	// ProviderNetworks returns a ProviderNetworkInformer.
 	ProviderNetworks() ProviderNetworkInformer
 	// SecurityGroups returns a SecurityGroupInformer.
@@ -67,6 +73,16 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// BgpPeers returns a BgpPeerInformer.
+func (v *version) BgpPeers() BgpPeerInformer {
+	return &bgpPeerInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ConnectivityChecks returns a ConnectivityCheckInformer.
+func (v *version) ConnectivityChecks() ConnectivityCheckInformer {
+	return &connectivityCheckInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // HtbQoses returns a HtbQosInformer.
 func (v *version) HtbQoses() HtbQosInformer {
 	return &htbQosInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -97,6 +113,11 @@ func (v *version) IptablesSnatRules() IptablesSnatRuleInformer {
 	return &iptablesSnatRuleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// LoadBalancerIPPools returns a LoadBalancerIPPoolInformer.
+func (v *version) LoadBalancerIPPools() LoadBalancerIPPoolInformer {
+	return &loadBalancerIPPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ProviderNetworks returns a ProviderNetworkInformer.
 func (v *version) ProviderNetworks() ProviderNetworkInformer {
 	return &providerNetworkInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
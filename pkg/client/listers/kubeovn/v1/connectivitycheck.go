@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ConnectivityCheckLister helps list ConnectivityChecks.
+// All objects returned here must be treated as read-only.
+type ConnectivityCheckLister interface {
+	// List lists all ConnectivityChecks in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ConnectivityCheck, err error)
+	// ConnectivityChecks returns an object that can list and get ConnectivityChecks.
+	ConnectivityChecks(namespace string) ConnectivityCheckNamespaceLister
+	ConnectivityCheckListerExpansion
+}
+
+// connectivityCheckLister implements the ConnectivityCheckLister interface.
+type connectivityCheckLister struct {
+	indexer cache.Indexer
+}
+
+// NewConnectivityCheckLister returns a new ConnectivityCheckLister.
+func NewConnectivityCheckLister(indexer cache.Indexer) ConnectivityCheckLister {
+	return &connectivityCheckLister{indexer: indexer}
+}
+
+// List lists all ConnectivityChecks in the indexer.
+func (s *connectivityCheckLister) List(selector labels.Selector) (ret []*v1.ConnectivityCheck, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ConnectivityCheck))
+	})
+	return ret, err
+}
+
+// ConnectivityChecks returns an object that can list and get ConnectivityChecks.
+func (s *connectivityCheckLister) ConnectivityChecks(namespace string) ConnectivityCheckNamespaceLister {
+	return connectivityCheckNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ConnectivityCheckNamespaceLister helps list and get ConnectivityChecks.
+// All objects returned here must be treated as read-only.
+type ConnectivityCheckNamespaceLister interface {
+	// List lists all ConnectivityChecks in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ConnectivityCheck, err error)
+	// Get retrieves the ConnectivityCheck from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ConnectivityCheck, error)
+	ConnectivityCheckNamespaceListerExpansion
+}
+
+// connectivityCheckNamespaceLister implements the ConnectivityCheckNamespaceLister
+// interface.
+type connectivityCheckNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ConnectivityChecks in the indexer for a given namespace.
+func (s connectivityCheckNamespaceLister) List(selector labels.Selector) (ret []*v1.ConnectivityCheck, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ConnectivityCheck))
+	})
+	return ret, err
+}
+
+// Get retrieves the ConnectivityCheck from the indexer for a given namespace and name.
+func (s connectivityCheckNamespaceLister) Get(name string) (*v1.ConnectivityCheck, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("connectivitycheck"), name)
+	}
+	return obj.(*v1.ConnectivityCheck), nil
+}
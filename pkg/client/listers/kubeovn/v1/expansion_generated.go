@@ -18,6 +18,18 @@ limitations under the License.
 
 package v1
 
+// BgpPeerListerExpansion allows custom methods to be added to
+// BgpPeerLister.
+type BgpPeerListerExpansion interface{}
+
+// ConnectivityCheckListerExpansion allows custom methods to be added to
+// ConnectivityCheckLister.
+type ConnectivityCheckListerExpansion interface{}
+
+// ConnectivityCheckNamespaceListerExpansion allows custom methods to be added to
+// ConnectivityCheckNamespaceLister.
+type ConnectivityCheckNamespaceListerExpansion interface{}
+
 // HtbQosListerExpansion allows custom methods to be added to
 // HtbQosLister.
 type HtbQosListerExpansion interface{}
@@ -42,6 +54,10 @@ type IptablesFIPRuleListerExpansion interface{}
 // IptablesSnatRuleLister.
 type IptablesSnatRuleListerExpansion interface{}
 
+// LoadBalancerIPPoolListerExpansion allows custom methods to be added to
+// LoadBalancerIPPoolLister.
+type LoadBalancerIPPoolListerExpansion interface{}
+
 // ProviderNetworkListerExpansion allows custom methods to be added to
 // ProviderNetworkLister.
 type ProviderNetworkListerExpansion interface{}
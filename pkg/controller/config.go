@@ -52,10 +52,12 @@ type Configuration struct {
 
 	ServiceClusterIPRange string
 
-	ClusterTcpLoadBalancer        string
-	ClusterUdpLoadBalancer        string
-	ClusterTcpSessionLoadBalancer string
-	ClusterUdpSessionLoadBalancer string
+	ClusterTcpLoadBalancer         string
+	ClusterUdpLoadBalancer         string
+	ClusterTcpSessionLoadBalancer  string
+	ClusterUdpSessionLoadBalancer  string
+	ClusterSctpLoadBalancer        string
+	ClusterSctpSessionLoadBalancer string
 
 	PodName      string
 	PodNamespace string
@@ -81,6 +83,7 @@ type Configuration struct {
 	EnableEcmp        bool
 	EnableKeepVmIP    bool
 	EnableLbSvc       bool
+	LbSvcMode         string
 
 	ExternalGatewayConfigNS string
 	ExternalGatewayNet      string
@@ -115,10 +118,12 @@ func ParseFlags() (*Configuration, error) {
 
 		argServiceClusterIPRange = pflag.String("service-cluster-ip-range", "10.96.0.0/12", "The kubernetes service cluster ip range")
 
-		argClusterTcpLoadBalancer        = pflag.String("cluster-tcp-loadbalancer", "cluster-tcp-loadbalancer", "The name for cluster tcp loadbalancer")
-		argClusterUdpLoadBalancer        = pflag.String("cluster-udp-loadbalancer", "cluster-udp-loadbalancer", "The name for cluster udp loadbalancer")
-		argClusterTcpSessionLoadBalancer = pflag.String("cluster-tcp-session-loadbalancer", "cluster-tcp-session-loadbalancer", "The name for cluster tcp session loadbalancer")
-		argClusterUdpSessionLoadBalancer = pflag.String("cluster-udp-session-loadbalancer", "cluster-udp-session-loadbalancer", "The name for cluster udp session loadbalancer")
+		argClusterTcpLoadBalancer         = pflag.String("cluster-tcp-loadbalancer", "cluster-tcp-loadbalancer", "The name for cluster tcp loadbalancer")
+		argClusterUdpLoadBalancer         = pflag.String("cluster-udp-loadbalancer", "cluster-udp-loadbalancer", "The name for cluster udp loadbalancer")
+		argClusterTcpSessionLoadBalancer  = pflag.String("cluster-tcp-session-loadbalancer", "cluster-tcp-session-loadbalancer", "The name for cluster tcp session loadbalancer")
+		argClusterUdpSessionLoadBalancer  = pflag.String("cluster-udp-session-loadbalancer", "cluster-udp-session-loadbalancer", "The name for cluster udp session loadbalancer")
+		argClusterSctpLoadBalancer        = pflag.String("cluster-sctp-loadbalancer", "cluster-sctp-loadbalancer", "The name for cluster sctp loadbalancer")
+		argClusterSctpSessionLoadBalancer = pflag.String("cluster-sctp-session-loadbalancer", "cluster-sctp-session-loadbalancer", "The name for cluster sctp session loadbalancer")
 
 		argWorkerNum       = pflag.Int("worker-num", 3, "The parallelism of each worker")
 		argEnablePprof     = pflag.Bool("enable-pprof", false, "Enable pprof")
@@ -140,6 +145,7 @@ func ParseFlags() (*Configuration, error) {
 		argEnableEcmp              = pflag.Bool("enable-ecmp", false, "Enable ecmp route for centralized subnet")
 		argKeepVmIP                = pflag.Bool("keep-vm-ip", false, "Whether to keep ip for kubevirt pod when pod is rebuild")
 		argEnableLbSvc             = pflag.Bool("enable-lb-svc", false, "Whether to support loadbalancer service")
+		argLbSvcMode               = pflag.String("lb-svc-mode", util.LbSvcModePod, "The mode to implement loadbalancer service, pod or native. Pod mode runs a pod with an attachment network for each service, native mode allocates ips from LoadBalancerIPPools and programs them to ovn load balancers")
 
 		argExternalGatewayConfigNS = pflag.String("external-gateway-config-ns", "kube-system", "The namespace of configmap external-gateway-config, default: kube-system")
 		argExternalGatewayNet      = pflag.String("external-gateway-net", "external", "The name of the external network which mappings with an ovs bridge, default: external")
@@ -168,59 +174,69 @@ func ParseFlags() (*Configuration, error) {
 	pflag.Parse()
 
 	config := &Configuration{
-		OvnNbAddr:                     *argOvnNbAddr,
-		OvnSbAddr:                     *argOvnSbAddr,
-		OvnTimeout:                    *argOvnTimeout,
-		CustCrdRetryMinDelay:          *argCustCrdRetryMinDelay,
-		CustCrdRetryMaxDelay:          *argCustCrdRetryMaxDelay,
-		KubeConfigFile:                *argKubeConfigFile,
-		DefaultLogicalSwitch:          *argDefaultLogicalSwitch,
-		DefaultCIDR:                   *argDefaultCIDR,
-		DefaultGateway:                *argDefaultGateway,
-		DefaultGatewayCheck:           *argDefaultGatewayCheck,
-		DefaultLogicalGateway:         *argDefaultLogicalGateway,
-		DefaultExcludeIps:             *argDefaultExcludeIps,
-		ClusterRouter:                 *argClusterRouter,
-		NodeSwitch:                    *argNodeSwitch,
-		NodeSwitchCIDR:                *argNodeSwitchCIDR,
-		NodeSwitchGateway:             *argNodeSwitchGateway,
-		ServiceClusterIPRange:         *argServiceClusterIPRange,
-		ClusterTcpLoadBalancer:        *argClusterTcpLoadBalancer,
-		ClusterUdpLoadBalancer:        *argClusterUdpLoadBalancer,
-		ClusterTcpSessionLoadBalancer: *argClusterTcpSessionLoadBalancer,
-		ClusterUdpSessionLoadBalancer: *argClusterUdpSessionLoadBalancer,
-		WorkerNum:                     *argWorkerNum,
-		EnablePprof:                   *argEnablePprof,
-		PprofPort:                     *argPprofPort,
-		NetworkType:                   *argNetworkType,
-		DefaultVlanID:                 *argDefaultVlanID,
-		LsDnatModDlDst:                *argLsDnatModDlDst,
-		DefaultProviderName:           *argDefaultProviderName,
-		DefaultHostInterface:          *argDefaultInterfaceName,
-		DefaultExchangeLinkName:       *argDefaultExchangeLinkName,
-		DefaultVlanName:               *argDefaultVlanName,
-		PodName:                       os.Getenv("POD_NAME"),
-		PodNamespace:                  os.Getenv("KUBE_NAMESPACE"),
-		PodNicType:                    *argPodNicType,
-		EnableLb:                      *argEnableLb,
-		EnableNP:                      *argEnableNP,
-		EnableEipSnat:                 *argEnableEipSnat,
-		EnableExternalVpc:             *argEnableExternalVpc,
-		ExternalGatewayConfigNS:       *argExternalGatewayConfigNS,
-		ExternalGatewayNet:            *argExternalGatewayNet,
-		ExternalGatewayVlanID:         *argExternalGatewayVlanID,
-		EnableEcmp:                    *argEnableEcmp,
-		EnableKeepVmIP:                *argKeepVmIP,
-		NodePgProbeTime:               *argNodePgProbeTime,
-		GCInterval:                    *argGCInterval,
-		InspectInterval:               *argInspectInterval,
-		EnableLbSvc:                   *argEnableLbSvc,
+		OvnNbAddr:                      *argOvnNbAddr,
+		OvnSbAddr:                      *argOvnSbAddr,
+		OvnTimeout:                     *argOvnTimeout,
+		CustCrdRetryMinDelay:           *argCustCrdRetryMinDelay,
+		CustCrdRetryMaxDelay:           *argCustCrdRetryMaxDelay,
+		KubeConfigFile:                 *argKubeConfigFile,
+		DefaultLogicalSwitch:           *argDefaultLogicalSwitch,
+		DefaultCIDR:                    *argDefaultCIDR,
+		DefaultGateway:                 *argDefaultGateway,
+		DefaultGatewayCheck:            *argDefaultGatewayCheck,
+		DefaultLogicalGateway:          *argDefaultLogicalGateway,
+		DefaultExcludeIps:              *argDefaultExcludeIps,
+		ClusterRouter:                  *argClusterRouter,
+		NodeSwitch:                     *argNodeSwitch,
+		NodeSwitchCIDR:                 *argNodeSwitchCIDR,
+		NodeSwitchGateway:              *argNodeSwitchGateway,
+		ServiceClusterIPRange:          *argServiceClusterIPRange,
+		ClusterTcpLoadBalancer:         *argClusterTcpLoadBalancer,
+		ClusterUdpLoadBalancer:         *argClusterUdpLoadBalancer,
+		ClusterTcpSessionLoadBalancer:  *argClusterTcpSessionLoadBalancer,
+		ClusterUdpSessionLoadBalancer:  *argClusterUdpSessionLoadBalancer,
+		ClusterSctpLoadBalancer:        *argClusterSctpLoadBalancer,
+		ClusterSctpSessionLoadBalancer: *argClusterSctpSessionLoadBalancer,
+		WorkerNum:                      *argWorkerNum,
+		EnablePprof:                    *argEnablePprof,
+		PprofPort:                      *argPprofPort,
+		NetworkType:                    *argNetworkType,
+		DefaultVlanID:                  *argDefaultVlanID,
+		LsDnatModDlDst:                 *argLsDnatModDlDst,
+		DefaultProviderName:            *argDefaultProviderName,
+		DefaultHostInterface:           *argDefaultInterfaceName,
+		DefaultExchangeLinkName:        *argDefaultExchangeLinkName,
+		DefaultVlanName:                *argDefaultVlanName,
+		PodName:                        os.Getenv("POD_NAME"),
+		PodNamespace:                   os.Getenv("KUBE_NAMESPACE"),
+		PodNicType:                     *argPodNicType,
+		EnableLb:                       *argEnableLb,
+		EnableNP:                       *argEnableNP,
+		EnableEipSnat:                  *argEnableEipSnat,
+		EnableExternalVpc:              *argEnableExternalVpc,
+		ExternalGatewayConfigNS:        *argExternalGatewayConfigNS,
+		ExternalGatewayNet:             *argExternalGatewayNet,
+		ExternalGatewayVlanID:          *argExternalGatewayVlanID,
+		EnableEcmp:                     *argEnableEcmp,
+		EnableKeepVmIP:                 *argKeepVmIP,
+		NodePgProbeTime:                *argNodePgProbeTime,
+		GCInterval:                     *argGCInterval,
+		InspectInterval:                *argInspectInterval,
+		EnableLbSvc:                    *argEnableLbSvc,
+		LbSvcMode:                      *argLbSvcMode,
 	}
 
 	if config.NetworkType == util.NetworkTypeVlan && config.DefaultHostInterface == "" {
 		return nil, fmt.Errorf("no host nic for vlan")
 	}
 
+	if config.LbSvcMode != util.LbSvcModePod && config.LbSvcMode != util.LbSvcModeNative {
+		return nil, fmt.Errorf("invalid lb svc mode %s, must be %s or %s", config.LbSvcMode, util.LbSvcModePod, util.LbSvcModeNative)
+	}
+	if config.EnableLbSvc && config.LbSvcMode == util.LbSvcModeNative && !config.EnableLb {
+		return nil, fmt.Errorf("lb svc mode %s requires load balancer to be enabled", util.LbSvcModeNative)
+	}
+
 	if config.DefaultGateway == "" {
 		gw, err := util.GetGwByCidr(config.DefaultCIDR)
 		if err != nil {
@@ -15,6 +15,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v1 "k8s.io/client-go/listers/core/v1"
+	discoveryv1 "k8s.io/client-go/listers/discovery/v1"
 	netv1 "k8s.io/client-go/listers/networking/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection"
@@ -43,6 +44,7 @@ type Controller struct {
 	ovnLegacyClient *ovs.LegacyClient
 	ovnClient       *ovs.OvnClient
 	ovnPgKeyMutex   *keymutex.KeyMutex
+	northdVersion   *northdVersionCache
 
 	podsLister             v1.PodLister
 	podsSynced             cache.InformerSynced
@@ -81,6 +83,12 @@ type Controller struct {
 	addOrUpdateVpcDnsQueue workqueue.RateLimitingInterface
 	delVpcDnsQueue         workqueue.RateLimitingInterface
 
+	lbIPPoolsLister           kubeovnlister.LoadBalancerIPPoolLister
+	lbIPPoolSynced            cache.InformerSynced
+	addOrUpdateLbIPPoolQueue  workqueue.RateLimitingInterface
+	delLbIPPoolQueue          workqueue.RateLimitingInterface
+	updateLbIPPoolStatusQueue workqueue.RateLimitingInterface
+
 	subnetsLister           kubeovnlister.SubnetLister
 	subnetSynced            cache.InformerSynced
 	addOrUpdateSubnetQueue  workqueue.RateLimitingInterface
@@ -150,14 +158,16 @@ type Controller struct {
 	deleteServiceQueue workqueue.RateLimitingInterface
 	updateServiceQueue workqueue.RateLimitingInterface
 
-	endpointsLister     v1.EndpointsLister
-	endpointsSynced     cache.InformerSynced
-	updateEndpointQueue workqueue.RateLimitingInterface
+	endpointSlicesLister discoveryv1.EndpointSliceLister
+	endpointSlicesSynced cache.InformerSynced
+	updateEndpointQueue  workqueue.RateLimitingInterface
 
-	npsLister     netv1.NetworkPolicyLister
-	npsSynced     cache.InformerSynced
-	updateNpQueue workqueue.RateLimitingInterface
-	deleteNpQueue workqueue.RateLimitingInterface
+	npsLister        netv1.NetworkPolicyLister
+	npsSynced        cache.InformerSynced
+	updateNpQueue    workqueue.RateLimitingInterface
+	updateNpPodQueue workqueue.RateLimitingInterface
+	deleteNpQueue    workqueue.RateLimitingInterface
+	npKeyMutex       *keymutex.KeyMutex
 
 	sgsLister          kubeovnlister.SecurityGroupLister
 	sgSynced           cache.InformerSynced
@@ -218,7 +228,7 @@ func NewController(config *Configuration) *Controller {
 	namespaceInformer := informerFactory.Core().V1().Namespaces()
 	nodeInformer := informerFactory.Core().V1().Nodes()
 	serviceInformer := informerFactory.Core().V1().Services()
-	endpointInformer := informerFactory.Core().V1().Endpoints()
+	endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices()
 	configMapInformer := cmInformerFactory.Core().V1().ConfigMaps()
 
 	controller := &Controller{
@@ -227,6 +237,7 @@ func NewController(config *Configuration) *Controller {
 		podSubnetMap:    &sync.Map{},
 		ovnLegacyClient: ovs.NewLegacyClient(config.OvnNbAddr, config.OvnTimeout, config.OvnSbAddr, config.ClusterRouter, config.ClusterTcpLoadBalancer, config.ClusterUdpLoadBalancer, config.ClusterTcpSessionLoadBalancer, config.ClusterUdpSessionLoadBalancer, config.NodeSwitch, config.NodeSwitchCIDR),
 		ovnPgKeyMutex:   keymutex.New(97),
+		northdVersion:   &northdVersionCache{},
 		ipam:            ovnipam.NewIPAM(),
 
 		vpcsLister:           vpcInformer.Lister(),
@@ -323,9 +334,9 @@ func NewController(config *Configuration) *Controller {
 		deleteServiceQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "DeleteService"),
 		updateServiceQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateService"),
 
-		endpointsLister:     endpointInformer.Lister(),
-		endpointsSynced:     endpointInformer.Informer().HasSynced,
-		updateEndpointQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateEndpoint"),
+		endpointSlicesLister: endpointSliceInformer.Lister(),
+		endpointSlicesSynced: endpointSliceInformer.Informer().HasSynced,
+		updateEndpointQueue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateEndpoint"),
 
 		configMapsLister: configMapInformer.Lister(),
 		configMapsSynced: configMapInformer.Informer().HasSynced,
@@ -373,9 +384,10 @@ func NewController(config *Configuration) *Controller {
 		UpdateFunc: controller.enqueueUpdateService,
 	})
 
-	endpointInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    controller.enqueueAddEndpoint,
-		UpdateFunc: controller.enqueueUpdateEndpoint,
+	endpointSliceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueAddEndpointSlice,
+		UpdateFunc: controller.enqueueUpdateEndpointSlice,
+		DeleteFunc: controller.enqueueDeleteEndpointSlice,
 	})
 
 	vpcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -414,6 +426,20 @@ func NewController(config *Configuration) *Controller {
 			UpdateFunc: controller.enqueueUpdateVpcDns,
 			DeleteFunc: controller.enqueueDeleteVpcDns,
 		})
+
+		if config.EnableLbSvc && config.LbSvcMode == util.LbSvcModeNative {
+			lbIPPoolInformer := kubeovnInformerFactory.Kubeovn().V1().LoadBalancerIPPools()
+			controller.lbIPPoolsLister = lbIPPoolInformer.Lister()
+			controller.lbIPPoolSynced = lbIPPoolInformer.Informer().HasSynced
+			controller.addOrUpdateLbIPPoolQueue = workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "AddOrUpdateLbIPPool")
+			controller.delLbIPPoolQueue = workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "DeleteLbIPPool")
+			controller.updateLbIPPoolStatusQueue = workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateLbIPPoolStatus")
+			lbIPPoolInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc:    controller.enqueueAddLbIPPool,
+				UpdateFunc: controller.enqueueUpdateLbIPPool,
+				DeleteFunc: controller.enqueueDeleteLbIPPool,
+			})
+		}
 	}
 
 	subnetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -443,7 +469,9 @@ func NewController(config *Configuration) *Controller {
 		controller.npsLister = npInformer.Lister()
 		controller.npsSynced = npInformer.Informer().HasSynced
 		controller.updateNpQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateNp")
+		controller.updateNpPodQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateNpPod")
 		controller.deleteNpQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "DeleteNp")
+		controller.npKeyMutex = keymutex.New(97)
 		npInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    controller.enqueueAddNp,
 			UpdateFunc: controller.enqueueUpdateNp,
@@ -511,7 +539,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 		c.ipSynced, c.virtualIpsSynced, c.iptablesEipSynced,
 		c.iptablesFipSynced, c.iptablesDnatRuleSynced, c.iptablesSnatRuleSynced,
 		c.vlanSynced, c.podsSynced, c.namespacesSynced, c.nodesSynced,
-		c.serviceSynced, c.endpointsSynced, c.configMapsSynced,
+		c.serviceSynced, c.endpointSlicesSynced, c.configMapsSynced,
 	}
 	if c.config.EnableNP {
 		cacheSyncs = append(cacheSyncs, c.npsSynced)
@@ -519,6 +547,9 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 
 	if c.config.EnableLb {
 		cacheSyncs = append(cacheSyncs, c.switchLBRuleSynced, c.vpcDnsSynced)
+		if c.nativeLbSvcEnabled() {
+			cacheSyncs = append(cacheSyncs, c.lbIPPoolSynced)
+		}
 	}
 
 	if ok := cache.WaitForCacheSync(stopCh, cacheSyncs...); !ok {
@@ -544,6 +575,12 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 		klog.Fatalf("failed to init ipam: %v", err)
 	}
 
+	if c.nativeLbSvcEnabled() {
+		if err := c.initLbIPPools(); err != nil {
+			klog.Fatalf("failed to init load balancer ip pools: %v", err)
+		}
+	}
+
 	if err := c.initNodeChassis(); err != nil {
 		klog.Errorf("failed to init node chassis: %v", err)
 	}
@@ -641,6 +678,12 @@ func (c *Controller) shutdown() {
 
 		c.addOrUpdateVpcDnsQueue.ShutDown()
 		c.delVpcDnsQueue.ShutDown()
+
+		if c.nativeLbSvcEnabled() {
+			c.addOrUpdateLbIPPoolQueue.ShutDown()
+			c.delLbIPPoolQueue.ShutDown()
+			c.updateLbIPPoolStatusQueue.ShutDown()
+		}
 	}
 
 	c.addVirtualIpQueue.ShutDown()
@@ -666,6 +709,7 @@ func (c *Controller) shutdown() {
 
 	if c.config.EnableNP {
 		c.updateNpQueue.ShutDown()
+		c.updateNpPodQueue.ShutDown()
 		c.deleteNpQueue.ShutDown()
 	}
 	c.addOrUpdateSgQueue.ShutDown()
@@ -751,6 +795,13 @@ func (c *Controller) startWorkers(stopCh <-chan struct{}) {
 		go wait.Until(func() {
 			c.resyncVpcDnsConfig()
 		}, 5*time.Second, stopCh)
+		go wait.Until(c.syncLbHealthCheckStatus, 5*time.Second, stopCh)
+
+		if c.nativeLbSvcEnabled() {
+			go wait.Until(c.runAddOrUpdateLbIPPoolWorker, time.Second, stopCh)
+			go wait.Until(c.runDelLbIPPoolWorker, time.Second, stopCh)
+			go wait.Until(c.runUpdateLbIPPoolStatusWorker, time.Second, stopCh)
+		}
 	}
 
 	for i := 0; i < c.config.WorkerNum; i++ {
@@ -771,6 +822,7 @@ func (c *Controller) startWorkers(stopCh <-chan struct{}) {
 
 		if c.config.EnableNP {
 			go wait.Until(c.runUpdateNpWorker, time.Second, stopCh)
+			go wait.Until(c.runUpdateNpPodWorker, time.Second, stopCh)
 			go wait.Until(c.runDeleteNpWorker, time.Second, stopCh)
 		}
 
@@ -3,9 +3,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -13,44 +15,79 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
-func (c *Controller) enqueueAddEndpoint(obj interface{}) {
+func endpointSliceServiceKey(slice *discoveryv1.EndpointSlice) string {
+	svcName := slice.Labels[discoveryv1.LabelServiceName]
+	if svcName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", slice.Namespace, svcName)
+}
+
+func (c *Controller) enqueueAddEndpointSlice(obj interface{}) {
 	if !c.isLeader() {
 		return
 	}
-	var key string
-	var err error
-	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
-		utilruntime.HandleError(err)
+	slice := obj.(*discoveryv1.EndpointSlice)
+	key := endpointSliceServiceKey(slice)
+	if key == "" {
 		return
 	}
-	klog.V(3).Infof("enqueue add endpoint %s", key)
+	klog.V(3).Infof("enqueue add endpoint slice %s/%s of service %s", slice.Namespace, slice.Name, key)
 	c.updateEndpointQueue.Add(key)
 }
 
-func (c *Controller) enqueueUpdateEndpoint(old, new interface{}) {
+func (c *Controller) enqueueUpdateEndpointSlice(old, new interface{}) {
 	if !c.isLeader() {
 		return
 	}
-	oldEp := old.(*v1.Endpoints)
-	newEp := new.(*v1.Endpoints)
-	if oldEp.ResourceVersion == newEp.ResourceVersion {
+	oldSlice := old.(*discoveryv1.EndpointSlice)
+	newSlice := new.(*discoveryv1.EndpointSlice)
+	if oldSlice.ResourceVersion == newSlice.ResourceVersion {
 		return
 	}
 
-	if len(oldEp.Subsets) == 0 && len(newEp.Subsets) == 0 {
+	if len(oldSlice.Endpoints) == 0 && len(newSlice.Endpoints) == 0 {
 		return
 	}
 
-	var key string
-	var err error
-	if key, err = cache.MetaNamespaceKeyFunc(new); err != nil {
-		utilruntime.HandleError(err)
+	key := endpointSliceServiceKey(newSlice)
+	if key == "" {
+		return
+	}
+	klog.V(3).Infof("enqueue update endpoint slice %s/%s of service %s", newSlice.Namespace, newSlice.Name, key)
+	c.updateEndpointQueue.Add(key)
+}
+
+func (c *Controller) enqueueDeleteEndpointSlice(obj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	var slice *discoveryv1.EndpointSlice
+	switch t := obj.(type) {
+	case *discoveryv1.EndpointSlice:
+		slice = t
+	case cache.DeletedFinalStateUnknown:
+		s, ok := t.Obj.(*discoveryv1.EndpointSlice)
+		if !ok {
+			klog.Warningf("unexpected object type: %T", t.Obj)
+			return
+		}
+		slice = s
+	default:
+		klog.Warningf("unexpected type: %T", obj)
+		return
+	}
+
+	// backends of a service may be split across several slices
+	key := endpointSliceServiceKey(slice)
+	if key == "" || len(slice.Endpoints) == 0 {
 		return
 	}
-	klog.V(3).Infof("enqueue update endpoint %s", key)
+	klog.V(3).Infof("enqueue delete endpoint slice %s/%s of service %s", slice.Namespace, slice.Name, key)
 	c.updateEndpointQueue.Add(key)
 }
 
@@ -98,14 +135,6 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 	}
 	klog.Infof("update endpoint %s/%s", namespace, name)
 
-	ep, err := c.endpointsLister.Endpoints(namespace).Get(name)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil
-		}
-		return err
-	}
-
 	cachedService, err := c.servicesLister.Services(namespace).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -115,9 +144,13 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 	}
 	svc := cachedService.DeepCopy()
 
-	var LbIPs []string
-	if vip, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]; ok {
-		LbIPs = []string{vip}
+	var LbIPs, ingressIPs []string
+	if c.nativeLbSvcEnabled() && isNativeLbSvc(svc) {
+		ingressIPs = lbSvcIngressIPs(svc)
+	}
+	vips, isSlr := slrVips(svc)
+	if isSlr {
+		LbIPs = vips
 	} else {
 		LbIPs = svc.Spec.ClusterIPs
 		if len(LbIPs) == 0 && svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != v1.ClusterIPNone {
@@ -128,6 +161,12 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 		}
 	}
 
+	slices, err := c.endpointSlicesLister.EndpointSlices(namespace).List(labels.Set{discoveryv1.LabelServiceName: name}.AsSelector())
+	if err != nil {
+		klog.Errorf("failed to list endpoint slices of service %s/%s: %v", namespace, name, err)
+		return err
+	}
+
 	pods, err := c.podsLister.Pods(namespace).List(labels.Set(svc.Spec.Selector).AsSelector())
 	if err != nil {
 		klog.Errorf("failed to get pods for service %s in namespace %s: %v", name, namespace, err)
@@ -135,33 +174,28 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 	}
 
 	var vpcName string
+	endpointPods := endpointSlicesPodNames(slices)
 	for _, pod := range pods {
-		if len(pod.Annotations) == 0 {
+		if len(pod.Annotations) == 0 || !endpointPods[pod.Name] {
 			continue
 		}
-
-		for _, subset := range ep.Subsets {
-			for _, addr := range subset.Addresses {
-				if addr.IP == pod.Status.PodIP {
-					if vpcName = pod.Annotations[util.LogicalRouterAnnotation]; vpcName != "" {
-						break
-					}
-				}
-			}
-			if vpcName != "" {
-				break
-			}
-		}
-		if vpcName != "" {
+		if vpcName = pod.Annotations[util.LogicalRouterAnnotation]; vpcName != "" {
 			break
 		}
 	}
 
 	if vpcName == "" {
-		if vpcName = svc.Annotations[util.VpcAnnotation]; vpcName == "" {
-			vpcName = util.DefaultVpc
+		vpcName = svc.Annotations[util.VpcAnnotation]
+	}
+	if vpcName == "" && isSlr {
+		// rules with static backends only belong to the vpc of the subnet the vip is in
+		if vpcName, err = c.getVipVpc(LbIPs[0]); err != nil {
+			return err
 		}
 	}
+	if vpcName == "" {
+		vpcName = util.DefaultVpc
+	}
 
 	vpc, err := c.vpcsLister.Get(vpcName)
 	if err != nil {
@@ -170,6 +204,13 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 	}
 
 	if svcVpc := svc.Annotations[util.VpcAnnotation]; svcVpc != vpcName {
+		if svcVpc != "" {
+			// endpoints of the service moved to another vpc
+			if err = c.deleteServiceVips(svc, append(append([]string{}, LbIPs...), ingressIPs...), svcVpc); err != nil {
+				klog.Errorf("failed to delete vips of service %s/%s from vpc %s: %v", namespace, name, svcVpc, err)
+				return err
+			}
+		}
 		if svc.Annotations == nil {
 			svc.Annotations = make(map[string]string, 1)
 		}
@@ -180,96 +221,344 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 		}
 	}
 
-	tcpLb, udpLb := vpc.Status.TcpLoadBalancer, vpc.Status.UdpLoadBalancer
-	if svc.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
-		tcpLb, udpLb = vpc.Status.TcpSessionLoadBalancer, vpc.Status.UdpSessionLoadBalancer
+	// the cluster load balancers are shared by all nodes and can not select node local endpoints,
+	// without template load balancers services with internal traffic policy set to local are left to
+	// kube-proxy on the client node
+	localOnly := serviceInternalTrafficLocal(svc)
+	if localOnly && !c.ovnLbTemplateSupported() {
+		klog.Infof("service %s/%s has internal traffic policy set to local, skip programming ovn load balancers", namespace, name)
+		c.recorder.Eventf(svc, v1.EventTypeWarning, "InternalTrafficPolicyUnsupported",
+			"internal traffic policy Local requires ovn-northd %d.%d or later, the service is left to kube-proxy", ovnLbTemplateMajorVersion, ovnLbTemplateMinorVersion)
+	}
+
+	hcOptions, hcEnabled := serviceHealthCheckOptions(svc)
+	lbs, err := c.getServiceLoadBalancers(svc, vpc)
+	if err != nil {
+		klog.Errorf("failed to get load balancers of service %s/%s, %v", namespace, name, err)
+		return err
+	}
+	vipLbs := make(map[string]map[v1.Protocol]string, len(LbIPs)+len(ingressIPs))
+	for _, ip := range LbIPs {
+		vipLbs[ip] = lbs
+	}
+	if len(ingressIPs) != 0 {
+		ingressLbs, err := c.getLbSvcLoadBalancers(svc, vpc)
+		if err != nil {
+			klog.Errorf("failed to get ingress load balancers of service %s/%s, %v", namespace, name, err)
+			return err
+		}
+		for _, ip := range ingressIPs {
+			vipLbs[ip] = ingressLbs
+		}
+	}
+
+	// backends depending on the node of the client are expanded per chassis by template load balancers
+	var nodes []*v1.Node
+	var templateLbs []string
+	useTemplateLb := !isSlr && serviceUsesTemplateLb(svc) && c.ovnLbTemplateSupported()
+	if useTemplateLb {
+		if nodes, err = c.nodesLister.List(labels.Everything()); err != nil {
+			klog.Errorf("failed to list nodes, %v", err)
+			return err
+		}
+	}
+	if c.ovnLbTemplateSupported() {
+		if templateLbs, err = c.ovnLegacyClient.ListVpcLoadBalancers(vpcName); err != nil {
+			klog.Errorf("failed to list load balancers of vpc %s, %v", vpcName, err)
+			return err
+		}
 	}
 
-	for _, settingIP := range LbIPs {
+	var slrBackends []kubeovnv1.SlrVipBackends
+	for settingIP, lbs := range vipLbs {
+		// ingress ips are not restricted by internal traffic policy
+		isIngress := util.ContainsString(ingressIPs, settingIP)
 		for _, port := range svc.Spec.Ports {
 			vip := util.JoinHostPort(settingIP, port.Port)
-			backends := getServicePortBackends(ep, pods, port, settingIP)
-			if port.Protocol == v1.ProtocolTCP {
-				// for performance reason delete lb with no backends
-				if len(backends) != 0 {
-					err = c.ovnLegacyClient.CreateLoadBalancerRule(tcpLb, vip, backends, string(port.Protocol))
-					if err != nil {
-						klog.Errorf("failed to update vip %s to tcp lb, %v", vip, err)
-						return err
-					}
-				} else {
-					err = c.ovnLegacyClient.DeleteLoadBalancerVip(vip, tcpLb)
-					if err != nil {
-						klog.Errorf("failed to delete vip %s at tcp lb, %v", vip, err)
+			lb, proto := lbs[port.Protocol], strings.ToLower(string(port.Protocol))
+			if lb == "" {
+				continue
+			}
+			endpoints := getServicePortEndpoints(slices, pods, port, settingIP)
+			backends := joinServiceBackends(endpoints)
+			var nodeBackends map[string]string
+			if !isIngress {
+				if useTemplateLb {
+					nodeBackends = getServicePortNodeBackends(svc, endpoints, nodes)
+				} else if localOnly {
+					backends = ""
+				}
+			}
+			if isSlr {
+				if static := getSlrBackends(svc, port, settingIP); len(static) != 0 {
+					backends = strings.TrimPrefix(backends+","+strings.Join(static, ","), ",")
+				}
+				slrVipBackends := kubeovnv1.SlrVipBackends{Vip: vip, Protocol: string(port.Protocol)}
+				if backends != "" {
+					slrVipBackends.Backends = util.UniqString(strings.Split(backends, ","))
+				}
+				slrBackends = append(slrBackends, slrVipBackends)
+			}
+			if nodeBackends != nil {
+				if err = c.setTemplateLoadBalancerVip(vpc, svc, lb, vip, port.Protocol, nodeBackends); err != nil {
+					klog.Errorf("failed to set vip %s to template lb of %s, %v", vip, lb, err)
+					return err
+				}
+				continue
+			}
+			if util.ContainsString(templateLbs, genTemplateLoadBalancerName(lb, util.CheckProtocol(settingIP))) {
+				if err = c.deleteTemplateLoadBalancerVip(lb, vip); err != nil {
+					klog.Errorf("failed to delete vip %s from template lb of %s, %v", vip, lb, err)
+					return err
+				}
+			}
+			// for performance reason delete lb with no backends
+			if len(backends) != 0 {
+				err = c.ovnLegacyClient.CreateLoadBalancerRule(lb, vip, backends, string(port.Protocol))
+				if err != nil {
+					klog.Errorf("failed to update vip %s to %s lb, %v", vip, proto, err)
+					return err
+				}
+				// ovn service monitor only probes tcp and udp backends
+				if hcEnabled && port.Protocol != v1.ProtocolSCTP {
+					if err = c.setServiceHealthCheck(lb, vip, key, hcOptions, pods, backends); err != nil {
+						klog.Errorf("failed to set health check of vip %s in %s lb, %v", vip, proto, err)
 						return err
 					}
 				}
 			} else {
-				if len(backends) != 0 {
-					err = c.ovnLegacyClient.CreateLoadBalancerRule(udpLb, vip, backends, string(port.Protocol))
-					if err != nil {
-						klog.Errorf("failed to update vip %s to udp lb, %v", vip, err)
-						return err
-					}
-				} else {
-					err = c.ovnLegacyClient.DeleteLoadBalancerVip(vip, udpLb)
-					if err != nil {
-						klog.Errorf("failed to delete vip %s at udp lb, %v", vip, err)
-						return err
-					}
+				err = c.ovnLegacyClient.DeleteLoadBalancerVip(vip, lb)
+				if err != nil {
+					klog.Errorf("failed to delete vip %s at %s lb, %v", vip, proto, err)
+					return err
 				}
 			}
 		}
 	}
 
+	if isSlr && strings.HasPrefix(name, "slr-") {
+		sort.Slice(slrBackends, func(i, j int) bool {
+			if slrBackends[i].Vip != slrBackends[j].Vip {
+				return slrBackends[i].Vip < slrBackends[j].Vip
+			}
+			return slrBackends[i].Protocol < slrBackends[j].Protocol
+		})
+		c.updateSlrBackends(strings.TrimPrefix(name, "slr-"), key, slrBackends)
+	}
 	return nil
 }
 
-func getServicePortBackends(endpoints *v1.Endpoints, pods []*v1.Pod, servicePort v1.ServicePort, serviceIP string) string {
-	backends := []string{}
-	protocol := util.CheckProtocol(serviceIP)
-	for _, subset := range endpoints.Subsets {
-		var targetPort int32
-		for _, port := range subset.Ports {
-			if port.Name == servicePort.Name {
-				targetPort = port.Port
-				break
+// getVipVpc returns the vpc of the subnet the vip belongs to
+func (c *Controller) getVipVpc(vip string) (string, error) {
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets: %v", err)
+		return "", err
+	}
+	for _, subnet := range subnets {
+		if subnet.Spec.Vpc != "" && util.CIDRContainIP(subnet.Spec.CIDRBlock, vip) {
+			return subnet.Spec.Vpc, nil
+		}
+	}
+	return "", nil
+}
+
+func endpointSlicesPodNames(slices []*discoveryv1.EndpointSlice) map[string]bool {
+	names := make(map[string]bool)
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				names[endpoint.TargetRef.Name] = true
 			}
 		}
+	}
+	return names
+}
+
+func endpointSlicePort(slice *discoveryv1.EndpointSlice, servicePort v1.ServicePort) int32 {
+	for _, port := range slice.Ports {
+		if port.Port == nil {
+			continue
+		}
+		if port.Name == nil && servicePort.Name == "" || port.Name != nil && *port.Name == servicePort.Name {
+			return *port.Port
+		}
+	}
+	return 0
+}
+
+// getEndpointAddress returns the address of the endpoint in the same ip family with the service ip,
+// falling back to the ips of the backing pod if the slice belongs to the other family
+func getEndpointAddress(endpoint discoveryv1.Endpoint, pods []*v1.Pod, protocol string) string {
+	for _, address := range endpoint.Addresses {
+		if util.CheckProtocol(address) == protocol {
+			return address
+		}
+	}
+	if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" {
+		return ""
+	}
+
+	for _, pod := range pods {
+		if pod.Name != endpoint.TargetRef.Name {
+			continue
+		}
+		podIPs := pod.Status.PodIPs
+		if len(podIPs) == 0 && pod.Status.PodIP != "" {
+			podIPs = []v1.PodIP{{IP: pod.Status.PodIP}}
+		}
+		for _, podIP := range podIPs {
+			if util.CheckProtocol(podIP.IP) == protocol {
+				return podIP.IP
+			}
+		}
+		break
+	}
+	return ""
+}
+
+// serviceBackend is a backend of a service port and the topology of its endpoint
+type serviceBackend struct {
+	address  string
+	nodeName string
+	zones    []string
+}
+
+// getServicePortEndpoints merges the backends of a service port from all endpoint slices of the service.
+// Ready endpoints are preferred, serving but terminating endpoints are only used when no endpoint is ready,
+// so that connections can still be drained during rolling updates.
+func getServicePortEndpoints(slices []*discoveryv1.EndpointSlice, pods []*v1.Pod, servicePort v1.ServicePort, serviceIP string) []serviceBackend {
+	protocol := util.CheckProtocol(serviceIP)
+	var ready, terminating []serviceBackend
+	seen := make(map[string]bool)
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		targetPort := endpointSlicePort(slice, servicePort)
 		if targetPort == 0 {
 			continue
 		}
 
-		for _, address := range subset.Addresses {
-			if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
-				backends = append(backends, util.JoinHostPort(address.IP, targetPort))
+		for _, endpoint := range slice.Endpoints {
+			isReady := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			isServing := isReady
+			if endpoint.Conditions.Serving != nil {
+				isServing = *endpoint.Conditions.Serving
+			}
+			isTerminating := endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating
+			if !isReady && !(isServing && isTerminating) {
 				continue
 			}
 
-			var ip string
-			for _, pod := range pods {
-				if pod.Name == address.TargetRef.Name {
-					podIPs := pod.Status.PodIPs
-					if len(podIPs) == 0 && pod.Status.PodIP != "" {
-						podIPs = []v1.PodIP{{IP: pod.Status.PodIP}}
-					}
-					for _, podIP := range podIPs {
-						if util.CheckProtocol(podIP.IP) == protocol {
-							ip = podIP.IP
-							break
-						}
-					}
-					break
-				}
-			}
+			ip := getEndpointAddress(endpoint, pods, protocol)
 			if ip == "" {
-				ip = address.IP
+				continue
+			}
+			address := util.JoinHostPort(ip, targetPort)
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+
+			backend := serviceBackend{address: address}
+			if endpoint.NodeName != nil {
+				backend.nodeName = *endpoint.NodeName
 			}
-			if ip != "" {
-				backends = append(backends, util.JoinHostPort(ip, targetPort))
+			if endpoint.Hints != nil {
+				for _, zone := range endpoint.Hints.ForZones {
+					backend.zones = append(backend.zones, zone.Name)
+				}
+			}
+			if isReady {
+				ready = append(ready, backend)
+			} else {
+				terminating = append(terminating, backend)
 			}
 		}
 	}
 
-	return strings.Join(backends, ",")
+	if len(ready) == 0 {
+		ready = terminating
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].address < ready[j].address })
+	return ready
+}
+
+func joinServiceBackends(backends []serviceBackend) string {
+	addresses := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		addresses = append(addresses, backend.address)
+	}
+	return strings.Join(addresses, ",")
+}
+
+// serviceTopologyHintsEnabled returns whether topology aware hints are enabled for the service, as kube-proxy does
+func serviceTopologyHintsEnabled(svc *v1.Service) bool {
+	value := svc.Annotations[v1.AnnotationTopologyAwareHints]
+	return value == "auto" || value == "Auto"
+}
+
+// filterBackendsByZone returns the backends hinted for the zone. Like kube-proxy, hints are ignored
+// if any backend has no hint or no backend is hinted for the zone.
+func filterBackendsByZone(backends []serviceBackend, zone string) []serviceBackend {
+	if zone == "" {
+		return backends
+	}
+	var result []serviceBackend
+	for _, backend := range backends {
+		if len(backend.zones) == 0 {
+			return backends
+		}
+		if util.ContainsString(backend.zones, zone) {
+			result = append(result, backend)
+		}
+	}
+	if len(result) == 0 {
+		return backends
+	}
+	return result
+}
+
+func serviceInternalTrafficLocal(svc *v1.Service) bool {
+	return svc.Spec.InternalTrafficPolicy != nil && *svc.Spec.InternalTrafficPolicy == v1.ServiceInternalTrafficPolicyLocal
+}
+
+// filterBackendsByNode returns the backends on the node
+func filterBackendsByNode(backends []serviceBackend, nodeName string) []serviceBackend {
+	var result []serviceBackend
+	for _, backend := range backends {
+		if backend.nodeName == nodeName {
+			result = append(result, backend)
+		}
+	}
+	return result
+}
+
+// getServicePortNodeBackends returns the backends of a service port by node when the backends
+// depend on the node of the client, nil is returned if all nodes share the same backends.
+// Nodes without local backends of services with internal traffic policy set to local get no backends.
+func getServicePortNodeBackends(svc *v1.Service, backends []serviceBackend, nodes []*v1.Node) map[string]string {
+	if serviceInternalTrafficLocal(svc) {
+		result := make(map[string]string, len(nodes))
+		for _, node := range nodes {
+			result[node.Name] = joinServiceBackends(filterBackendsByNode(backends, node.Name))
+		}
+		return result
+	}
+
+	if !serviceTopologyHintsEnabled(svc) || len(backends) == 0 {
+		return nil
+	}
+	for _, backend := range backends {
+		if len(backend.zones) == 0 {
+			return nil
+		}
+	}
+
+	result := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		result[node.Name] = joinServiceBackends(filterBackendsByZone(backends, node.Labels[v1.LabelTopologyZone]))
+	}
+	return result
 }
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestEndpoint(address, node string, ready, serving, terminating *bool, zones ...string) discoveryv1.Endpoint {
+	endpoint := discoveryv1.Endpoint{
+		Addresses:  []string{address},
+		Conditions: discoveryv1.EndpointConditions{Ready: ready, Serving: serving, Terminating: terminating},
+	}
+	if node != "" {
+		endpoint.NodeName = &node
+	}
+	if len(zones) != 0 {
+		endpoint.Hints = &discoveryv1.EndpointHints{}
+		for _, zone := range zones {
+			endpoint.Hints.ForZones = append(endpoint.Hints.ForZones, discoveryv1.ForZone{Name: zone})
+		}
+	}
+	return endpoint
+}
+
+func newTestSlice(addressType discoveryv1.AddressType, port int32, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		AddressType: addressType,
+		Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+		Endpoints:   endpoints,
+	}
+}
+
+func TestGetServicePortEndpoints(t *testing.T) {
+	yes, no := true, false
+	servicePort := corev1.ServicePort{Port: 80}
+
+	tests := []struct {
+		name      string
+		slices    []*discoveryv1.EndpointSlice
+		pods      []*corev1.Pod
+		serviceIP string
+		want      string
+	}{
+		{
+			name: "merge slices and remove duplicates",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, newTestEndpoint("10.16.0.6", "", nil, nil, nil), newTestEndpoint("10.16.0.5", "", &yes, nil, nil)),
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, newTestEndpoint("10.16.0.5", "", &yes, nil, nil), newTestEndpoint("10.16.0.7", "", &yes, nil, nil)),
+			},
+			serviceIP: "10.96.0.10",
+			want:      "10.16.0.5:8080,10.16.0.6:8080,10.16.0.7:8080",
+		},
+		{
+			name: "not ready endpoints are skipped",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, newTestEndpoint("10.16.0.5", "", &yes, nil, nil), newTestEndpoint("10.16.0.6", "", &no, &no, nil)),
+			},
+			serviceIP: "10.96.0.10",
+			want:      "10.16.0.5:8080",
+		},
+		{
+			name: "terminating endpoints are ignored when any endpoint is ready",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, newTestEndpoint("10.16.0.5", "", &yes, nil, nil), newTestEndpoint("10.16.0.6", "", &no, &yes, &yes)),
+			},
+			serviceIP: "10.96.0.10",
+			want:      "10.16.0.5:8080",
+		},
+		{
+			name: "serving terminating endpoints are used when no endpoint is ready",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, newTestEndpoint("10.16.0.5", "", &no, &yes, &yes), newTestEndpoint("10.16.0.6", "", &no, &no, &yes)),
+			},
+			serviceIP: "10.96.0.10",
+			want:      "10.16.0.5:8080",
+		},
+		{
+			name: "slices of the other family and fqdn slices are skipped",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, newTestEndpoint("10.16.0.5", "", &yes, nil, nil)),
+				newTestSlice(discoveryv1.AddressTypeIPv6, 8080, newTestEndpoint("fd00:10:16::5", "", &yes, nil, nil)),
+				newTestSlice(discoveryv1.AddressTypeFQDN, 8080, newTestEndpoint("example.com", "", &yes, nil, nil)),
+			},
+			serviceIP: "fd00:10:96::10",
+			want:      "[fd00:10:16::5]:8080",
+		},
+		{
+			name: "address of the service family is taken from the pod",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestSlice(discoveryv1.AddressTypeIPv4, 8080, discoveryv1.Endpoint{
+					Addresses: []string{"10.16.0.5"},
+					TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-0"},
+				}),
+			},
+			pods: []*corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-0"},
+				Status:     corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: "10.16.0.5"}, {IP: "fd00:10:16::5"}}},
+			}},
+			serviceIP: "fd00:10:96::10",
+			want:      "[fd00:10:16::5]:8080",
+		},
+		{
+			name:      "no slices",
+			serviceIP: "10.96.0.10",
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinServiceBackends(getServicePortEndpoints(tt.slices, tt.pods, servicePort, tt.serviceIP)); got != tt.want {
+				t.Errorf("getServicePortEndpoints() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetServicePortEndpointsTopology(t *testing.T) {
+	yes := true
+	slices := []*discoveryv1.EndpointSlice{
+		newTestSlice(discoveryv1.AddressTypeIPv4, 8080,
+			newTestEndpoint("10.16.0.6", "node2", &yes, nil, nil, "zone-b"),
+			newTestEndpoint("10.16.0.5", "node1", &yes, nil, nil, "zone-a", "zone-b"),
+			newTestEndpoint("10.16.0.7", "", &yes, nil, nil)),
+	}
+	want := []serviceBackend{
+		{address: "10.16.0.5:8080", nodeName: "node1", zones: []string{"zone-a", "zone-b"}},
+		{address: "10.16.0.6:8080", nodeName: "node2", zones: []string{"zone-b"}},
+		{address: "10.16.0.7:8080"},
+	}
+	if got := getServicePortEndpoints(slices, nil, corev1.ServicePort{Port: 80}, "10.96.0.10"); !reflect.DeepEqual(got, want) {
+		t.Errorf("getServicePortEndpoints() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetServicePortNodeBackends(t *testing.T) {
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{corev1.LabelTopologyZone: "zone-b"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{corev1.LabelTopologyZone: "zone-c"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node4"}},
+	}
+	hinted := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{corev1.AnnotationTopologyAwareHints: "auto"}}}
+	local := corev1.ServiceInternalTrafficPolicyLocal
+	localOnly := &corev1.Service{Spec: corev1.ServiceSpec{InternalTrafficPolicy: &local}}
+
+	tests := []struct {
+		name     string
+		svc      *corev1.Service
+		backends []serviceBackend
+		want     map[string]string
+	}{
+		{
+			name:     "hints disabled",
+			svc:      &corev1.Service{},
+			backends: []serviceBackend{{address: "10.16.0.5:80", zones: []string{"zone-a"}}},
+			want:     nil,
+		},
+		{
+			name: "backend without hint",
+			svc:  hinted,
+			backends: []serviceBackend{
+				{address: "10.16.0.5:80", zones: []string{"zone-a"}},
+				{address: "10.16.0.6:80"},
+			},
+			want: nil,
+		},
+		{
+			name: "backends by zone",
+			svc:  hinted,
+			backends: []serviceBackend{
+				{address: "10.16.0.5:80", zones: []string{"zone-a"}},
+				{address: "10.16.0.6:80", zones: []string{"zone-b"}},
+				{address: "10.16.0.7:80", zones: []string{"zone-a", "zone-b"}},
+			},
+			want: map[string]string{
+				"node1": "10.16.0.5:80,10.16.0.7:80",
+				"node2": "10.16.0.6:80,10.16.0.7:80",
+				"node3": "10.16.0.5:80,10.16.0.6:80,10.16.0.7:80",
+				"node4": "10.16.0.5:80,10.16.0.6:80,10.16.0.7:80",
+			},
+		},
+		{
+			name: "internal traffic policy local",
+			svc:  localOnly,
+			backends: []serviceBackend{
+				{address: "10.16.0.5:80", nodeName: "node1"},
+				{address: "10.16.0.6:80", nodeName: "node2"},
+				{address: "10.16.0.7:80", nodeName: "node1"},
+				{address: "10.16.0.8:80"},
+			},
+			want: map[string]string{
+				"node1": "10.16.0.5:80,10.16.0.7:80",
+				"node2": "10.16.0.6:80",
+				"node3": "",
+				"node4": "",
+			},
+		},
+		{
+			name:     "internal traffic policy local without backends",
+			svc:      localOnly,
+			backends: nil,
+			want:     map[string]string{"node1": "", "node2": "", "node3": "", "node4": ""},
+		},
+		{
+			name: "internal traffic policy local ignores hints",
+			svc: &corev1.Service{
+				ObjectMeta: hinted.ObjectMeta,
+				Spec:       localOnly.Spec,
+			},
+			backends: []serviceBackend{
+				{address: "10.16.0.5:80", nodeName: "node1", zones: []string{"zone-b"}},
+				{address: "10.16.0.6:80", nodeName: "node2", zones: []string{"zone-a"}},
+			},
+			want: map[string]string{
+				"node1": "10.16.0.5:80",
+				"node2": "10.16.0.6:80",
+				"node3": "",
+				"node4": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getServicePortNodeBackends(tt.svc, tt.backends, nodes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getServicePortNodeBackends() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -98,6 +98,9 @@ func (c *Controller) removeExternalGateway() error {
 		klog.Errorf("failed to delete external gateway switch, %v", err)
 		return err
 	}
+	if c.config.EnableLb {
+		return c.syncRouterLoadBalancerGroup(util.DefaultVpc, c.config.ClusterRouter)
+	}
 	return nil
 }
 
@@ -160,6 +163,9 @@ func (c *Controller) establishExternalGateway(config map[string]string) error {
 		klog.Errorf("failed to create external gateway switch, %v", err)
 		return err
 	}
-
+	// the cluster router gets a distributed gateway port, on which its load balancers are applied
+	if c.config.EnableLb {
+		return c.syncRouterLoadBalancerGroup(util.DefaultVpc, c.config.ClusterRouter)
+	}
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
@@ -36,6 +37,7 @@ func (c *Controller) gc() error {
 		c.gcVip,
 		c.gcLbSvcPods,
 		c.gcVpcDns,
+		c.gcVpcLearnedRoutes,
 	}
 	for _, gcFunc := range gcFunctions {
 		if err := gcFunc(); err != nil {
@@ -402,12 +404,17 @@ func (c *Controller) gcLoadBalancer() error {
 				if err != nil {
 					return err
 				}
+				if err = c.ovnLegacyClient.RemoveLoadBalancerGroupFromLogicalSwitch(c.GenVpcLoadBalancer(vpc.Name).LoadBalancerGroup, subnetName); err != nil {
+					return err
+				}
 			}
 
 			vpc.Status.TcpLoadBalancer = ""
 			vpc.Status.TcpSessionLoadBalancer = ""
 			vpc.Status.UdpLoadBalancer = ""
 			vpc.Status.UdpSessionLoadBalancer = ""
+			vpc.Status.SctpLoadBalancer = ""
+			vpc.Status.SctpSessionLoadBalancer = ""
 			bytes, err := vpc.Status.Bytes()
 			if err != nil {
 				return err
@@ -428,6 +435,15 @@ func (c *Controller) gcLoadBalancer() error {
 			klog.Errorf("failed to delete load balancer, %v", err)
 			return err
 		}
+		lbgs, err := c.ovnLegacyClient.ListLoadBalancerGroups()
+		if err != nil {
+			return err
+		}
+		for _, lbg := range lbgs {
+			if err = c.ovnLegacyClient.DeleteLoadBalancerGroup(lbg); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 
@@ -436,27 +452,39 @@ func (c *Controller) gcLoadBalancer() error {
 		klog.Errorf("failed to list svc, %v", err)
 		return err
 	}
-	tcpVips := []string{}
-	udpVips := []string{}
-	tcpSessionVips := []string{}
-	udpSessionVips := []string{}
+	// vips expected in each load balancer, vips left in the load balancers of a vpc
+	// the service no longer belongs to are removed
+	lbVips := map[string][]string{}
+	affinityTimeoutSupported := c.ovnLbAffinityTimeoutSupported()
 	for _, svc := range svcs {
-		ip := svc.Spec.ClusterIP
-		if v, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]; ok {
-			ip = v
+		ips := svc.Spec.ClusterIPs
+		if len(ips) == 0 {
+			ips = []string{svc.Spec.ClusterIP}
+		}
+		if vips, ok := slrVips(svc); ok {
+			ips = vips
 		}
+		vpcName := svc.Annotations[util.VpcAnnotation]
+		if vpcName == "" {
+			vpcName = util.DefaultVpc
+		}
+		vpcLb := c.GenVpcLoadBalancer(vpcName)
 		for _, port := range svc.Spec.Ports {
-			if port.Protocol == corev1.ProtocolTCP {
-				if svc.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
-					tcpSessionVips = append(tcpSessionVips, fmt.Sprintf("%s:%d", ip, port.Port))
-				} else {
-					tcpVips = append(tcpVips, fmt.Sprintf("%s:%d", ip, port.Port))
+			lb := serviceLoadBalancer(vpcLb, svc, port.Protocol, affinityTimeoutSupported)
+			for _, ip := range ips {
+				lbVips[lb] = append(lbVips[lb], util.JoinHostPort(ip, port.Port))
+				if serviceUsesTemplateLb(svc) {
+					templateLb := genTemplateLoadBalancerName(lb, util.CheckProtocol(ip))
+					lbVips[templateLb] = append(lbVips[templateLb], util.JoinHostPort(ip, port.Port))
 				}
-			} else {
-				if svc.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
-					udpSessionVips = append(udpSessionVips, fmt.Sprintf("%s:%d", ip, port.Port))
-				} else {
-					udpVips = append(udpVips, fmt.Sprintf("%s:%d", ip, port.Port))
+			}
+		}
+		if c.nativeLbSvcEnabled() && isNativeLbSvc(svc) {
+			vpcLb = c.genLbSvcVpcLoadBalancer(vpcName)
+			for _, port := range svc.Spec.Ports {
+				lb := serviceLoadBalancer(vpcLb, svc, port.Protocol, affinityTimeoutSupported)
+				for _, ip := range lbSvcIngressIPs(svc) {
+					lbVips[lb] = append(lbVips[lb], util.JoinHostPort(ip, port.Port))
 				}
 			}
 		}
@@ -467,93 +495,56 @@ func (c *Controller) gcLoadBalancer() error {
 		klog.Errorf("failed to list vpc, %v", err)
 		return err
 	}
-	var vpcLbs []string
+	var vpcLbs, vpcLbgs []string
+	templateVars := make(map[string]bool)
 	for _, vpc := range vpcs {
-		tcpLb, udpLb := vpc.Status.TcpLoadBalancer, vpc.Status.UdpLoadBalancer
-		tcpSessLb, udpSessLb := vpc.Status.TcpSessionLoadBalancer, vpc.Status.UdpSessionLoadBalancer
-		vpcLbs = append(vpcLbs, tcpLb, udpLb, tcpSessLb, udpSessLb)
-
-		if tcpLb != "" {
-			lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(tcpLb)
-			if err != nil {
-				klog.Errorf("failed to get lb %v", err)
-			}
-			vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
-			if err != nil {
-				klog.Errorf("failed to get tcp lb vips %v", err)
-				return err
-			}
-			for vip := range vips {
-				if !util.IsStringIn(vip, tcpVips) {
-					err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, tcpLb)
-					if err != nil {
-						klog.Errorf("failed to delete vip %s from tcp lb %s, %v", vip, tcpLb, err)
-						return err
-					}
-				}
-			}
+		vpcLbgs = append(vpcLbgs, c.GenVpcLoadBalancer(vpc.Name).LoadBalancerGroup)
+		lbs, err := c.listVpcLoadBalancers(vpc.Name)
+		if err != nil {
+			return err
 		}
-
-		if tcpSessLb != "" {
-			lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(tcpSessLb)
-			if err != nil {
-				klog.Errorf("failed to get lb %v", err)
-			}
-			vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
-			if err != nil {
-				klog.Errorf("failed to get tcp session lb vips %v", err)
-				return err
-			}
-			for vip := range vips {
-				if !util.IsStringIn(vip, tcpSessionVips) {
-					err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, tcpSessLb)
-					if err != nil {
-						klog.Errorf("failed to delete vip %s from tcp session lb %s, %v", vip, tcpSessLb, err)
-						return err
+		baseLbs := []*VpcLoadBalancer{c.GenVpcLoadBalancer(vpc.Name)}
+		if c.nativeLbSvcEnabled() {
+			baseLbs = append(baseLbs, c.genLbSvcVpcLoadBalancer(vpc.Name))
+		}
+		for _, protocol := range lbProtocols {
+			for _, lb := range lbs[protocol] {
+				// session load balancers with dedicated affinity timeout are destroyed once no service uses them
+				isBase := false
+				for _, baseLb := range baseLbs {
+					if l, sl := baseLb.protocolLoadBalancers(protocol); lb == l || lb == sl {
+						isBase = true
+						break
 					}
 				}
-			}
-		}
-
-		if udpLb != "" {
-			lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(udpLb)
-			if err != nil {
-				klog.Errorf("failed to get lb %v", err)
-				return err
-			}
-			vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
-			if err != nil {
-				klog.Errorf("failed to get udp lb vips %v", err)
-				return err
-			}
-			for vip := range vips {
-				if !util.IsStringIn(vip, udpVips) {
-					err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, udpLb)
-					if err != nil {
-						klog.Errorf("failed to delete vip %s from tcp lb %s, %v", vip, udpLb, err)
-						return err
-					}
+				if _, ok := lbVips[lb]; !ok && !isBase {
+					continue
 				}
-			}
-		}
+				vpcLbs = append(vpcLbs, lb)
 
-		if udpSessLb != "" {
-			lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(udpSessLb)
-			if err != nil {
-				klog.Errorf("failed to get lb %v", err)
-				return err
-			}
-			vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
-			if err != nil {
-				klog.Errorf("failed to get udp session lb vips %v", err)
-				return err
-			}
-			for vip := range vips {
-				if !util.IsStringIn(vip, udpSessionVips) {
-					err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, udpSessLb)
-					if err != nil {
-						klog.Errorf("failed to delete vip %s from udp session lb %s, %v", vip, udpSessLb, err)
-						return err
+				lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(lb)
+				if err != nil {
+					klog.Errorf("failed to get lb %v", err)
+					return err
+				}
+				if lbUuid == "" {
+					continue
+				}
+				vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
+				if err != nil {
+					klog.Errorf("failed to get lb %s vips %v", lb, err)
+					return err
+				}
+				for vip, backends := range vips {
+					if !util.IsStringIn(vip, lbVips[lb]) {
+						if err = c.ovnLegacyClient.DeleteLoadBalancerVip(vip, lb); err != nil {
+							klog.Errorf("failed to delete vip %s from lb %s, %v", vip, lb, err)
+							return err
+						}
+						continue
+					}
+					if strings.HasPrefix(backends, "^") {
+						templateVars[strings.TrimPrefix(backends, "^")] = true
 					}
 				}
 			}
@@ -577,6 +568,27 @@ func (c *Controller) gcLoadBalancer() error {
 			return err
 		}
 	}
+
+	if c.ovnLbTemplateSupported() {
+		if err = c.gcChassisTemplateVars(templateVars); err != nil {
+			klog.Errorf("failed to gc chassis template vars, %v", err)
+			return err
+		}
+	}
+
+	lbgs, err := c.ovnLegacyClient.ListLoadBalancerGroups()
+	if err != nil {
+		return err
+	}
+	for _, lbg := range lbgs {
+		if util.ContainsString(vpcLbgs, lbg) {
+			continue
+		}
+		klog.Infof("start to destroy load balancer group %s", lbg)
+		if err := c.ovnLegacyClient.DeleteLoadBalancerGroup(lbg); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -842,3 +854,42 @@ func (c *Controller) gcVpcDns() error {
 	}
 	return nil
 }
+
+// gcVpcLearnedRoutes removes the learned routes of nodes which are deleted or no longer run a speaker from vpc status
+func (c *Controller) gcVpcLearnedRoutes() error {
+	klog.Infof("start to gc vpc learned routes")
+	vpcs, err := c.vpcsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list vpc, %v", err)
+		return err
+	}
+	speakerNodes, err := c.speakerNodes()
+	if err != nil {
+		return err
+	}
+
+	for _, cachedVpc := range vpcs {
+		if len(activeLearnedRoutes(cachedVpc.Status.LearnedRoutes, speakerNodes)) == len(cachedVpc.Status.LearnedRoutes) {
+			continue
+		}
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			vpc, err := c.config.KubeOvnClient.KubeovnV1().Vpcs().Get(context.Background(), cachedVpc.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			routes := activeLearnedRoutes(vpc.Status.LearnedRoutes, speakerNodes)
+			if len(routes) == len(vpc.Status.LearnedRoutes) {
+				return nil
+			}
+			klog.Infof("gc %d learned routes of vpc %s", len(vpc.Status.LearnedRoutes)-len(routes), vpc.Name)
+			vpc.Status.LearnedRoutes = routes
+			_, err = c.config.KubeOvnClient.KubeovnV1().Vpcs().UpdateStatus(context.Background(), vpc, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to gc learned routes of vpc %s, %v", cachedVpc.Name, err)
+			return err
+		}
+	}
+	return nil
+}
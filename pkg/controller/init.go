@@ -80,6 +80,8 @@ func (c *Controller) InitDefaultVpc() error {
 		vpc.Status.TcpSessionLoadBalancer = c.config.ClusterTcpSessionLoadBalancer
 		vpc.Status.UdpLoadBalancer = c.config.ClusterUdpLoadBalancer
 		vpc.Status.UdpSessionLoadBalancer = c.config.ClusterUdpSessionLoadBalancer
+		vpc.Status.SctpLoadBalancer = c.config.ClusterSctpLoadBalancer
+		vpc.Status.SctpSessionLoadBalancer = c.config.ClusterSctpSessionLoadBalancer
 	}
 	vpc.Status.Standby = true
 	vpc.Status.Default = true
@@ -217,70 +219,48 @@ func (c *Controller) initLoadBalancer() error {
 		vpc := cachedVpc.DeepCopy()
 		vpcLb := c.GenVpcLoadBalancer(vpc.Name)
 
-		tcpLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLb.TcpLoadBalancer)
-		if err != nil {
-			return fmt.Errorf("failed to find tcp lb: %v", err)
-		}
-		if tcpLb == "" {
-			klog.Infof("init cluster tcp load balancer %s", vpcLb.TcpLoadBalancer)
-			err := c.ovnLegacyClient.CreateLoadBalancer(vpcLb.TcpLoadBalancer, util.ProtocolTCP, "")
-			if err != nil {
-				klog.Errorf("failed to create cluster tcp load balancer: %v", err)
-				return err
-			}
-		} else {
-			klog.Infof("tcp load balancer %s exists", tcpLb)
+		if err = c.createVpcLoadBalancers(vpcLb); err != nil {
+			return err
 		}
 
-		tcpSessionLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLb.TcpSessLoadBalancer)
-		if err != nil {
-			return fmt.Errorf("failed to find tcp session lb: %v", err)
-		}
-		if tcpSessionLb == "" {
-			klog.Infof("init cluster tcp session load balancer %s", vpcLb.TcpSessLoadBalancer)
-			err := c.ovnLegacyClient.CreateLoadBalancer(vpcLb.TcpSessLoadBalancer, util.ProtocolTCP, "ip_src")
-			if err != nil {
-				klog.Errorf("failed to create cluster tcp session load balancer: %v", err)
+		if c.nativeLbSvcEnabled() {
+			if err = c.initLbSvcLoadBalancers(vpc.Name); err != nil {
+				klog.Errorf("failed to init load balancers for load balancer services: %v", err)
 				return err
 			}
-		} else {
-			klog.Infof("tcp session load balancer %s exists", vpcLb.TcpSessLoadBalancer)
 		}
 
-		udpLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLb.UdpLoadBalancer)
+		lbs := vpcLb.loadBalancers()
+		affinityLbs, err := c.ovnLegacyClient.ListVpcLoadBalancers(vpc.Name)
 		if err != nil {
-			return fmt.Errorf("failed to find udp lb: %v", err)
+			return err
 		}
-		if udpLb == "" {
-			klog.Infof("init cluster udp load balancer %s", vpcLb.UdpLoadBalancer)
-			err := c.ovnLegacyClient.CreateLoadBalancer(vpcLb.UdpLoadBalancer, util.ProtocolUDP, "")
-			if err != nil {
-				klog.Errorf("failed to create cluster udp load balancer: %v", err)
-				return err
-			}
-		} else {
-			klog.Infof("udp load balancer %s exists", udpLb)
+		lbs = append(lbs, affinityLbs...)
+		if err = c.ovnLegacyClient.AddLoadBalancersToGroup(vpcLb.LoadBalancerGroup, lbs...); err != nil {
+			klog.Errorf("failed to init load balancer group %s: %v", vpcLb.LoadBalancerGroup, err)
+			return err
 		}
-
-		udpSessionLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLb.UdpSessLoadBalancer)
-		if err != nil {
-			return fmt.Errorf("failed to find udp session lb: %v", err)
+		// logical switches created by previous versions reference the load balancers one by one
+		if err = c.ovnLegacyClient.MigrateLoadBalancersToGroup(vpcLb.LoadBalancerGroup, lbs...); err != nil {
+			klog.Errorf("failed to migrate load balancers to group %s: %v", vpcLb.LoadBalancerGroup, err)
+			return err
 		}
-		if udpSessionLb == "" {
-			klog.Infof("init cluster udp session load balancer %s", vpcLb.UdpSessLoadBalancer)
-			err := c.ovnLegacyClient.CreateLoadBalancer(vpcLb.UdpSessLoadBalancer, util.ProtocolUDP, "ip_src")
-			if err != nil {
-				klog.Errorf("failed to create cluster udp session load balancer: %v", err)
-				return err
-			}
-		} else {
-			klog.Infof("udp session load balancer %s exists", vpcLb.UdpSessLoadBalancer)
+		router := vpc.Status.Router
+		if vpc.Name == util.DefaultVpc {
+			router = c.config.ClusterRouter
+		} else if router == "" {
+			router = vpc.Name
+		}
+		if err = c.syncRouterLoadBalancerGroup(vpc.Name, router); err != nil {
+			return err
 		}
 
 		vpc.Status.TcpLoadBalancer = vpcLb.TcpLoadBalancer
 		vpc.Status.TcpSessionLoadBalancer = vpcLb.TcpSessLoadBalancer
 		vpc.Status.UdpLoadBalancer = vpcLb.UdpLoadBalancer
 		vpc.Status.UdpSessionLoadBalancer = vpcLb.UdpSessLoadBalancer
+		vpc.Status.SctpLoadBalancer = vpcLb.SctpLoadBalancer
+		vpc.Status.SctpSessionLoadBalancer = vpcLb.SctpSessLoadBalancer
 		bytes, err := vpc.Status.Bytes()
 		if err != nil {
 			return err
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// serviceAffinityTimeout returns the client ip affinity timeout of the service in seconds
+func serviceAffinityTimeout(svc *v1.Service) int32 {
+	if svc.Spec.SessionAffinityConfig != nil && svc.Spec.SessionAffinityConfig.ClientIP != nil &&
+		svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds != nil && *svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds > 0 {
+		return *svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds
+	}
+	return v1.DefaultClientIPServiceAffinitySeconds
+}
+
+// lbProtocols are the protocols served by the load balancers of a vpc
+var lbProtocols = []v1.Protocol{v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP}
+
+func genAffinityLoadBalancerName(sessionLb string, timeout int32) string {
+	return fmt.Sprintf("%s-%d", sessionLb, timeout)
+}
+
+// serviceLoadBalancer returns the load balancer of the vpc the vips of the service with the protocol belong to.
+// Services with the default affinity timeout share the session load balancers of the vpc,
+// others use dedicated session load balancers per timeout if ovn-northd supports affinity timeout.
+func serviceLoadBalancer(vpcLb *VpcLoadBalancer, svc *v1.Service, protocol v1.Protocol, affinityTimeoutSupported bool) string {
+	lb, sessionLb := vpcLb.protocolLoadBalancers(protocol)
+	if svc.Spec.SessionAffinity != v1.ServiceAffinityClientIP {
+		return lb
+	}
+	if timeout := serviceAffinityTimeout(svc); affinityTimeoutSupported && timeout != v1.DefaultClientIPServiceAffinitySeconds {
+		return genAffinityLoadBalancerName(sessionLb, timeout)
+	}
+	return sessionLb
+}
+
+// getServiceLoadBalancers returns the load balancers the vips of the service belong to by protocol,
+// dedicated session load balancers for the protocols used by the service are created on demand.
+func (c *Controller) getServiceLoadBalancers(svc *v1.Service, vpc *kubeovnv1.Vpc) (map[v1.Protocol]string, error) {
+	return c.getVpcServiceLoadBalancers(svc, vpc, c.GenVpcLoadBalancer(vpc.Name))
+}
+
+// getVpcServiceLoadBalancers returns the load balancers in vpcLb the vips of the service belong to by protocol,
+// dedicated session load balancers are created on demand.
+func (c *Controller) getVpcServiceLoadBalancers(svc *v1.Service, vpc *kubeovnv1.Vpc, vpcLb *VpcLoadBalancer) (map[v1.Protocol]string, error) {
+	supported := c.ovnLbAffinityTimeoutSupported()
+	lbs := make(map[v1.Protocol]string, len(lbProtocols))
+	for _, protocol := range lbProtocols {
+		lbs[protocol] = serviceLoadBalancer(vpcLb, svc, protocol, supported)
+	}
+	if svc.Spec.SessionAffinity != v1.ServiceAffinityClientIP || serviceAffinityTimeout(svc) == v1.DefaultClientIPServiceAffinitySeconds {
+		return lbs, nil
+	}
+	if !supported {
+		klog.Warningf("ovn-northd %q does not support affinity timeout, ignore the timeout of service %s/%s", c.getNorthdVersion(), svc.Namespace, svc.Name)
+		c.recorder.Eventf(svc, v1.EventTypeWarning, "SessionAffinityTimeoutUnsupported",
+			"ClientIP session affinity timeout %d is ignored as it requires ovn-northd %d.%d or later",
+			serviceAffinityTimeout(svc), ovnLbTemplateMajorVersion, ovnLbTemplateMinorVersion)
+		return lbs, nil
+	}
+
+	affinityLbs, err := c.ovnLegacyClient.ListVpcLoadBalancers(vpc.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range svc.Spec.Ports {
+		lb := lbs[port.Protocol]
+		if lb == "" || util.IsStringIn(lb, affinityLbs) {
+			continue
+		}
+		if err = c.createAffinityLoadBalancer(vpc, lb, strings.ToLower(string(port.Protocol)), serviceAffinityTimeout(svc)); err != nil {
+			return nil, err
+		}
+		affinityLbs = append(affinityLbs, lb)
+	}
+	return lbs, nil
+}
+
+// createAffinityLoadBalancer creates a session load balancer with the given affinity timeout and adds it
+// to the load balancer group of the vpc. The load balancer is marked with the vpc only after it is added,
+// so a partially configured one will be completed on retry.
+func (c *Controller) createAffinityLoadBalancer(vpc *kubeovnv1.Vpc, lb, protocol string, timeout int32) error {
+	lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(lb)
+	if err != nil {
+		klog.Errorf("failed to find lb %s, %v", lb, err)
+		return err
+	}
+	if lbUuid == "" {
+		klog.Infof("create session lb %s with affinity timeout %d", lb, timeout)
+		if err = c.ovnLegacyClient.CreateLoadBalancer(lb, protocol, "ip_src"); err != nil {
+			klog.Errorf("failed to create lb %s, %v", lb, err)
+			return err
+		}
+	}
+	if err = c.ovnLegacyClient.SetLoadBalancerAffinityTimeout(lb, timeout); err != nil {
+		klog.Errorf("failed to set affinity timeout of lb %s, %v", lb, err)
+		return err
+	}
+	lbg := c.GenVpcLoadBalancer(vpc.Name).LoadBalancerGroup
+	if err = c.ovnLegacyClient.AddLoadBalancersToGroup(lbg, lb); err != nil {
+		klog.Errorf("failed to add lb %s to group %s, %v", lb, lbg, err)
+		return err
+	}
+	if err = c.ovnLegacyClient.SetLoadBalancerVpc(lb, vpc.Name); err != nil {
+		klog.Errorf("failed to set vpc of lb %s, %v", lb, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServiceLoadBalancer(t *testing.T) {
+	vpcLb := &VpcLoadBalancer{
+		TcpLoadBalancer:     "cluster-tcp-loadbalancer",
+		TcpSessLoadBalancer: "cluster-tcp-session-loadbalancer",
+	}
+	timeout := int32(600)
+	defaultTimeout := corev1.DefaultClientIPServiceAffinitySeconds
+
+	tests := []struct {
+		name      string
+		spec      corev1.ServiceSpec
+		supported bool
+		want      string
+	}{
+		{
+			name:      "no session affinity",
+			supported: true,
+			want:      "cluster-tcp-loadbalancer",
+		},
+		{
+			name:      "default affinity timeout",
+			spec:      corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP},
+			supported: true,
+			want:      "cluster-tcp-session-loadbalancer",
+		},
+		{
+			name: "explicit default affinity timeout",
+			spec: corev1.ServiceSpec{
+				SessionAffinity:       corev1.ServiceAffinityClientIP,
+				SessionAffinityConfig: &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &defaultTimeout}},
+			},
+			supported: true,
+			want:      "cluster-tcp-session-loadbalancer",
+		},
+		{
+			name: "dedicated affinity timeout",
+			spec: corev1.ServiceSpec{
+				SessionAffinity:       corev1.ServiceAffinityClientIP,
+				SessionAffinityConfig: &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout}},
+			},
+			supported: true,
+			want:      "cluster-tcp-session-loadbalancer-600",
+		},
+		{
+			name: "affinity timeout not supported by ovn",
+			spec: corev1.ServiceSpec{
+				SessionAffinity:       corev1.ServiceAffinityClientIP,
+				SessionAffinityConfig: &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout}},
+			},
+			supported: false,
+			want:      "cluster-tcp-session-loadbalancer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{Spec: tt.spec}
+			if got := serviceLoadBalancer(vpcLb, svc, corev1.ProtocolTCP, tt.supported); got != tt.want {
+				t.Errorf("serviceLoadBalancer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
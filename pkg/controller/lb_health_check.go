@@ -0,0 +1,349 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+var healthCheckOptionAnnotations = map[string]string{
+	"interval":      util.ServiceHealthCheckIntervalAnnotation,
+	"timeout":       util.ServiceHealthCheckTimeoutAnnotation,
+	"success_count": util.ServiceHealthCheckSuccessCountAnnotation,
+	"failure_count": util.ServiceHealthCheckFailureCountAnnotation,
+}
+
+// health check vips are named with a reserved prefix and labeled, so that a vip created by users is never taken over
+const healthCheckVipPrefix = "kube-ovn-health-check-"
+
+func genHealthCheckVipName(subnet string) string {
+	return healthCheckVipPrefix + subnet
+}
+
+// isHealthCheckVip returns whether the vip is the health check vip created by kube-ovn for the subnet
+func isHealthCheckVip(vip *kubeovnv1.Vip, subnet string) bool {
+	return vip.Name == genHealthCheckVipName(subnet) &&
+		vip.Labels[util.HealthCheckVipLabel] == "true" &&
+		vip.Spec.Subnet == subnet
+}
+
+// serviceHealthCheckOptions returns the ovn health check options of the service and whether health check is enabled
+func serviceHealthCheckOptions(svc *v1.Service) (map[string]string, bool) {
+	if svc.Annotations[util.ServiceHealthCheckAnnotation] != "true" {
+		return nil, false
+	}
+
+	options := make(map[string]string, len(healthCheckOptionAnnotations))
+	for option, annotation := range healthCheckOptionAnnotations {
+		value, ok := svc.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+			klog.Warningf("ignore invalid annotation %s=%s of service %s/%s", annotation, value, svc.Namespace, svc.Name)
+			continue
+		}
+		options[option] = value
+	}
+	return options, true
+}
+
+func serviceHealthCheckChanged(oldSvc, newSvc *v1.Service) bool {
+	if oldSvc.Annotations[util.ServiceHealthCheckAnnotation] != newSvc.Annotations[util.ServiceHealthCheckAnnotation] {
+		return true
+	}
+	for _, annotation := range healthCheckOptionAnnotations {
+		if oldSvc.Annotations[annotation] != newSvc.Annotations[annotation] {
+			return true
+		}
+	}
+	return false
+}
+
+func setSlrHealthCheckAnnotations(annotations map[string]string, hc *kubeovnv1.SlrHealthCheck) {
+	delete(annotations, util.ServiceHealthCheckAnnotation)
+	for _, annotation := range healthCheckOptionAnnotations {
+		delete(annotations, annotation)
+	}
+	if hc == nil {
+		return
+	}
+
+	annotations[util.ServiceHealthCheckAnnotation] = "true"
+	for annotation, value := range map[string]int{
+		util.ServiceHealthCheckIntervalAnnotation:     hc.Interval,
+		util.ServiceHealthCheckTimeoutAnnotation:      hc.Timeout,
+		util.ServiceHealthCheckSuccessCountAnnotation: hc.SuccessCount,
+		util.ServiceHealthCheckFailureCountAnnotation: hc.FailureCount,
+	} {
+		if value > 0 {
+			annotations[annotation] = strconv.Itoa(value)
+		}
+	}
+}
+
+// getHealthCheckVip returns the source ip used by ovn service monitor to probe backends in the subnet.
+// The ip is reserved by a vip so that it will never be allocated to a pod.
+func (c *Controller) getHealthCheckVip(subnet string) (string, error) {
+	name := genHealthCheckVipName(subnet)
+	vip, err := c.virtualIpsLister.Get(name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to get health check vip %s: %v", name, err)
+			return "", err
+		}
+		vip = &kubeovnv1.Vip{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{util.HealthCheckVipLabel: "true"},
+			},
+			Spec: kubeovnv1.VipSpec{Subnet: subnet},
+		}
+		if _, err = c.config.KubeOvnClient.KubeovnV1().Vips().Create(context.Background(), vip, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+			klog.Errorf("failed to create health check vip %s: %v", name, err)
+			return "", err
+		}
+		return "", fmt.Errorf("health check vip %s is not ready", name)
+	}
+	if !isHealthCheckVip(vip, subnet) {
+		err = fmt.Errorf("vip %s is not a health check vip of subnet %s", name, subnet)
+		klog.Error(err)
+		return "", err
+	}
+	if vip.Status.V4ip == "" {
+		return "", fmt.Errorf("health check vip %s is not ready", name)
+	}
+	return vip.Status.V4ip, nil
+}
+
+// setServiceHealthCheck configures the health check of an ipv4 vip, ovn service monitor does not support ipv6 yet
+func (c *Controller) setServiceHealthCheck(lb, vip, svcKey string, options map[string]string, pods []*v1.Pod, backends string) error {
+	if util.CheckProtocol(parseVipAddr(vip)) != kubeovnv1.ProtocolIPv4 {
+		return nil
+	}
+
+	podByIP := make(map[string]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		for _, podIP := range pod.Status.PodIPs {
+			podByIP[podIP.IP] = pod
+		}
+		if pod.Status.PodIP != "" {
+			podByIP[pod.Status.PodIP] = pod
+		}
+	}
+
+	srcIPs := make(map[string]string)
+	mappings := make(map[string]string)
+	for _, backend := range strings.Split(backends, ",") {
+		ip := parseVipAddr(backend)
+		pod := podByIP[ip]
+		if pod == nil {
+			continue
+		}
+		provider, subnet := podIPProvider(pod, ip)
+		if subnet == "" {
+			continue
+		}
+		srcIP, ok := srcIPs[subnet]
+		if !ok {
+			var err error
+			if srcIP, err = c.getHealthCheckVip(subnet); err != nil {
+				return err
+			}
+			srcIPs[subnet] = srcIP
+		}
+		mappings[ip] = fmt.Sprintf("%s:%s", ovs.PodNameToPortName(c.getNameByPod(pod), pod.Namespace, provider), srcIP)
+	}
+
+	if err := c.ovnLegacyClient.SetLoadBalancerHealthCheck(lb, vip, svcKey, options, mappings); err != nil {
+		return err
+	}
+	return c.gcLoadBalancerIpPortMappings(lb)
+}
+
+// podIPProvider returns the provider and the subnet of the pod network which owns the ip
+func podIPProvider(pod *v1.Pod, ip string) (string, string) {
+	suffix := strings.TrimPrefix(util.IpAddressAnnotationTemplate, "%s")
+	providers := make([]string, 0, 1)
+	for key := range pod.Annotations {
+		if strings.HasSuffix(key, suffix) {
+			providers = append(providers, strings.TrimSuffix(key, suffix))
+		}
+	}
+	sort.Strings(providers)
+
+	for _, provider := range providers {
+		for _, podIP := range strings.Split(pod.Annotations[fmt.Sprintf(util.IpAddressAnnotationTemplate, provider)], ",") {
+			if strings.TrimSpace(podIP) == ip {
+				return provider, pod.Annotations[fmt.Sprintf(util.LogicalSwitchAnnotationTemplate, provider)]
+			}
+		}
+	}
+	return "", ""
+}
+
+// staleIpPortMappings returns the backend ips in ip_port_mappings which are no longer used by any vip of the loadbalancer
+func staleIpPortMappings(mappings, vips map[string]string) []string {
+	inUse := make(map[string]bool)
+	for _, backends := range vips {
+		for _, backend := range strings.Split(backends, ",") {
+			if backend = strings.TrimSpace(backend); backend != "" {
+				inUse[parseVipAddr(backend)] = true
+			}
+		}
+	}
+
+	var stale []string
+	for ip := range mappings {
+		if !inUse[ip] {
+			stale = append(stale, ip)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// gcLoadBalancerIpPortMappings removes ip_port_mappings of backends which have left the loadbalancer
+func (c *Controller) gcLoadBalancerIpPortMappings(lb string) error {
+	mappings, err := c.ovnLegacyClient.GetLoadBalancerIpPortMappings(lb)
+	if err != nil {
+		klog.Errorf("failed to get ip_port_mappings of lb %s, %v", lb, err)
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lb)
+	if err != nil {
+		klog.Errorf("failed to get vips of lb %s, %v", lb, err)
+		return err
+	}
+	return c.ovnLegacyClient.RemoveLoadBalancerIpPortMappings(lb, staleIpPortMappings(mappings, vips))
+}
+
+// syncLbHealthCheckStatus exports the status of backends probed by ovn service monitor
+// to metrics and the status of SwitchLBRules
+func (c *Controller) syncLbHealthCheckStatus() {
+	svcs, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services, %v", err)
+		return
+	}
+
+	var monitors map[string]string
+	metricLbBackendHealth.Reset()
+	for _, svc := range svcs {
+		if _, enabled := serviceHealthCheckOptions(svc); !enabled {
+			continue
+		}
+		if monitors == nil {
+			serviceMonitors, err := c.ovnLegacyClient.ListServiceMonitors()
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+			monitors = make(map[string]string, len(serviceMonitors))
+			for _, m := range serviceMonitors {
+				monitors[fmt.Sprintf("%s/%s", util.JoinHostPort(m.IP, m.Port), m.Protocol)] = m.Status
+			}
+		}
+
+		slices, err := c.endpointSlicesLister.EndpointSlices(svc.Namespace).List(labels.Set{discoveryv1.LabelServiceName: svc.Name}.AsSelector())
+		if err != nil {
+			klog.Errorf("failed to list endpoint slices of service %s/%s, %v", svc.Namespace, svc.Name, err)
+			continue
+		}
+
+		var health []kubeovnv1.SlrBackendHealth
+		seen := make(map[string]bool)
+		for _, slice := range slices {
+			if slice.AddressType != discoveryv1.AddressTypeIPv4 {
+				continue
+			}
+			for _, port := range slice.Ports {
+				if port.Port == nil {
+					continue
+				}
+				protocol := "tcp"
+				if port.Protocol != nil {
+					protocol = strings.ToLower(string(*port.Protocol))
+				}
+				if protocol == util.ProtocolSCTP {
+					continue
+				}
+				for _, endpoint := range slice.Endpoints {
+					for _, address := range endpoint.Addresses {
+						key := fmt.Sprintf("%s/%s", util.JoinHostPort(address, *port.Port), protocol)
+						if seen[key] {
+							continue
+						}
+						seen[key] = true
+
+						status := monitors[key]
+						if status == "" {
+							status = "unknown"
+						}
+						health = append(health, kubeovnv1.SlrBackendHealth{
+							Address:  address,
+							Port:     *port.Port,
+							Protocol: protocol,
+							Status:   status,
+						})
+
+						value := 0.0
+						if status == "online" {
+							value = 1
+						}
+						metricLbBackendHealth.WithLabelValues(svc.Namespace, svc.Name, address, strconv.Itoa(int(*port.Port)), protocol).Set(value)
+					}
+				}
+			}
+		}
+		sort.Slice(health, func(i, j int) bool {
+			if health[i].Address != health[j].Address {
+				return health[i].Address < health[j].Address
+			}
+			if health[i].Port != health[j].Port {
+				return health[i].Port < health[j].Port
+			}
+			return health[i].Protocol < health[j].Protocol
+		})
+
+		if _, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]; ok && strings.HasPrefix(svc.Name, "slr-") {
+			c.updateSlrBackendHealth(strings.TrimPrefix(svc.Name, "slr-"), fmt.Sprintf("%s/%s", svc.Namespace, svc.Name), health)
+		}
+	}
+}
+
+func (c *Controller) updateSlrBackendHealth(name, svcKey string, health []kubeovnv1.SlrBackendHealth) {
+	slr, err := c.switchLBRuleLister.Get(name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to get SwitchLBRule %s, %v", name, err)
+		}
+		return
+	}
+	if slr.Status.Service != svcKey || reflect.DeepEqual(slr.Status.BackendHealth, health) {
+		return
+	}
+
+	newSlr := slr.DeepCopy()
+	newSlr.Status.BackendHealth = health
+	if _, err = c.config.KubeOvnClient.KubeovnV1().SwitchLBRules().UpdateStatus(context.Background(), newSlr, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("failed to update backend health of SwitchLBRule %s, %v", name, err)
+	}
+}
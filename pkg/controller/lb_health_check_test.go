@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func TestPodIPProvider(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "vm-0",
+		Namespace: "default",
+		Annotations: map[string]string{
+			"ovn.kubernetes.io/ip_address":                    "10.16.0.5,fd00:10:16::5",
+			"ovn.kubernetes.io/logical_switch":                "ovn-default",
+			"attach.default.ovn.kubernetes.io/ip_address":     "172.17.0.2",
+			"attach.default.ovn.kubernetes.io/logical_switch": "attach-subnet",
+		},
+	}}
+
+	tests := []struct {
+		name         string
+		ip           string
+		wantProvider string
+		wantSubnet   string
+	}{
+		{"default network ipv4", "10.16.0.5", "ovn", "ovn-default"},
+		{"default network ipv6", "fd00:10:16::5", "ovn", "ovn-default"},
+		{"attachment network", "172.17.0.2", "attach.default.ovn", "attach-subnet"},
+		{"unknown ip", "192.168.0.1", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, subnet := podIPProvider(pod, tt.ip)
+			if provider != tt.wantProvider || subnet != tt.wantSubnet {
+				t.Errorf("podIPProvider() = (%q, %q), want (%q, %q)", provider, subnet, tt.wantProvider, tt.wantSubnet)
+			}
+		})
+	}
+}
+
+func TestStaleIpPortMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings map[string]string
+		vips     map[string]string
+		want     []string
+	}{
+		{
+			name:     "no mappings",
+			mappings: map[string]string{},
+			vips:     map[string]string{"10.96.0.10:53": "10.16.0.5:53"},
+			want:     nil,
+		},
+		{
+			name: "all backends in use",
+			mappings: map[string]string{
+				"10.16.0.5": "coredns-0.kube-system:10.16.0.2",
+				"10.16.0.6": "coredns-1.kube-system:10.16.0.2",
+			},
+			vips: map[string]string{
+				"10.96.0.10:53":   "10.16.0.5:53,10.16.0.6:53",
+				"10.96.0.10:9153": "10.16.0.5:9153",
+			},
+			want: nil,
+		},
+		{
+			name: "backend left the vip",
+			mappings: map[string]string{
+				"10.16.0.5": "coredns-0.kube-system:10.16.0.2",
+				"10.16.0.6": "coredns-1.kube-system:10.16.0.2",
+				"10.16.0.7": "coredns-2.kube-system:10.16.0.2",
+			},
+			vips: map[string]string{"10.96.0.10:53": "10.16.0.5:53"},
+			want: []string{"10.16.0.6", "10.16.0.7"},
+		},
+		{
+			name:     "backend used by another vip",
+			mappings: map[string]string{"10.16.0.5": "web-0.default:10.16.0.2"},
+			vips: map[string]string{
+				"10.96.0.10:53": "10.16.0.6:53",
+				"10.96.0.20:80": "10.16.0.5:8080",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := staleIpPortMappings(tt.mappings, tt.vips); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("staleIpPortMappings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHealthCheckVip(t *testing.T) {
+	labels := map[string]string{util.HealthCheckVipLabel: "true"}
+	tests := []struct {
+		name string
+		vip  *kubeovnv1.Vip
+		want bool
+	}{
+		{
+			name: "health check vip",
+			vip: &kubeovnv1.Vip{
+				ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-health-check-ovn-default", Labels: labels},
+				Spec:       kubeovnv1.VipSpec{Subnet: "ovn-default"},
+			},
+			want: true,
+		},
+		{
+			name: "user vip without label",
+			vip: &kubeovnv1.Vip{
+				ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-health-check-ovn-default"},
+				Spec:       kubeovnv1.VipSpec{Subnet: "ovn-default"},
+			},
+		},
+		{
+			name: "vip of another subnet",
+			vip: &kubeovnv1.Vip{
+				ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-health-check-ovn-default", Labels: labels},
+				Spec:       kubeovnv1.VipSpec{Subnet: "subnet1"},
+			},
+		},
+		{
+			name: "legacy name",
+			vip: &kubeovnv1.Vip{
+				ObjectMeta: metav1.ObjectMeta{Name: "ovn-default-health-check", Labels: labels},
+				Spec:       kubeovnv1.VipSpec{Subnet: "ovn-default"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHealthCheckVip(tt.vip, "ovn-default"); got != tt.want {
+				t.Errorf("isHealthCheckVip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// The load balancers of a vpc are shared by pods on all nodes. Vips whose backends depend on the node of
+// the client are moved to template load balancers, whose backends refer to a chassis template variable
+// that is expanded to the backends selected for the node on each chassis.
+
+// serviceUsesTemplateLb returns whether the backends of the service may depend on the node of the client
+func serviceUsesTemplateLb(svc *v1.Service) bool {
+	return serviceTopologyHintsEnabled(svc) || serviceInternalTrafficLocal(svc)
+}
+
+// enqueueTemplateLbServices enqueues services whose backends may depend on the node of the client,
+// so that the template variables of new chassis or nodes moved to another zone are updated
+func (c *Controller) enqueueTemplateLbServices() {
+	svcs, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services, %v", err)
+		return
+	}
+	for _, svc := range svcs {
+		if serviceUsesTemplateLb(svc) {
+			c.updateEndpointQueue.Add(fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
+		}
+	}
+}
+
+func genTemplateLoadBalancerName(lb, protocol string) string {
+	return fmt.Sprintf("%s-template-%s", lb, strings.ToLower(protocol))
+}
+
+var lbTemplateVarNameReplacer = strings.NewReplacer("-", "_", ":", "_", "[", "", "]", "")
+
+// genLbTemplateVarName returns the name of the chassis template variable holding the backends of the vip
+func genLbTemplateVarName(lb, vip string) string {
+	return lbTemplateVarNameReplacer.Replace(fmt.Sprintf("%s_%s", lb, vip))
+}
+
+// getNodeChassis returns the chassis of nodes by node name
+func (c *Controller) getNodeChassis() (map[string]string, error) {
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list nodes, %v", err)
+		return nil, err
+	}
+	chassis := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		if name := node.Annotations[util.ChassisAnnotation]; name != "" {
+			chassis[node.Name] = name
+		}
+	}
+	return chassis, nil
+}
+
+// createTemplateLoadBalancer creates the template load balancer of the ip family for the vpc load balancer lb
+// and adds it to the load balancer group of the vpc
+func (c *Controller) createTemplateLoadBalancer(vpc *kubeovnv1.Vpc, svc *v1.Service, lb string, protocol v1.Protocol, family string) (string, error) {
+	name := genTemplateLoadBalancerName(lb, family)
+	lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(name)
+	if err != nil {
+		klog.Errorf("failed to find lb %s, %v", name, err)
+		return "", err
+	}
+	if lbUuid != "" {
+		return name, nil
+	}
+
+	vpcLb := c.GenVpcLoadBalancer(vpc.Name)
+	var selectFields string
+	if plainLb, _ := vpcLb.protocolLoadBalancers(protocol); lb != plainLb {
+		selectFields = "ip_src"
+	}
+	klog.Infof("create template lb %s", name)
+	if err = c.ovnLegacyClient.CreateTemplateLoadBalancer(name, strings.ToLower(string(protocol)), selectFields, strings.ToLower(family)); err != nil {
+		klog.Errorf("failed to create template lb %s, %v", name, err)
+		return "", err
+	}
+	if selectFields != "" {
+		if err = c.ovnLegacyClient.SetLoadBalancerAffinityTimeout(name, serviceAffinityTimeout(svc)); err != nil {
+			klog.Errorf("failed to set affinity timeout of lb %s, %v", name, err)
+			return "", err
+		}
+	}
+	if err = c.ovnLegacyClient.AddLoadBalancersToGroup(vpcLb.LoadBalancerGroup, name); err != nil {
+		klog.Errorf("failed to add lb %s to group %s, %v", name, vpcLb.LoadBalancerGroup, err)
+		return "", err
+	}
+	if err = c.ovnLegacyClient.SetLoadBalancerVpc(name, vpc.Name); err != nil {
+		klog.Errorf("failed to set vpc of lb %s, %v", name, err)
+		return "", err
+	}
+	return name, nil
+}
+
+// setTemplateLoadBalancerVip moves the vip from the vpc load balancer lb to the template load balancer,
+// with the backends of each node set to the template variable of its chassis
+func (c *Controller) setTemplateLoadBalancerVip(vpc *kubeovnv1.Vpc, svc *v1.Service, lb, vip string, protocol v1.Protocol, nodeBackends map[string]string) error {
+	nodeChassis, err := c.getNodeChassis()
+	if err != nil {
+		return err
+	}
+
+	family := util.CheckProtocol(parseVipAddr(vip))
+	templateLb, err := c.createTemplateLoadBalancer(vpc, svc, lb, protocol, family)
+	if err != nil {
+		return err
+	}
+
+	varName := genLbTemplateVarName(templateLb, vip)
+	for node, chassis := range nodeChassis {
+		if err = c.ovnLegacyClient.SetChassisTemplateVars(chassis, map[string]string{varName: nodeBackends[node]}); err != nil {
+			klog.Errorf("failed to set backends of vip %s for node %s, %v", vip, node, err)
+			return err
+		}
+	}
+	if err = c.ovnLegacyClient.SetLoadBalancerTemplateVip(templateLb, vip, "^"+varName); err != nil {
+		klog.Errorf("failed to set vip %s to lb %s, %v", vip, templateLb, err)
+		return err
+	}
+	if err = c.ovnLegacyClient.RemoveLoadBalancerVip(lb, vip); err != nil {
+		klog.Errorf("failed to delete vip %s from lb %s, %v", vip, lb, err)
+		return err
+	}
+	return c.ovnLegacyClient.DeleteLoadBalancerHealthCheck(lb, vip)
+}
+
+// deleteTemplateLoadBalancerVip removes the vip and its template variables from the template load balancer
+// of the vpc load balancer lb, if any
+func (c *Controller) deleteTemplateLoadBalancerVip(lb, vip string) error {
+	templateLb := genTemplateLoadBalancerName(lb, util.CheckProtocol(parseVipAddr(vip)))
+	lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(templateLb)
+	if err != nil {
+		klog.Errorf("failed to find lb %s, %v", templateLb, err)
+		return err
+	}
+	if lbUuid == "" {
+		return nil
+	}
+	if err = c.ovnLegacyClient.RemoveLoadBalancerVip(templateLb, vip); err != nil {
+		klog.Errorf("failed to delete vip %s from lb %s, %v", vip, templateLb, err)
+		return err
+	}
+
+	nodeChassis, err := c.getNodeChassis()
+	if err != nil {
+		return err
+	}
+	varName := genLbTemplateVarName(templateLb, vip)
+	for _, chassis := range nodeChassis {
+		if err = c.ovnLegacyClient.SetChassisTemplateVars(chassis, map[string]string{varName: ""}); err != nil {
+			klog.Errorf("failed to delete template var %s of chassis %s, %v", varName, chassis, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// gcChassisTemplateVars removes template variables not referred by any template load balancer
+// and those of chassis no longer in the cluster
+func (c *Controller) gcChassisTemplateVars(varNames map[string]bool) error {
+	nodeChassis, err := c.getNodeChassis()
+	if err != nil {
+		return err
+	}
+	chassisSet := make(map[string]bool, len(nodeChassis))
+	for _, chassis := range nodeChassis {
+		chassisSet[chassis] = true
+	}
+
+	chassisVars, err := c.ovnLegacyClient.ListChassisTemplateVars()
+	if err != nil {
+		return err
+	}
+	for chassis, names := range chassisVars {
+		if !chassisSet[chassis] {
+			klog.Infof("gc template vars of chassis %s", chassis)
+			if err = c.ovnLegacyClient.DeleteChassisTemplateVars(chassis); err != nil {
+				return err
+			}
+			continue
+		}
+		stale := make(map[string]string)
+		for _, name := range names {
+			if !varNames[name] {
+				stale[name] = ""
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		klog.Infof("gc template vars of chassis %s: %v", chassis, stale)
+		if err = c.ovnLegacyClient.SetChassisTemplateVars(chassis, stale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package controller
+
+import "testing"
+
+func TestGenLbTemplateVarName(t *testing.T) {
+	tests := []struct {
+		lb, vip, want string
+	}{
+		{"cluster-tcp-loadbalancer-template-ipv4", "10.96.0.10:53", "cluster_tcp_loadbalancer_template_ipv4_10.96.0.10_53"},
+		{"vpc-test-udp-load-template-ipv6", "[fd00:10:96::a]:53", "vpc_test_udp_load_template_ipv6_fd00_10_96__a_53"},
+	}
+	for _, tt := range tests {
+		if got := genLbTemplateVarName(tt.lb, tt.vip); got != tt.want {
+			t.Errorf("genLbTemplateVarName(%q, %q) = %q, want %q", tt.lb, tt.vip, got, tt.want)
+		}
+	}
+}
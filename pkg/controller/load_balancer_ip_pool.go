@@ -0,0 +1,333 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// lbIPPoolIPAMName returns the name of the ipam subnet of the pool,
+// which never conflicts with subnet names as '/' is not allowed in them
+func lbIPPoolIPAMName(pool string) string {
+	return fmt.Sprintf("lb-ip-pool/%s", pool)
+}
+
+func (c *Controller) enqueueAddLbIPPool(obj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue add load balancer ip pool %s", key)
+	c.addOrUpdateLbIPPoolQueue.Add(key)
+}
+
+func (c *Controller) enqueueUpdateLbIPPool(old, new interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	oldPool := old.(*kubeovnv1.LoadBalancerIPPool)
+	newPool := new.(*kubeovnv1.LoadBalancerIPPool)
+	if oldPool.ResourceVersion == newPool.ResourceVersion || reflect.DeepEqual(oldPool.Spec, newPool.Spec) {
+		return
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(new); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue update load balancer ip pool %s", key)
+	c.addOrUpdateLbIPPoolQueue.Add(key)
+}
+
+func (c *Controller) enqueueDeleteLbIPPool(obj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	var key string
+	var err error
+	if key, err = cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue delete load balancer ip pool %s", key)
+	c.delLbIPPoolQueue.Add(key)
+}
+
+func (c *Controller) runAddOrUpdateLbIPPoolWorker() {
+	for c.processNextLbIPPoolWorkItem("addOrUpdateLbIPPool", c.addOrUpdateLbIPPoolQueue, c.handleAddOrUpdateLbIPPool) {
+	}
+}
+
+func (c *Controller) runDelLbIPPoolWorker() {
+	for c.processNextLbIPPoolWorkItem("delLbIPPool", c.delLbIPPoolQueue, c.handleDelLbIPPool) {
+	}
+}
+
+func (c *Controller) runUpdateLbIPPoolStatusWorker() {
+	for c.processNextLbIPPoolWorkItem("updateLbIPPoolStatus", c.updateLbIPPoolStatusQueue, c.handleUpdateLbIPPoolStatus) {
+	}
+}
+
+func (c *Controller) processNextLbIPPoolWorkItem(queueName string, queue workqueue.RateLimitingInterface, handler func(key string) error) bool {
+	obj, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer queue.Done(obj)
+		var key string
+		var ok bool
+		if key, ok = obj.(string); !ok {
+			queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue %s but got %#v", queueName, obj))
+			return nil
+		}
+		if err := handler(key); err != nil {
+			queue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+		}
+		queue.Forget(obj)
+		return nil
+	}(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+	return true
+}
+
+// addLbIPPoolToIPAM registers the pool to ipam, pools have no gateway
+func (c *Controller) addLbIPPoolToIPAM(pool *kubeovnv1.LoadBalancerIPPool) error {
+	var gw string
+	if util.CheckProtocol(pool.Spec.CIDRBlock) == kubeovnv1.ProtocolDual {
+		gw = ","
+	}
+	return c.ipam.AddOrUpdateSubnet(lbIPPoolIPAMName(pool.Name), pool.Spec.CIDRBlock, gw, pool.Spec.ExcludeIps)
+}
+
+func (c *Controller) handleAddOrUpdateLbIPPool(key string) error {
+	pool, err := c.lbIPPoolsLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	klog.Infof("handle add or update load balancer ip pool %s", key)
+
+	if err = util.CheckCidrs(pool.Spec.CIDRBlock); err != nil {
+		klog.Errorf("invalid cidr block %s of load balancer ip pool %s: %v", pool.Spec.CIDRBlock, key, err)
+		return nil
+	}
+	if err = c.addLbIPPoolToIPAM(pool); err != nil {
+		klog.Errorf("failed to add load balancer ip pool %s to ipam: %v", key, err)
+		return err
+	}
+
+	c.enqueueNativeLbSvcs()
+	c.updateLbIPPoolStatusQueue.Add(key)
+	return nil
+}
+
+func (c *Controller) handleDelLbIPPool(key string) error {
+	klog.Infof("handle delete load balancer ip pool %s", key)
+	c.ipam.DeleteSubnet(lbIPPoolIPAMName(key))
+	// services with ips in the pool will allocate ips from other pools
+	c.enqueueNativeLbSvcs()
+	return nil
+}
+
+func (c *Controller) handleUpdateLbIPPoolStatus(key string) error {
+	cachedPool, err := c.lbIPPoolsLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	pool := cachedPool.DeepCopy()
+
+	svcs, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services: %v", err)
+		return err
+	}
+
+	var cidrs []*net.IPNet
+	for _, cidrBlock := range strings.Split(pool.Spec.CIDRBlock, ",") {
+		_, cidr, err := net.ParseCIDR(cidrBlock)
+		if err != nil {
+			return nil
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	var v4Using, v6Using float64
+	for _, svc := range svcs {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if !c.ipam.IsIPAssignedToPod(ingress.IP, lbIPPoolIPAMName(pool.Name), lbSvcIPAMKey(svc)) {
+				continue
+			}
+			if util.CheckProtocol(ingress.IP) == kubeovnv1.ProtocolIPv4 {
+				v4Using++
+			} else {
+				v6Using++
+			}
+		}
+	}
+
+	var v4Available, v6Available float64
+	v4ExcludeIps, v6ExcludeIps := util.SplitIpsByProtocol(pool.Spec.ExcludeIps)
+	for _, cidr := range cidrs {
+		if util.CheckProtocol(cidr.String()) == kubeovnv1.ProtocolIPv4 {
+			v4Available = util.AddressCount(cidr) - util.CountIpNums(util.ExpandExcludeIPs(v4ExcludeIps, cidr.String())) - v4Using
+		} else {
+			v6Available = util.AddressCount(cidr) - util.CountIpNums(util.ExpandExcludeIPs(v6ExcludeIps, cidr.String())) - v6Using
+		}
+	}
+	if v4Available < 0 {
+		v4Available = 0
+	}
+	if v6Available < 0 {
+		v6Available = 0
+	}
+
+	status := kubeovnv1.LoadBalancerIPPoolStatus{
+		V4AvailableIPs: v4Available,
+		V4UsingIPs:     v4Using,
+		V6AvailableIPs: v6Available,
+		V6UsingIPs:     v6Using,
+	}
+	if reflect.DeepEqual(pool.Status, status) {
+		return nil
+	}
+	pool.Status = status
+	if _, err = c.config.KubeOvnClient.KubeovnV1().LoadBalancerIPPools().UpdateStatus(context.Background(), pool, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("failed to update status of load balancer ip pool %s: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+// enqueueLbIPPoolsStatus updates the status of all pools the ips belong to
+func (c *Controller) enqueueLbIPPoolsStatus(ips ...string) {
+	pools, err := c.lbIPPoolsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list load balancer ip pools: %v", err)
+		return
+	}
+	for _, pool := range pools {
+		for _, ip := range ips {
+			if util.CIDRContainIP(pool.Spec.CIDRBlock, ip) {
+				c.updateLbIPPoolStatusQueue.Add(pool.Name)
+				break
+			}
+		}
+	}
+}
+
+// listLbIPPools returns the pools the service is allowed to allocate ips of its ip families from, sorted by name
+func (c *Controller) listLbIPPools(svc *v1.Service) ([]*kubeovnv1.LoadBalancerIPPool, error) {
+	pools, err := c.lbIPPoolsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list load balancer ip pools: %v", err)
+		return nil, err
+	}
+
+	poolName := svc.Annotations[util.LbIPPoolAnnotation]
+	result := make([]*kubeovnv1.LoadBalancerIPPool, 0, len(pools))
+	for _, pool := range pools {
+		if poolName != "" && pool.Name != poolName {
+			continue
+		}
+		if len(pool.Spec.Namespaces) != 0 && !util.IsStringIn(svc.Namespace, pool.Spec.Namespaces) {
+			continue
+		}
+		if protocol := util.CheckProtocol(pool.Spec.CIDRBlock); protocol != kubeovnv1.ProtocolDual && len(svc.Spec.IPFamilies) != 0 {
+			matched := false
+			for _, family := range svc.Spec.IPFamilies {
+				if string(family) == protocol {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		result = append(result, pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// initLbIPPools registers all pools to ipam and restores the ips allocated to load balancer services
+func (c *Controller) initLbIPPools() error {
+	pools, err := c.lbIPPoolsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list load balancer ip pools: %v", err)
+		return err
+	}
+	for _, pool := range pools {
+		if err = c.addLbIPPoolToIPAM(pool); err != nil {
+			klog.Errorf("failed to add load balancer ip pool %s to ipam: %v", pool.Name, err)
+		}
+	}
+
+	svcs, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services: %v", err)
+		return err
+	}
+	for _, svc := range svcs {
+		if !isNativeLbSvc(svc) {
+			continue
+		}
+		for _, pool := range pools {
+			// ipv4 address must be the first one of dual stack addresses
+			var v4IP, v6IP string
+			for _, ingress := range svc.Status.LoadBalancer.Ingress {
+				if ingress.IP == "" || !util.CIDRContainIP(pool.Spec.CIDRBlock, ingress.IP) {
+					continue
+				}
+				if util.CheckProtocol(ingress.IP) == kubeovnv1.ProtocolIPv4 {
+					v4IP = ingress.IP
+				} else {
+					v6IP = ingress.IP
+				}
+			}
+			ips := strings.Trim(strings.Join([]string{v4IP, v6IP}, ","), ",")
+			if ips == "" {
+				continue
+			}
+			key := lbSvcIPAMKey(svc)
+			if _, _, _, err = c.ipam.GetStaticAddress(key, key, ips, "", lbIPPoolIPAMName(pool.Name), true); err != nil {
+				klog.Errorf("failed to restore ips %s of service %s/%s: %v", ips, svc.Namespace, svc.Name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
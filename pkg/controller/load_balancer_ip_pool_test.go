@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	ovnipam "github.com/kubeovn/kube-ovn/pkg/ipam"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func newTestLbIPPoolController(t *testing.T, pools ...*kubeovnv1.LoadBalancerIPPool) *Controller {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &Controller{
+		ipam:            ovnipam.NewIPAM(),
+		lbIPPoolsLister: kubeovnlister.NewLoadBalancerIPPoolLister(indexer),
+	}
+	for _, pool := range pools {
+		if err := indexer.Add(pool); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.addLbIPPoolToIPAM(pool); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func newTestLbIPPool(name, cidrBlock string, namespaces ...string) *kubeovnv1.LoadBalancerIPPool {
+	return &kubeovnv1.LoadBalancerIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       kubeovnv1.LoadBalancerIPPoolSpec{CIDRBlock: cidrBlock, Namespaces: namespaces},
+	}
+}
+
+func newTestLbSvc(namespace, name, pool, loadBalancerIP string, families ...corev1.IPFamily) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.ServiceSpec{
+			Type:           corev1.ServiceTypeLoadBalancer,
+			LoadBalancerIP: loadBalancerIP,
+			IPFamilies:     families,
+		},
+	}
+	if pool != "" {
+		svc.Annotations = map[string]string{util.LbIPPoolAnnotation: pool}
+	}
+	return svc
+}
+
+func TestAcquireLbSvcIPs(t *testing.T) {
+	tests := []struct {
+		name    string
+		pools   []*kubeovnv1.LoadBalancerIPPool
+		svc     *corev1.Service
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "random ip",
+			pools: []*kubeovnv1.LoadBalancerIPPool{newTestLbIPPool("pool1", "10.0.0.0/30")},
+			svc:   newTestLbSvc("default", "svc", "", "", corev1.IPv4Protocol),
+			want:  []string{"10.0.0.1"},
+		},
+		{
+			name:  "requested ip",
+			pools: []*kubeovnv1.LoadBalancerIPPool{newTestLbIPPool("pool1", "10.0.0.0/29")},
+			svc:   newTestLbSvc("default", "svc", "", "10.0.0.5", corev1.IPv4Protocol),
+			want:  []string{"10.0.0.5"},
+		},
+		{
+			name:    "requested ip out of pools",
+			pools:   []*kubeovnv1.LoadBalancerIPPool{newTestLbIPPool("pool1", "10.0.0.0/29")},
+			svc:     newTestLbSvc("default", "svc", "", "10.0.1.5", corev1.IPv4Protocol),
+			wantErr: true,
+		},
+		{
+			name:  "dual stack pool",
+			pools: []*kubeovnv1.LoadBalancerIPPool{newTestLbIPPool("pool1", "10.0.0.0/30,fd00::/126")},
+			svc:   newTestLbSvc("default", "svc", "", "", corev1.IPv4Protocol, corev1.IPv6Protocol),
+			want:  []string{"10.0.0.1", "fd00::1"},
+		},
+		{
+			name: "pool of other families skipped",
+			pools: []*kubeovnv1.LoadBalancerIPPool{
+				newTestLbIPPool("pool1", "fd00::/126"),
+				newTestLbIPPool("pool2", "10.0.0.0/30"),
+			},
+			svc:  newTestLbSvc("default", "svc", "", "", corev1.IPv4Protocol),
+			want: []string{"10.0.0.1"},
+		},
+		{
+			name: "pool of other namespaces skipped",
+			pools: []*kubeovnv1.LoadBalancerIPPool{
+				newTestLbIPPool("pool1", "10.0.0.0/30", "other"),
+				newTestLbIPPool("pool2", "10.0.1.0/30"),
+			},
+			svc:  newTestLbSvc("default", "svc", "", "", corev1.IPv4Protocol),
+			want: []string{"10.0.1.1"},
+		},
+		{
+			name: "pool specified by annotation",
+			pools: []*kubeovnv1.LoadBalancerIPPool{
+				newTestLbIPPool("pool1", "10.0.0.0/30"),
+				newTestLbIPPool("pool2", "10.0.1.0/30"),
+			},
+			svc:  newTestLbSvc("default", "svc", "pool2", "", corev1.IPv4Protocol),
+			want: []string{"10.0.1.1"},
+		},
+		{
+			name:    "no pool",
+			svc:     newTestLbSvc("default", "svc", "", "", corev1.IPv4Protocol),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestLbIPPoolController(t, tt.pools...)
+			got, err := c.acquireLbSvcIPs(tt.svc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("acquireLbSvcIPs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("acquireLbSvcIPs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcquireLbSvcIPsExhausted(t *testing.T) {
+	c := newTestLbIPPoolController(t, newTestLbIPPool("pool1", "10.0.0.0/30"), newTestLbIPPool("pool2", "10.0.1.0/30"))
+	// a /30 pool has two usable ips, the third service is allocated from the next pool
+	var got [][]string
+	for _, name := range []string{"svc1", "svc2", "svc3"} {
+		ips, err := c.acquireLbSvcIPs(newTestLbSvc("default", name, "", "", corev1.IPv4Protocol))
+		if err != nil {
+			t.Fatalf("acquireLbSvcIPs() for %s error = %v", name, err)
+		}
+		got = append(got, ips)
+	}
+	want := [][]string{{"10.0.0.1"}, {"10.0.0.2"}, {"10.0.1.1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("acquireLbSvcIPs() = %v, want %v", got, want)
+	}
+
+	// allocated ips are kept across calls
+	ips, err := c.acquireLbSvcIPs(newTestLbSvc("default", "svc1", "", "", corev1.IPv4Protocol))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ips, want[0]) {
+		t.Errorf("acquireLbSvcIPs() = %v, want %v", ips, want[0])
+	}
+
+	// ips are reallocated once the requested ip changes
+	ips, err = c.acquireLbSvcIPs(newTestLbSvc("default", "svc1", "", "10.0.1.2", corev1.IPv4Protocol))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ips, []string{"10.0.1.2"}) {
+		t.Errorf("acquireLbSvcIPs() = %v, want [10.0.1.2]", ips)
+	}
+
+	if _, err = c.acquireLbSvcIPs(newTestLbSvc("default", "svc4", "", "", corev1.IPv4Protocol)); err != nil {
+		t.Fatalf("acquireLbSvcIPs() should reuse the released ip, error = %v", err)
+	}
+	if _, err = c.acquireLbSvcIPs(newTestLbSvc("default", "svc5", "", "", corev1.IPv4Protocol)); err == nil {
+		t.Errorf("acquireLbSvcIPs() should fail when all pools are exhausted")
+	}
+}
+
+func TestLbSvcIngress(t *testing.T) {
+	tests := []struct {
+		name     string
+		ips      []string
+		families []corev1.IPFamily
+		want     []corev1.LoadBalancerIngress
+	}{
+		{
+			name: "no families",
+			ips:  []string{"10.0.0.1", "fd00::1"},
+			want: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}, {IP: "fd00::1"}},
+		},
+		{
+			name:     "ipv4 only",
+			ips:      []string{"10.0.0.1", "fd00::1"},
+			families: []corev1.IPFamily{corev1.IPv4Protocol},
+			want:     []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+		},
+		{
+			name:     "ipv6 only",
+			ips:      []string{"10.0.0.1", "fd00::1"},
+			families: []corev1.IPFamily{corev1.IPv6Protocol},
+			want:     []corev1.LoadBalancerIngress{{IP: "fd00::1"}},
+		},
+		{
+			name:     "dual stack",
+			ips:      []string{"10.0.0.1", "fd00::1"},
+			families: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			want:     []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}, {IP: "fd00::1"}},
+		},
+		{
+			name:     "no ips",
+			families: []corev1.IPFamily{corev1.IPv4Protocol},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lbSvcIngress(tt.ips, tt.families); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lbSvcIngress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveLbIngressIPs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ingress []corev1.LoadBalancerIngress
+		ips     []string
+		want    []corev1.LoadBalancerIngress
+	}{
+		{
+			name:    "ingress of other implementations kept",
+			ingress: []corev1.LoadBalancerIngress{{IP: "192.168.0.1"}, {Hostname: "lb.example.com"}},
+			want:    []corev1.LoadBalancerIngress{{IP: "192.168.0.1"}, {Hostname: "lb.example.com"}},
+		},
+		{
+			name:    "released ips removed",
+			ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}, {IP: "192.168.0.1"}, {IP: "fd00::1"}},
+			ips:     []string{"10.0.0.1", "fd00::1"},
+			want:    []corev1.LoadBalancerIngress{{IP: "192.168.0.1"}},
+		},
+		{
+			name:    "all removed",
+			ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+			ips:     []string{"10.0.0.1"},
+			want:    []corev1.LoadBalancerIngress{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removeLbIngressIPs(tt.ingress, tt.ips); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removeLbIngressIPs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -65,6 +65,9 @@ func (c *Controller) enqueueUpdateNamespace(old, new interface{}) {
 			c.updateNpQueue.Add(np)
 		}
 	}
+	if !reflect.DeepEqual(oldNs.Labels, newNs.Labels) {
+		c.enqueueSelectorSgs(oldNs, newNs)
+	}
 
 	// in case annotations are removed by other controllers
 	if newNs.Annotations == nil || newNs.Annotations[util.LogicalSwitchAnnotation] == "" {
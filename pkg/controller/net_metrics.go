@@ -24,9 +24,23 @@ var (
 			"protocol",
 			"subnet_cidr",
 		})
+
+	metricLbBackendHealth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "lb_backend_health_status",
+			Help: "The health status of loadbalancer backend probed by ovn service monitor, 1 for online and 0 for offline.",
+		},
+		[]string{
+			"namespace",
+			"service",
+			"backend_ip",
+			"backend_port",
+			"protocol",
+		})
 )
 
 func registerMetrics() {
 	prometheus.MustRegister(metricSubnetAvailableIPs)
 	prometheus.MustRegister(metricSubnetUsedIPs)
+	prometheus.MustRegister(metricLbBackendHealth)
 }
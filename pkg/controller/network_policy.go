@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -67,6 +68,144 @@ func (c *Controller) enqueueUpdateNp(old, new interface{}) {
 	}
 }
 
+// enqueueUpdateNpPod enqueues the network policies matching a pod which joined or left their scope,
+// the pod is read from the lister again when the item is processed
+func (c *Controller) enqueueUpdateNpPod(pod *corev1.Pod) {
+	for _, np := range c.podMatchNetworkPolicies(pod) {
+		klog.V(3).Infof("enqueue update np %s for pod %s/%s", np, pod.Namespace, pod.Name)
+		c.updateNpPodQueue.Add(npPodKey(np, pod.Namespace, pod.Name))
+	}
+}
+
+// npPodRemoval is a pod leaving the scope of a network policy. The addresses and the ports
+// of the pod are captured when it is enqueued, as the pod is gone when the item is processed.
+// Lists are joined by comma to keep the item comparable.
+type npPodRemoval struct {
+	np        string
+	namespace string
+	name      string
+	// selected is whether the pod is selected by the pod selector of the policy
+	selected    bool
+	ports       string
+	addressSets string
+	addresses   string
+	svcIPs      string
+}
+
+func (c *Controller) enqueueRemoveNpPod(pod *corev1.Pod) {
+	podNs, err := c.namespacesLister.Get(pod.Namespace)
+	if err != nil {
+		klog.Errorf("failed to get namespace %s, %v", pod.Namespace, err)
+		return
+	}
+	nps, err := c.npsLister.NetworkPolicies(corev1.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list network policies, %v", err)
+		return
+	}
+	svcs, err := c.servicesLister.Services(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list svc, %v", err)
+		return
+	}
+	var ports []string
+	if !pod.Spec.HostNetwork {
+		if ports, err = c.podPortNames(pod); err != nil {
+			klog.Errorf("failed to get ports of pod %s/%s, %v", pod.Namespace, pod.Name, err)
+			return
+		}
+	}
+
+	removals, err := npPodRemovals(pod, *podNs, nps, ports, svcs)
+	if err != nil {
+		klog.Errorf("failed to get network policies of pod %s/%s, %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	for _, removal := range removals {
+		klog.V(3).Infof("enqueue remove pod %s/%s from np %s", pod.Namespace, pod.Name, removal.np)
+		c.updateNpPodQueue.Add(removal)
+	}
+}
+
+// npPodRemovals returns the removals of a pod from the network policies whose scope it is in
+func npPodRemovals(pod *corev1.Pod, podNs corev1.Namespace, nps []*netv1.NetworkPolicy, ports []string, svcs []*corev1.Service) ([]npPodRemoval, error) {
+	protocols := []string{kubeovnv1.ProtocolIPv4, kubeovnv1.ProtocolIPv6}
+	var addresses, svcIPs []string
+	for _, podIP := range pod.Status.PodIPs {
+		if podIP.IP != "" {
+			addresses = append(addresses, podIP.IP)
+		}
+	}
+	for _, protocol := range protocols {
+		ips, err := svcMatchPods(svcs, pod, protocol)
+		if err != nil {
+			return nil, err
+		}
+		svcIPs = append(svcIPs, ips...)
+	}
+
+	var removals []npPodRemoval
+	for _, np := range nps {
+		if !isPodMatchNetworkPolicy(pod, podNs, np, np.Namespace) {
+			continue
+		}
+		removal := npPodRemoval{
+			np:        fmt.Sprintf("%s/%s", np.Namespace, np.Name),
+			namespace: pod.Namespace,
+			name:      pod.Name,
+			addresses: strings.Join(addresses, ","),
+			svcIPs:    strings.Join(svcIPs, ","),
+		}
+		sel, _ := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+		if pod.Namespace == np.Namespace && sel.Matches(labels.Set(pod.Labels)) && !pod.Spec.HostNetwork {
+			removal.selected = true
+			removal.ports = strings.Join(ports, ",")
+		}
+
+		var addressSets []string
+		for _, protocol := range protocols {
+			for asName, peers := range npPeerAddressSets(np, protocol) {
+				for _, npp := range peers {
+					if isPodMatchPolicyPeer(pod, podNs, npp, np.Namespace) {
+						addressSets = append(addressSets, asName)
+						break
+					}
+				}
+			}
+		}
+		sort.Strings(addressSets)
+		removal.addressSets = strings.Join(addressSets, ",")
+		removals = append(removals, removal)
+	}
+	return removals, nil
+}
+
+// splitNpPodRemovalList splits a list of a npPodRemoval joined by comma
+func splitNpPodRemovalList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// npPodKey returns the key of a network policy and a pod in the form of npNamespace/npName/podNamespace/podName
+func npPodKey(np, podNamespace, podName string) string {
+	return fmt.Sprintf("%s/%s/%s", np, podNamespace, podName)
+}
+
+func splitNpPodKey(key string) (np, podNamespace, podName string, err error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 4 {
+		return "", "", "", fmt.Errorf("unexpected np pod key format: %q", key)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return "", "", "", fmt.Errorf("unexpected np pod key format: %q", key)
+		}
+	}
+	return parts[0] + "/" + parts[1], parts[2], parts[3], nil
+}
+
 func (c *Controller) runUpdateNpWorker() {
 	for c.processNextUpdateNpWorkItem() {
 	}
@@ -77,6 +216,47 @@ func (c *Controller) runDeleteNpWorker() {
 	}
 }
 
+func (c *Controller) runUpdateNpPodWorker() {
+	for c.processNextUpdateNpPodWorkItem() {
+	}
+}
+
+func (c *Controller) processNextUpdateNpPodWorkItem() bool {
+	obj, shutdown := c.updateNpPodQueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.updateNpPodQueue.Done(obj)
+		switch item := obj.(type) {
+		case string:
+			if err := c.handleUpdateNpPod(item); err != nil {
+				c.updateNpPodQueue.AddRateLimited(obj)
+				return fmt.Errorf("error syncing '%s': %s, requeuing", item, err.Error())
+			}
+		case npPodRemoval:
+			if err := c.handleRemoveNpPod(item); err != nil {
+				c.updateNpPodQueue.AddRateLimited(obj)
+				return fmt.Errorf("error removing pod %s/%s from np %s: %s, requeuing", item.namespace, item.name, item.np, err.Error())
+			}
+		default:
+			c.updateNpPodQueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string or npPodRemoval in workqueue but got %#v", obj))
+			return nil
+		}
+		c.updateNpPodQueue.Forget(obj)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
 func (c *Controller) processNextUpdateNpWorkItem() bool {
 	obj, shutdown := c.updateNpQueue.Get()
 
@@ -145,6 +325,9 @@ func (c *Controller) handleUpdateNp(key string) error {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
 		return nil
 	}
+	c.npKeyMutex.Lock(key)
+	defer c.npKeyMutex.Unlock(key)
+
 	np, err := c.npsLister.NetworkPolicies(namespace).Get(name)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -152,26 +335,11 @@ func (c *Controller) handleUpdateNp(key string) error {
 		}
 		return err
 	}
-	subnet, err := c.subnetsLister.Get(c.config.DefaultLogicalSwitch)
-	if err != nil {
-		klog.Errorf("failed to get default subnet %v", err)
-		return err
-	}
-	subnets, err := c.subnetsLister.List(labels.Everything())
+	subnet, err := c.getNpSubnet(np)
 	if err != nil {
-		klog.Errorf("failed to list subnets %v", err)
 		return err
 	}
 
-	for _, s := range subnets {
-		for _, ns := range s.Spec.Namespaces {
-			if ns == np.Namespace {
-				subnet = s
-				break
-			}
-		}
-	}
-
 	defer func() {
 		if err != nil {
 			c.recorder.Eventf(np, corev1.EventTypeWarning, "CreateACLFailed", err.Error())
@@ -519,6 +687,8 @@ func (c *Controller) handleDeleteNp(key string) error {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
 		return nil
 	}
+	c.npKeyMutex.Lock(key)
+	defer c.npKeyMutex.Unlock(key)
 
 	pgName := strings.Replace(fmt.Sprintf("%s.%s", name, namespace), "-", ".", -1)
 	if err := c.ovnLegacyClient.DeletePortGroup(pgName); err != nil {
@@ -563,6 +733,391 @@ func (c *Controller) handleDeleteNp(key string) error {
 	return nil
 }
 
+// handleUpdateNpPod only updates the address sets and the port group of a network policy
+// for a pod joining its scope, ACLs are left untouched
+func (c *Controller) handleUpdateNpPod(key string) error {
+	npKey, podNamespace, podName, err := splitNpPodKey(key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(npKey)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", npKey))
+		return nil
+	}
+	c.npKeyMutex.Lock(npKey)
+	defer c.npKeyMutex.Unlock(npKey)
+
+	np, err := c.npsLister.NetworkPolicies(namespace).Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	pgName := strings.Replace(fmt.Sprintf("%s.%s", np.Name, np.Namespace), "-", ".", -1)
+	exists, err := c.ovnLegacyClient.PortGroupExists(pgName)
+	if err != nil {
+		klog.Errorf("failed to check port group %s, %v", pgName, err)
+		return err
+	}
+	if !exists {
+		// the policy has not been synced yet, fall back to a full update
+		c.updateNpQueue.Add(npKey)
+		return nil
+	}
+
+	subnet, err := c.getNpSubnet(np)
+	if err != nil {
+		return err
+	}
+	var protocols []string
+	for _, cidrBlock := range strings.Split(subnet.Spec.CIDRBlock, ",") {
+		protocols = append(protocols, util.CheckProtocol(cidrBlock))
+	}
+
+	pod, err := c.podsLister.Pods(podNamespace).Get(podName)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to get pod %s/%s, %v", podNamespace, podName, err)
+		return err
+	}
+	if pod == nil || !isPodAlive(pod) {
+		// the pod leaving the scope is handled by the npPodRemoval enqueued for it
+		return nil
+	}
+
+	podNs, err := c.namespacesLister.Get(pod.Namespace)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.updateNpQueue.Add(npKey)
+			return nil
+		}
+		klog.Errorf("failed to get namespace %s, %v", pod.Namespace, err)
+		return err
+	}
+	return c.addNpPod(np, pgName, protocols, pod, *podNs)
+}
+
+// addNpPod adds the ports and the addresses of a pod to the port group and the address sets of a network policy
+func (c *Controller) addNpPod(np *netv1.NetworkPolicy, pgName string, protocols []string, pod *corev1.Pod, podNs corev1.Namespace) error {
+	sel, _ := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+	if pod.Namespace == np.Namespace && sel.Matches(labels.Set(pod.Labels)) && !pod.Spec.HostNetwork {
+		podPorts, err := c.fetchPodPorts(pod)
+		if err != nil {
+			return err
+		}
+		for _, port := range podPorts {
+			if err = c.ovnClient.PortGroupAddPort(pgName, port); err != nil {
+				klog.Errorf("failed to add port %s to port group %s, %v", port, pgName, err)
+				return err
+			}
+		}
+
+		if pod.Annotations[util.AllocatedAnnotation] == "true" {
+			for _, protocol := range protocols {
+				svcAsName := strings.Replace(fmt.Sprintf("%s.%s.service.%s", np.Name, np.Namespace, protocol), "-", ".", -1)
+				podSvcIPs, err := c.podMatchedSvcIPs(pod, protocol)
+				if err != nil {
+					return err
+				}
+				for _, address := range podSvcIPs {
+					if err = c.ovnLegacyClient.AddAddressSetAddresses(svcAsName, address); err != nil {
+						klog.Errorf("failed to add %s to address_set %s, %v", address, svcAsName, err)
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	for _, protocol := range protocols {
+		podAddresses, err := c.podPolicyAddresses(pod, protocol)
+		if err != nil {
+			return err
+		}
+		if len(podAddresses) == 0 {
+			continue
+		}
+
+		for asName, peers := range npPeerAddressSets(np, protocol) {
+			matched := false
+			for _, npp := range peers {
+				if isPodMatchPolicyPeer(pod, podNs, npp, np.Namespace) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+			for _, address := range podAddresses {
+				if err = c.ovnLegacyClient.AddAddressSetAddresses(asName, address); err != nil {
+					klog.Errorf("failed to add %s to address_set %s, %v", address, asName, err)
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// handleRemoveNpPod removes the addresses and the ports of a pod leaving the scope of a network policy
+// from its port group and address sets, ACLs are left untouched
+func (c *Controller) handleRemoveNpPod(removal npPodRemoval) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(removal.np)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", removal.np))
+		return nil
+	}
+	c.npKeyMutex.Lock(removal.np)
+	defer c.npKeyMutex.Unlock(removal.np)
+
+	np, err := c.npsLister.NetworkPolicies(namespace).Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	pgName := strings.Replace(fmt.Sprintf("%s.%s", np.Name, np.Namespace), "-", ".", -1)
+	exists, err := c.ovnLegacyClient.PortGroupExists(pgName)
+	if err != nil {
+		klog.Errorf("failed to check port group %s, %v", pgName, err)
+		return err
+	}
+	if !exists {
+		// the full update of the policy is computed from the pods left in its scope
+		return nil
+	}
+
+	subnet, err := c.getNpSubnet(np)
+	if err != nil {
+		return err
+	}
+	var protocols []string
+	for _, cidrBlock := range strings.Split(subnet.Spec.CIDRBlock, ",") {
+		protocols = append(protocols, util.CheckProtocol(cidrBlock))
+	}
+
+	// the pod may have been recreated with the same name, or only its IP changed,
+	// so the ports and the addresses of the current pod are kept
+	var keptPorts, keptAddresses []string
+	pod, err := c.podsLister.Pods(removal.namespace).Get(removal.name)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to get pod %s/%s, %v", removal.namespace, removal.name, err)
+		return err
+	}
+	if pod != nil && isPodAlive(pod) {
+		if keptPorts, err = c.fetchPodPorts(pod); err != nil {
+			return err
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			keptAddresses = append(keptAddresses, podIP.IP)
+		}
+	}
+
+	if removal.selected {
+		for _, port := range splitNpPodRemovalList(removal.ports) {
+			if util.ContainsString(keptPorts, port) {
+				continue
+			}
+			if err = c.ovnClient.PortGroupRemovePort(pgName, port); err != nil {
+				klog.Errorf("failed to remove port %s from port group %s, %v", port, pgName, err)
+				return err
+			}
+		}
+	}
+
+	sel, _ := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+	addressSets := splitNpPodRemovalList(removal.addressSets)
+	for _, protocol := range protocols {
+		var svcIPs []string
+		for _, svcIP := range splitNpPodRemovalList(removal.svcIPs) {
+			if util.CheckProtocol(svcIP) == protocol {
+				svcIPs = append(svcIPs, svcIP)
+			}
+		}
+
+		if removal.selected {
+			svcAsName := strings.Replace(fmt.Sprintf("%s.%s.service.%s", np.Name, np.Namespace, protocol), "-", ".", -1)
+			inUse, err := c.npSvcIPsInUse(removal.namespace, protocol, svcIPs, func(pod *corev1.Pod, _ corev1.Namespace) bool {
+				return pod.Namespace == np.Namespace && sel.Matches(labels.Set(pod.Labels)) && !pod.Spec.HostNetwork
+			})
+			if err != nil {
+				return err
+			}
+			if err = c.removeNpAddresses(svcAsName, svcIPs, inUse); err != nil {
+				return err
+			}
+		}
+
+		for asName, peers := range npPeerAddressSets(np, protocol) {
+			if !util.ContainsString(addressSets, asName) {
+				continue
+			}
+			inUse, err := c.npSvcIPsInUse(removal.namespace, protocol, svcIPs, func(pod *corev1.Pod, podNs corev1.Namespace) bool {
+				for _, npp := range peers {
+					if isPodMatchPolicyPeer(pod, podNs, npp, np.Namespace) {
+						return true
+					}
+				}
+				return false
+			})
+			if err != nil {
+				return err
+			}
+			for _, address := range splitNpPodRemovalList(removal.addresses) {
+				if util.CheckProtocol(address) == protocol && !util.ContainsString(keptAddresses, address) {
+					inUse[address] = false
+				}
+			}
+			addresses := make([]string, 0, len(inUse))
+			for address := range inUse {
+				addresses = append(addresses, address)
+			}
+			if err = c.removeNpAddresses(asName, addresses, inUse); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// npSvcIPsInUse returns whether each of the service IPs is matched by an alive pod in the namespace
+// the match function accepts
+func (c *Controller) npSvcIPsInUse(namespace, protocol string, svcIPs []string, match func(*corev1.Pod, corev1.Namespace) bool) (map[string]bool, error) {
+	inUse := make(map[string]bool, len(svcIPs))
+	for _, svcIP := range svcIPs {
+		inUse[svcIP] = false
+	}
+	if len(svcIPs) == 0 {
+		return inUse, nil
+	}
+
+	podNs, err := c.namespacesLister.Get(namespace)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return inUse, nil
+		}
+		klog.Errorf("failed to get namespace %s, %v", namespace, err)
+		return nil, err
+	}
+	pods, err := c.podsLister.Pods(namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list pods, %v", err)
+		return nil, err
+	}
+	svcs, err := c.servicesLister.Services(namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list svc, %v", err)
+		return nil, err
+	}
+	for _, pod := range pods {
+		if !isPodAlive(pod) || !match(pod, *podNs) {
+			continue
+		}
+		matched, err := svcMatchPods(svcs, pod, protocol)
+		if err != nil {
+			return nil, err
+		}
+		for _, svcIP := range matched {
+			if _, ok := inUse[svcIP]; ok {
+				inUse[svcIP] = true
+			}
+		}
+	}
+	return inUse, nil
+}
+
+// removeNpAddresses removes the addresses not in use from an address set
+func (c *Controller) removeNpAddresses(asName string, addresses []string, inUse map[string]bool) error {
+	for _, address := range addresses {
+		if inUse[address] {
+			continue
+		}
+		if err := c.ovnLegacyClient.RemoveAddressSetAddresses(asName, address); err != nil {
+			klog.Errorf("failed to remove %s from address_set %s, %v", address, asName, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// npPeerAddressSets returns the allow address sets of the protocol of the ingress and egress rules
+// with peers, and the peers of each address set
+func npPeerAddressSets(np *netv1.NetworkPolicy, protocol string) map[string][]netv1.NetworkPolicyPeer {
+	addressSets := make(map[string][]netv1.NetworkPolicyPeer)
+	if hasIngressRule(np) {
+		asNamePrefix := strings.Replace(fmt.Sprintf("%s.%s.ingress.allow", np.Name, np.Namespace), "-", ".", -1)
+		for idx, npr := range np.Spec.Ingress {
+			if len(npr.From) != 0 {
+				addressSets[fmt.Sprintf("%s.%s.%d", asNamePrefix, protocol, idx)] = npr.From
+			}
+		}
+	}
+	if hasEgressRule(np) {
+		asNamePrefix := strings.Replace(fmt.Sprintf("%s.%s.egress.allow", np.Name, np.Namespace), "-", ".", -1)
+		for idx, npr := range np.Spec.Egress {
+			if len(npr.To) != 0 {
+				addressSets[fmt.Sprintf("%s.%s.%d", asNamePrefix, protocol, idx)] = npr.To
+			}
+		}
+	}
+	return addressSets
+}
+
+// podPolicyAddresses returns the pod IPs and the matched service IPs of the protocol,
+// the same as what fetchPolicySelectedAddresses selects for a pod
+func (c *Controller) podPolicyAddresses(pod *corev1.Pod, protocol string) ([]string, error) {
+	var addresses []string
+	for _, podIP := range pod.Status.PodIPs {
+		if podIP.IP != "" && util.CheckProtocol(podIP.IP) == protocol {
+			addresses = append(addresses, podIP.IP)
+			svcIPs, err := c.podMatchedSvcIPs(pod, protocol)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, svcIPs...)
+		}
+	}
+	return addresses, nil
+}
+
+func (c *Controller) podMatchedSvcIPs(pod *corev1.Pod, protocol string) ([]string, error) {
+	svcs, err := c.servicesLister.Services(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list svc, %v", err)
+		return nil, err
+	}
+	return svcMatchPods(svcs, pod, protocol)
+}
+
+func (c *Controller) getNpSubnet(np *netv1.NetworkPolicy) (*kubeovnv1.Subnet, error) {
+	subnet, err := c.subnetsLister.Get(c.config.DefaultLogicalSwitch)
+	if err != nil {
+		klog.Errorf("failed to get default subnet %v", err)
+		return nil, err
+	}
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets %v", err)
+		return nil, err
+	}
+
+	for _, s := range subnets {
+		for _, ns := range s.Spec.Namespaces {
+			if ns == np.Namespace {
+				subnet = s
+				break
+			}
+		}
+	}
+	return subnet, nil
+}
+
 func (c *Controller) fetchSelectedPorts(namespace string, selector *metav1.LabelSelector) ([]string, error) {
 	sel, err := metav1.LabelSelectorAsSelector(selector)
 	if err != nil {
@@ -575,23 +1130,38 @@ func (c *Controller) fetchSelectedPorts(namespace string, selector *metav1.Label
 
 	ports := make([]string, 0, len(pods))
 	for _, pod := range pods {
-		if !isPodAlive(pod) || pod.Spec.HostNetwork {
-			continue
-		}
-		podName := c.getNameByPod(pod)
-		podNets, err := c.getPodKubeovnNets(pod)
+		podPorts, err := c.fetchPodPorts(pod)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get pod networks, %v", err)
+			return nil, err
 		}
+		ports = append(ports, podPorts...)
+	}
+	return ports, nil
+}
 
-		for _, podNet := range podNets {
-			if !isOvnSubnet(podNet.Subnet) {
-				continue
-			}
+func (c *Controller) fetchPodPorts(pod *corev1.Pod) ([]string, error) {
+	if !isPodAlive(pod) || pod.Spec.HostNetwork {
+		return nil, nil
+	}
+	return c.podPortNames(pod)
+}
 
-			if pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] == "true" {
-				ports = append(ports, ovs.PodNameToPortName(podName, pod.Namespace, podNet.ProviderName))
-			}
+// podPortNames returns the names of the logical switch ports allocated to a pod
+func (c *Controller) podPortNames(pod *corev1.Pod) ([]string, error) {
+	podName := c.getNameByPod(pod)
+	podNets, err := c.getPodKubeovnNets(pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod networks, %v", err)
+	}
+
+	var ports []string
+	for _, podNet := range podNets {
+		if !isOvnSubnet(podNet.Subnet) {
+			continue
+		}
+
+		if pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] == "true" {
+			ports = append(ports, ovs.PodNameToPortName(podName, pod.Namespace, podNet.ProviderName))
 		}
 	}
 	return ports, nil
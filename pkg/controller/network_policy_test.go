@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitNpPodKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		np           string
+		podNamespace string
+		podName      string
+		wantErr      bool
+	}{
+		{
+			name:         "same namespace",
+			key:          npPodKey("default/deny-all", "default", "nginx-0"),
+			np:           "default/deny-all",
+			podNamespace: "default",
+			podName:      "nginx-0",
+		},
+		{
+			name:         "peer in other namespace",
+			key:          npPodKey("app/allow-monitoring", "monitoring", "prometheus-0"),
+			np:           "app/allow-monitoring",
+			podNamespace: "monitoring",
+			podName:      "prometheus-0",
+		},
+		{
+			name:    "np key only",
+			key:     "default/deny-all",
+			wantErr: true,
+		},
+		{
+			name:    "empty part",
+			key:     "default//default/nginx-0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			np, podNamespace, podName, err := splitNpPodKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitNpPodKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if np != tt.np || podNamespace != tt.podNamespace || podName != tt.podName {
+				t.Errorf("splitNpPodKey(%q) = %q, %q, %q, want %q, %q, %q", tt.key, np, podNamespace, podName, tt.np, tt.podNamespace, tt.podName)
+			}
+		})
+	}
+}
+
+func TestNpPeerAddressSets(t *testing.T) {
+	podPeer := netv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}
+	ipBlockPeer := netv1.NetworkPolicyPeer{IPBlock: &netv1.IPBlock{CIDR: "10.0.0.0/8"}}
+	np := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "app-ns"},
+		Spec: netv1.NetworkPolicySpec{
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{From: []netv1.NetworkPolicyPeer{podPeer}},
+				{},
+				{From: []netv1.NetworkPolicyPeer{ipBlockPeer}},
+			},
+			Egress: []netv1.NetworkPolicyEgressRule{
+				{To: []netv1.NetworkPolicyPeer{ipBlockPeer, podPeer}},
+			},
+		},
+	}
+
+	got := npPeerAddressSets(np, "IPv4")
+	want := map[string][]netv1.NetworkPolicyPeer{
+		"allow.web.app.ns.ingress.allow.IPv4.0": {podPeer},
+		"allow.web.app.ns.ingress.allow.IPv4.2": {ipBlockPeer},
+		"allow.web.app.ns.egress.allow.IPv4.0":  {ipBlockPeer, podPeer},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("npPeerAddressSets() = %v, want %v", got, want)
+	}
+
+}
+
+func TestNpPodRemovals(t *testing.T) {
+	webPeer := netv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}
+	monitoringPeer := netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}},
+	}
+	denyAll := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-all", Namespace: "app"},
+		Spec:       netv1.NetworkPolicySpec{PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress}},
+	}
+	allowWeb := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "app"},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress},
+			Ingress:     []netv1.NetworkPolicyIngressRule{{From: []netv1.NetworkPolicyPeer{webPeer}}},
+		},
+	}
+	allowMonitoring := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-monitoring", Namespace: "other"},
+		Spec: netv1.NetworkPolicySpec{
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress},
+			Ingress:     []netv1.NetworkPolicyIngressRule{{From: []netv1.NetworkPolicyPeer{monitoringPeer}}},
+		},
+	}
+	nps := []*netv1.NetworkPolicy{denyAll, allowWeb, allowMonitoring}
+	svcs := []*corev1.Service{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "app"},
+		Spec: corev1.ServiceSpec{
+			Selector:   map[string]string{"app": "web"},
+			ClusterIPs: []string{"10.96.0.10", "fd00:10:96::10"},
+		},
+	}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "app", Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: "10.16.0.5"}, {IP: "fd00:10:16::5"}}},
+	}
+	podNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+
+	got, err := npPodRemovals(pod, podNs, nps, []string{"web-0.app"}, svcs)
+	if err != nil {
+		t.Fatalf("npPodRemovals() error = %v", err)
+	}
+	want := []npPodRemoval{
+		{
+			np:        "app/deny-all",
+			namespace: "app",
+			name:      "web-0",
+			selected:  true,
+			ports:     "web-0.app",
+			addresses: "10.16.0.5,fd00:10:16::5",
+			svcIPs:    "10.96.0.10,fd00:10:96::10",
+		},
+		{
+			np:          "app/allow-web",
+			namespace:   "app",
+			name:        "web-0",
+			addressSets: "allow.web.app.ingress.allow.IPv4.0,allow.web.app.ingress.allow.IPv6.0",
+			addresses:   "10.16.0.5,fd00:10:16::5",
+			svcIPs:      "10.96.0.10,fd00:10:96::10",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("npPodRemovals() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSplitNpPodRemovalList(t *testing.T) {
+	if got := splitNpPodRemovalList(""); got != nil {
+		t.Errorf("splitNpPodRemovalList(\"\") = %v, want nil", got)
+	}
+	want := []string{"10.16.0.5", "fd00:10:16::5"}
+	if got := splitNpPodRemovalList("10.16.0.5,fd00:10:16::5"); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitNpPodRemovalList() = %v, want %v", got, want)
+	}
+}
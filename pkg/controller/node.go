@@ -67,6 +67,11 @@ func (c *Controller) enqueueUpdateNode(oldObj, newObj interface{}) {
 		klog.V(3).Infof("enqueue update node %s", key)
 		c.updateNodeQueue.Add(key)
 	}
+
+	if oldNode.Annotations[util.ChassisAnnotation] != newNode.Annotations[util.ChassisAnnotation] ||
+		oldNode.Labels[v1.LabelTopologyZone] != newNode.Labels[v1.LabelTopologyZone] {
+		c.enqueueTemplateLbServices()
+	}
 }
 
 func (c *Controller) enqueueDeleteNode(obj interface{}) {
@@ -81,6 +86,17 @@ func (c *Controller) enqueueDeleteNode(obj interface{}) {
 	}
 	klog.V(3).Infof("enqueue delete node %s", key)
 	c.deleteNodeQueue.Add(key)
+
+	// routes learned by the speaker of the node are no longer installed
+	vpcs, _ := c.vpcsLister.List(labels.Everything())
+	for _, vpc := range vpcs {
+		for _, route := range vpc.Status.LearnedRoutes {
+			if route.Node == key {
+				c.addOrUpdateVpcQueue.Add(vpc.Name)
+				break
+			}
+		}
+	}
 }
 
 func (c *Controller) runAddNodeWorker() {
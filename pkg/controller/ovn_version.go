@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// load balancer templates and the affinity_timeout option of load balancers are supported since ovn 22.12,
+// the options are ignored by earlier versions
+const (
+	ovnLbTemplateMajorVersion = 22
+	ovnLbTemplateMinorVersion = 12
+)
+
+// the version of ovn-northd is refreshed periodically as ovn-central may be upgraded after the controller
+const northdVersionTTL = time.Minute
+
+type northdVersionCache struct {
+	sync.Mutex
+	version   string
+	updatedAt time.Time
+}
+
+// parseOvnVersion parses the major and minor version from an ovn version such as 22.03.1-20.21.0-56.4
+func parseOvnVersion(version string) (int, int, error) {
+	fields := strings.SplitN(strings.SplitN(version, "-", 2)[0], ".", 3)
+	if len(fields) < 2 {
+		return 0, 0, fmt.Errorf("invalid ovn version %q", version)
+	}
+	major, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ovn version %q: %v", version, err)
+	}
+	minor, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ovn version %q: %v", version, err)
+	}
+	return major, minor, nil
+}
+
+func ovnVersionAtLeast(version string, major, minor int) bool {
+	vMajor, vMinor, err := parseOvnVersion(version)
+	if err != nil {
+		return false
+	}
+	return vMajor > major || vMajor == major && vMinor >= minor
+}
+
+// getNorthdVersion returns the cached version of ovn-northd
+func (c *Controller) getNorthdVersion() string {
+	c.northdVersion.Lock()
+	defer c.northdVersion.Unlock()
+	if c.northdVersion.version != "" && time.Since(c.northdVersion.updatedAt) < northdVersionTTL {
+		return c.northdVersion.version
+	}
+
+	version, err := c.ovnLegacyClient.GetNorthdVersion()
+	if err != nil || version == "" {
+		// keep using the last known version until ovn-northd is reachable again
+		return c.northdVersion.version
+	}
+	if version != c.northdVersion.version {
+		klog.Infof("ovn-northd version %s", version)
+	}
+	c.northdVersion.version, c.northdVersion.updatedAt = version, time.Now()
+	return version
+}
+
+// ovnLbTemplateSupported returns whether ovn-northd supports load balancer templates and affinity timeout
+func (c *Controller) ovnLbTemplateSupported() bool {
+	return ovnVersionAtLeast(c.getNorthdVersion(), ovnLbTemplateMajorVersion, ovnLbTemplateMinorVersion)
+}
+
+// ovnLbAffinityTimeoutSupported returns whether ovn-northd supports the affinity_timeout option of load balancers
+func (c *Controller) ovnLbAffinityTimeoutSupported() bool {
+	return c.ovnLbTemplateSupported()
+}
@@ -0,0 +1,24 @@
+package controller
+
+import "testing"
+
+func TestOvnVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"22.03.1-20.21.0-56.4", false},
+		{"22.09.0-20.27.0-70.6", false},
+		{"22.12.0-20.28.0-71.0", true},
+		{"23.03.0", true},
+		{"", false},
+		{"invalid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := ovnVersionAtLeast(tt.version, ovnLbTemplateMajorVersion, ovnLbTemplateMinorVersion); got != tt.want {
+				t.Errorf("ovnVersionAtLeast(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
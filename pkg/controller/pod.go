@@ -62,10 +62,12 @@ func (c *Controller) enqueueAddPod(obj interface{}) {
 	}
 
 	p := obj.(*v1.Pod)
-	// TODO: we need to find a way to reduce duplicated np added to the queue
 	if c.config.EnableNP && p.Status.PodIP != "" {
-		for _, np := range c.podMatchNetworkPolicies(p) {
-			c.updateNpQueue.Add(np)
+		c.enqueueUpdateNpPod(p)
+	}
+	if p.Status.PodIP != "" {
+		for _, sg := range c.podMatchSecurityGroups(p) {
+			c.syncSgPortsQueue.Add(sg)
 		}
 	}
 
@@ -134,9 +136,10 @@ func (c *Controller) enqueueDeletePod(obj interface{}) {
 
 	p := obj.(*v1.Pod)
 	if c.config.EnableNP {
-		for _, np := range c.podMatchNetworkPolicies(p) {
-			c.updateNpQueue.Add(np)
-		}
+		c.enqueueRemoveNpPod(p)
+	}
+	for _, sg := range c.podMatchSecurityGroups(p) {
+		c.syncSgPortsQueue.Add(sg)
 	}
 
 	if p.Spec.HostNetwork {
@@ -189,10 +192,23 @@ func (c *Controller) enqueueUpdatePod(oldObj, newObj interface{}) {
 			}
 		}
 
-		if oldPod.Status.PodIP != newPod.Status.PodIP {
-			for _, np := range c.podMatchNetworkPolicies(newPod) {
-				c.updateNpQueue.Add(np)
+		if oldPod.Status.PodIP != newPod.Status.PodIP || isPodAlive(oldPod) != isPodAlive(newPod) {
+			if oldPod.Status.PodIP != "" && isPodAlive(oldPod) {
+				c.enqueueRemoveNpPod(oldPod)
 			}
+			if newPod.Status.PodIP != "" && isPodAlive(newPod) {
+				c.enqueueUpdateNpPod(newPod)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(oldPod.Labels, newPod.Labels) ||
+		oldPod.Status.PodIP != newPod.Status.PodIP ||
+		isPodAlive(oldPod) != isPodAlive(newPod) {
+		oldSgs := c.podMatchSecurityGroups(oldPod)
+		newSgs := c.podMatchSecurityGroups(newPod)
+		for _, sg := range append(oldSgs, newSgs...) {
+			c.syncSgPortsQueue.Add(sg)
 		}
 	}
 
@@ -3,13 +3,14 @@ package controller
 import (
 	"context"
 	"fmt"
-	"net"
 	"reflect"
 	"strings"
 
 	"github.com/cnf/structhash"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -198,6 +199,28 @@ func (c *Controller) updateDenyAllSgPorts() error {
 		}
 		ports = append(ports, ret["name"][0])
 	}
+
+	sgs, err := c.sgsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list security groups, %v", err)
+		return err
+	}
+	for _, sg := range sgs {
+		if sg.Name == util.DenyAllSecurityGroup || !sgHasSelector(sg) || sg.Status.PortGroup == "" {
+			continue
+		}
+		// the selected ports are already synced to the port group of the security group
+		selected, err := c.ovnClient.GetPortGroupPorts(sg.Status.PortGroup)
+		if err != nil {
+			klog.Errorf("failed to get ports of port group %s, %v", sg.Status.PortGroup, err)
+			return err
+		}
+		for _, port := range selected {
+			if !util.ContainsString(ports, port) {
+				ports = append(ports, port)
+			}
+		}
+	}
 	return c.ovnLegacyClient.SetPortsToPortGroup(ovs.GetSgPortGroupName(util.DenyAllSecurityGroup), ports)
 }
 
@@ -286,44 +309,18 @@ func (c *Controller) handleAddOrUpdateSg(key string) error {
 }
 
 func (c *Controller) validateSgRule(sg *kubeovnv1.SecurityGroup) error {
-	// check sg rules
+	// check sg rules, the priority is validated by webhook
 	allRules := append(sg.Spec.IngressRules, sg.Spec.EgressRules...)
 	for _, rule := range allRules {
-		if rule.IPVersion != "ipv4" && rule.IPVersion != "ipv6" {
-			return fmt.Errorf("IPVersion should be 'ipv4' or 'ipv6'")
-		}
-
-		if rule.Priority < 1 || rule.Priority > 200 {
-			return fmt.Errorf("priority '%d' is not in the range of 1 to 200", rule.Priority)
+		if err := util.ValidateSgRule(rule); err != nil {
+			return err
 		}
 
-		switch rule.RemoteType {
-		case kubeovnv1.SgRemoteTypeAddress:
-			if strings.Contains(rule.RemoteAddress, "/") {
-				if _, _, err := net.ParseCIDR(rule.RemoteAddress); err != nil {
-					return fmt.Errorf("invalid CIDR '%s'", rule.RemoteAddress)
-				}
-			} else {
-				if net.ParseIP(rule.RemoteAddress) == nil {
-					return fmt.Errorf("invalid ip address '%s'", rule.RemoteAddress)
-				}
-			}
-		case kubeovnv1.SgRemoteTypeSg:
+		if rule.RemoteType == kubeovnv1.SgRemoteTypeSg {
 			_, err := c.sgsLister.Get(rule.RemoteSecurityGroup)
 			if err != nil {
 				return fmt.Errorf("failed to get remote sg '%s', %v", rule.RemoteSecurityGroup, err)
 			}
-		default:
-			return fmt.Errorf("not support sgRemoteType '%s'", rule.RemoteType)
-		}
-
-		if rule.Protocol == kubeovnv1.ProtocolTCP || rule.Protocol == kubeovnv1.ProtocolUDP {
-			if rule.PortRangeMin < 1 || rule.PortRangeMin > 65535 || rule.PortRangeMax < 1 || rule.PortRangeMax > 65535 {
-				return fmt.Errorf("portRange is out of range")
-			}
-			if rule.PortRangeMin > rule.PortRangeMax {
-				return fmt.Errorf("portRange err, range Minimum value greater than maximum value")
-			}
 		}
 	}
 	return nil
@@ -369,12 +366,8 @@ func (c *Controller) syncSgLogicalPort(key string) error {
 
 	var v4s, v6s []string
 	var ports []string
-	for _, ret := range results {
-		if len(ret["port_security"]) < 2 {
-			continue
-		}
-		ports = append(ports, ret["name"][0])
-		for _, address := range ret["port_security"][1:] {
+	appendAddresses := func(addresses []string) {
+		for _, address := range addresses {
 			if strings.Contains(address, ":") {
 				v6s = append(v6s, address)
 			} else {
@@ -382,7 +375,33 @@ func (c *Controller) syncSgLogicalPort(key string) error {
 			}
 		}
 	}
+	for _, ret := range results {
+		if len(ret["port_security"]) < 2 {
+			continue
+		}
+		ports = append(ports, ret["name"][0])
+		appendAddresses(ret["port_security"][1:])
+	}
 
+	// ports of pods selected by the security group selectors
+	selected, err := c.getSgSelectedPorts(sg)
+	if err != nil {
+		klog.Errorf("failed to get ports selected by sg %s, %v", key, err)
+		return err
+	}
+	for port, addresses := range selected {
+		if util.ContainsString(ports, port) {
+			continue
+		}
+		ports = append(ports, port)
+		appendAddresses(addresses)
+	}
+
+	oldPorts, err := c.ovnClient.GetPortGroupPorts(sg.Status.PortGroup)
+	if err != nil {
+		klog.Errorf("failed to get ports of port group %s, %v", sg.Status.PortGroup, err)
+		return err
+	}
 	if err = c.ovnLegacyClient.SetPortsToPortGroup(sg.Status.PortGroup, ports); err != nil {
 		klog.Errorf("failed to set port to sg, %v", err)
 		return err
@@ -395,10 +414,143 @@ func (c *Controller) syncSgLogicalPort(key string) error {
 		klog.Errorf("failed to set address_set, %v", err)
 		return err
 	}
-	c.addOrUpdateSgQueue.Add(util.DenyAllSecurityGroup)
+
+	if sg.Status.BoundPorts != len(ports) {
+		newSg := sg.DeepCopy()
+		newSg.Status.BoundPorts = len(ports)
+		c.patchSgStatus(newSg)
+	}
+	if len(util.DiffStringSlice(oldPorts, ports)) != 0 {
+		c.addOrUpdateSgQueue.Add(util.DenyAllSecurityGroup)
+	}
 	return nil
 }
 
+func sgHasSelector(sg *kubeovnv1.SecurityGroup) bool {
+	return sg.Spec.PodSelector != nil || sg.Spec.NamespaceSelector != nil
+}
+
+// sgSelectors returns the namespace and pod selectors of the security group,
+// a nil selector of the spec selects everything
+func sgSelectors(sg *kubeovnv1.SecurityGroup) (labels.Selector, labels.Selector, error) {
+	nsSel, podSel := labels.Everything(), labels.Everything()
+	var err error
+	if sg.Spec.NamespaceSelector != nil {
+		if nsSel, err = metav1.LabelSelectorAsSelector(sg.Spec.NamespaceSelector); err != nil {
+			return nil, nil, fmt.Errorf("invalid namespace selector of sg %s, %v", sg.Name, err)
+		}
+	}
+	if sg.Spec.PodSelector != nil {
+		if podSel, err = metav1.LabelSelectorAsSelector(sg.Spec.PodSelector); err != nil {
+			return nil, nil, fmt.Errorf("invalid pod selector of sg %s, %v", sg.Name, err)
+		}
+	}
+	return nsSel, podSel, nil
+}
+
+func isPodMatchSg(pod *corev1.Pod, podNs *corev1.Namespace, sg *kubeovnv1.SecurityGroup) bool {
+	if !sgHasSelector(sg) {
+		return false
+	}
+	nsSel, podSel, err := sgSelectors(sg)
+	if err != nil {
+		return false
+	}
+	return nsSel.Matches(labels.Set(podNs.Labels)) && podSel.Matches(labels.Set(pod.Labels))
+}
+
+// getSgSelectedPorts returns the logical ports and their addresses of pods selected by the security group
+func (c *Controller) getSgSelectedPorts(sg *kubeovnv1.SecurityGroup) (map[string][]string, error) {
+	ports := map[string][]string{}
+	if !sgHasSelector(sg) {
+		return ports, nil
+	}
+
+	nsSel, podSel, err := sgSelectors(sg)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	namespaces, err := c.namespacesLister.List(nsSel)
+	if err != nil {
+		klog.Errorf("failed to list namespaces, %v", err)
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		pods, err := c.podsLister.Pods(ns.Name).List(podSel)
+		if err != nil {
+			klog.Errorf("failed to list pods in namespace %s, %v", ns.Name, err)
+			return nil, err
+		}
+		for _, pod := range pods {
+			if !isPodAlive(pod) || pod.Spec.HostNetwork {
+				continue
+			}
+			podNets, err := c.getPodKubeovnNets(pod)
+			if err != nil {
+				klog.Errorf("failed to get pod nets of %s/%s, %v", pod.Namespace, pod.Name, err)
+				return nil, err
+			}
+			podName := c.getNameByPod(pod)
+			for _, podNet := range podNets {
+				if !isOvnSubnet(podNet.Subnet) ||
+					pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] != "true" {
+					continue
+				}
+				portName := ovs.PodNameToPortName(podName, pod.Namespace, podNet.ProviderName)
+				ipStr := pod.Annotations[fmt.Sprintf(util.IpAddressAnnotationTemplate, podNet.ProviderName)]
+				if ipStr == "" {
+					ports[portName] = nil
+				} else {
+					ports[portName] = strings.Split(ipStr, ",")
+				}
+			}
+		}
+	}
+	return ports, nil
+}
+
+func (c *Controller) podMatchSecurityGroups(pod *corev1.Pod) []string {
+	podNs, err := c.namespacesLister.Get(pod.Namespace)
+	if err != nil {
+		return nil
+	}
+	sgs, err := c.sgsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list security groups, %v", err)
+		return nil
+	}
+	var match []string
+	for _, sg := range sgs {
+		if isPodMatchSg(pod, podNs, sg) {
+			match = append(match, sg.Name)
+		}
+	}
+	return match
+}
+
+// enqueueSelectorSgs syncs ports of the security groups whose namespace selectors match
+// the old or the new labels of a namespace
+func (c *Controller) enqueueSelectorSgs(oldNs, newNs *corev1.Namespace) {
+	sgs, err := c.sgsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list security groups, %v", err)
+		return
+	}
+	for _, sg := range sgs {
+		if sg.Spec.NamespaceSelector == nil {
+			continue
+		}
+		nsSel, err := metav1.LabelSelectorAsSelector(sg.Spec.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		if nsSel.Matches(labels.Set(oldNs.Labels)) || nsSel.Matches(labels.Set(newNs.Labels)) {
+			c.syncSgPortsQueue.Add(sg.Name)
+		}
+	}
+}
+
 func (c *Controller) getPortSg(port *ovnnb.LogicalSwitchPort) ([]string, error) {
 	var sgList []string
 	for key, value := range port.ExternalIDs {
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func TestIsPodMatchSg(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "shop", Labels: map[string]string{"app": "web"}}}
+	podNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "shop"}}}
+
+	tests := []struct {
+		name string
+		spec kubeovnv1.SecurityGroupSpec
+		want bool
+	}{
+		{
+			name: "no selector",
+			want: false,
+		},
+		{
+			name: "pod selector matched in any namespace",
+			spec: kubeovnv1.SecurityGroupSpec{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+			want: true,
+		},
+		{
+			name: "pod selector not matched",
+			spec: kubeovnv1.SecurityGroupSpec{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+			want: false,
+		},
+		{
+			name: "namespace selector selects all pods in the namespace",
+			spec: kubeovnv1.SecurityGroupSpec{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shop"}}},
+			want: true,
+		},
+		{
+			name: "both selectors matched",
+			spec: kubeovnv1.SecurityGroupSpec{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shop"}},
+			},
+			want: true,
+		},
+		{
+			name: "namespace selector not matched",
+			spec: kubeovnv1.SecurityGroupSpec{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "billing"}},
+			},
+			want: false,
+		},
+		{
+			name: "empty pod selector selects all pods",
+			spec: kubeovnv1.SecurityGroupSpec{PodSelector: &metav1.LabelSelector{}},
+			want: true,
+		},
+		{
+			name: "invalid selector",
+			spec: kubeovnv1.SecurityGroupSpec{PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}}}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sg := &kubeovnv1.SecurityGroup{ObjectMeta: metav1.ObjectMeta{Name: "sg"}, Spec: tt.spec}
+			if got := isPodMatchSg(pod, podNs, sg); got != tt.want {
+				t.Errorf("isPodMatchSg() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -14,7 +15,6 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
-	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
@@ -49,6 +49,11 @@ func (c *Controller) enqueueAddService(obj interface{}) {
 		}
 	}
 
+	// services of SwitchLBRules with static backends only have no endpoint slices
+	if _, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]; ok {
+		c.updateEndpointQueue.Add(key)
+	}
+
 	if c.config.EnableLbSvc {
 		klog.V(3).Infof("enqueue add service %s", key)
 		c.addServiceQueue.Add(key)
@@ -63,7 +68,7 @@ func (c *Controller) enqueueDeleteService(obj interface{}) {
 	//klog.V(3).Infof("enqueue delete service %s/%s", svc.Namespace, svc.Name)
 	klog.Infof("enqueue delete service %s/%s", svc.Namespace, svc.Name)
 
-	vip, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]
+	vips, ok := slrVips(svc)
 	if ok || svc.Spec.ClusterIP != v1.ClusterIPNone && svc.Spec.ClusterIP != "" {
 
 		if c.config.EnableNP {
@@ -79,20 +84,22 @@ func (c *Controller) enqueueDeleteService(obj interface{}) {
 			}
 		}
 
-		ip := svc.Spec.ClusterIP
+		ips := []string{svc.Spec.ClusterIP}
 		if ok {
-			ip = vip
+			ips = vips
 		}
 
-		for _, port := range svc.Spec.Ports {
-			vpcSvc := &vpcService{
-				Vip:      fmt.Sprintf("%s:%d", ip, port.Port),
-				Protocol: port.Protocol,
-				Vpc:      svc.Annotations[util.VpcAnnotation],
-				Svc:      svc,
+		for _, ip := range ips {
+			for _, port := range svc.Spec.Ports {
+				vpcSvc := &vpcService{
+					Vip:      util.JoinHostPort(ip, port.Port),
+					Protocol: port.Protocol,
+					Vpc:      svc.Annotations[util.VpcAnnotation],
+					Svc:      svc,
+				}
+				klog.Infof("delete vpc service %v", vpcSvc)
+				c.deleteServiceQueue.Add(vpcSvc)
 			}
-			klog.Infof("delete vpc service %v", vpcSvc)
-			c.deleteServiceQueue.Add(vpcSvc)
 		}
 	}
 }
@@ -115,6 +122,19 @@ func (c *Controller) enqueueUpdateService(old, new interface{}) {
 	}
 	klog.V(3).Infof("enqueue update service %s", key)
 	c.updateServiceQueue.Add(key)
+	if c.nativeLbSvcEnabled() && (oldSvc.Spec.Type != newSvc.Spec.Type ||
+		oldSvc.Spec.LoadBalancerIP != newSvc.Spec.LoadBalancerIP ||
+		!reflect.DeepEqual(oldSvc.Spec.LoadBalancerClass, newSvc.Spec.LoadBalancerClass) ||
+		!reflect.DeepEqual(oldSvc.Spec.IPFamilies, newSvc.Spec.IPFamilies) ||
+		oldSvc.Annotations[util.LbIPPoolAnnotation] != newSvc.Annotations[util.LbIPPoolAnnotation]) {
+		c.addServiceQueue.Add(key)
+	}
+	if serviceHealthCheckChanged(oldSvc, newSvc) ||
+		!reflect.DeepEqual(oldSvc.Spec.InternalTrafficPolicy, newSvc.Spec.InternalTrafficPolicy) ||
+		oldSvc.Annotations[v1.AnnotationTopologyAwareHints] != newSvc.Annotations[v1.AnnotationTopologyAwareHints] ||
+		oldSvc.Annotations[util.SwitchLBRuleBackendsAnnotation] != newSvc.Annotations[util.SwitchLBRuleBackendsAnnotation] {
+		c.updateEndpointQueue.Add(key)
+	}
 }
 
 func (c *Controller) runAddServiceWorker() {
@@ -236,29 +256,27 @@ func (c *Controller) handleDeleteService(service *vpcService) error {
 		}
 	}
 
-	vpcLbConfig := c.GenVpcLoadBalancer(service.Vpc)
+	vpcLbs, err := c.listVpcLoadBalancers(service.Vpc)
+	if err != nil {
+		return err
+	}
 	vip := service.Vip
-	if service.Protocol == v1.ProtocolTCP {
-		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, vpcLbConfig.TcpLoadBalancer); err != nil {
-			klog.Errorf("failed to delete vip %s from tcp lb, %v", vip, err)
-			return err
-		}
-		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, vpcLbConfig.TcpSessLoadBalancer); err != nil {
-			klog.Errorf("failed to delete vip %s from tcp session lb, %v", vip, err)
-			return err
-		}
-	} else {
-		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, vpcLbConfig.UdpLoadBalancer); err != nil {
-			klog.Errorf("failed to delete vip %s from udp lb, %v", vip, err)
-			return err
-		}
-		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, vpcLbConfig.UdpSessLoadBalancer); err != nil {
-			klog.Errorf("failed to delete vip %s from udp session lb, %v", vip, err)
+	for _, lb := range vpcLbs[service.Protocol] {
+		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, lb); err != nil {
+			klog.Errorf("failed to delete vip %s from %s lb %s, %v", vip, strings.ToLower(string(service.Protocol)), lb, err)
 			return err
 		}
 	}
 
-	if service.Svc.Spec.Type == v1.ServiceTypeLoadBalancer && c.config.EnableLbSvc {
+	if c.nativeLbSvcEnabled() {
+		// the ips are owned by the new service if it has been recreated
+		if _, err = c.servicesLister.Services(service.Svc.Namespace).Get(service.Svc.Name); k8serrors.IsNotFound(err) {
+			if _, err = c.releaseLbSvcIPs(service.Svc); err != nil {
+				klog.Errorf("failed to release ips of service %s, %v", service.Svc.Name, err)
+				return err
+			}
+		}
+	} else if service.Svc.Spec.Type == v1.ServiceTypeLoadBalancer && c.config.EnableLbSvc {
 		if err := c.deleteLbSvc(service.Svc); err != nil {
 			klog.Errorf("failed to delete service %s, %v", service.Svc.Name, err)
 			return err
@@ -283,18 +301,24 @@ func (c *Controller) handleUpdateService(key string) error {
 		return err
 	}
 
-	ip := ""
-	if vip, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]; ok {
-		ip = vip
-	} else {
-		ip = svc.Spec.ClusterIP
-		if ip == "" || ip == v1.ClusterIPNone {
+	ips, ok := slrVips(svc)
+	if !ok {
+		ips = svc.Spec.ClusterIPs
+		if len(ips) == 0 && svc.Spec.ClusterIP != "" {
+			ips = []string{svc.Spec.ClusterIP}
+		}
+		if len(ips) == 0 || ips[0] == v1.ClusterIPNone {
 			return nil
 		}
 	}
 
-	tcpVips := []string{}
-	udpVips := []string{}
+	if _, enabled := serviceHealthCheckOptions(svc); !enabled {
+		if err = c.ovnLegacyClient.DeleteServiceHealthChecks(key); err != nil {
+			klog.Errorf("failed to delete health checks of service %s, %v", key, err)
+			return err
+		}
+	}
+
 	vpcName := svc.Annotations[util.VpcAnnotation]
 	if vpcName == "" {
 		vpcName = util.DefaultVpc
@@ -305,96 +329,124 @@ func (c *Controller) handleUpdateService(key string) error {
 		return err
 	}
 
-	tcpLb, udpLb := vpc.Status.TcpLoadBalancer, vpc.Status.UdpLoadBalancer
-	oTcpLb, oUdpLb := vpc.Status.TcpSessionLoadBalancer, vpc.Status.UdpSessionLoadBalancer
-	if svc.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
-		tcpLb, udpLb = vpc.Status.TcpSessionLoadBalancer, vpc.Status.UdpSessionLoadBalancer
-		oTcpLb, oUdpLb = vpc.Status.TcpLoadBalancer, vpc.Status.UdpLoadBalancer
-	}
-
-	for _, port := range svc.Spec.Ports {
-		if port.Protocol == v1.ProtocolTCP {
-			if util.CheckProtocol(ip) == kubeovnv1.ProtocolIPv6 {
-				tcpVips = append(tcpVips, fmt.Sprintf("[%s]:%d", ip, port.Port))
-			} else {
-				tcpVips = append(tcpVips, fmt.Sprintf("%s:%d", ip, port.Port))
-			}
-		} else if port.Protocol == v1.ProtocolUDP {
-			if util.CheckProtocol(ip) == kubeovnv1.ProtocolIPv6 {
-				udpVips = append(udpVips, fmt.Sprintf("[%s]:%d", ip, port.Port))
-			} else {
-				udpVips = append(udpVips, fmt.Sprintf("%s:%d", ip, port.Port))
-			}
-		}
-	}
-	// for service update
-	lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(tcpLb)
+	lbs, err := c.getServiceLoadBalancers(svc, vpc)
 	if err != nil {
-		klog.Errorf("failed to get lb %v", err)
+		klog.Errorf("failed to get load balancers of service %s, %v", key, err)
 		return err
 	}
-	vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
+	// the vips may have been moved from any other load balancer of the vpc
+	vpcLbs, err := c.listVpcLoadBalancers(vpc.Name)
 	if err != nil {
-		klog.Errorf("failed to get tcp lb vips %v", err)
 		return err
 	}
-	klog.V(3).Infof("exist tcp vips are %v", vips)
-	for _, vip := range tcpVips {
-		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, oTcpLb); err != nil {
-			klog.Errorf("failed to delete lb %s form %s, %v", vip, oTcpLb, err)
+
+	svcVips := make(map[v1.Protocol][]string, len(lbProtocols))
+	for _, ip := range ips {
+		for _, port := range svc.Spec.Ports {
+			svcVips[port.Protocol] = append(svcVips[port.Protocol], util.JoinHostPort(ip, port.Port))
+		}
+	}
+
+	// for service update
+	for _, protocol := range lbProtocols {
+		proto, lb := strings.ToLower(string(protocol)), lbs[protocol]
+		lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(lb)
+		if err != nil {
+			klog.Errorf("failed to get lb %v", err)
 			return err
 		}
-		if _, ok := vips[vip]; !ok {
-			klog.Infof("add vip %s to tcp lb %s", vip, oTcpLb)
-			c.updateEndpointQueue.Add(key)
-			break
+		if lbUuid == "" {
+			continue
+		}
+		vips, err := c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
+		if err != nil {
+			klog.Errorf("failed to get %s lb vips %v", proto, err)
+			return err
+		}
+		klog.V(3).Infof("exist %s vips are %v", proto, vips)
+		for _, vip := range svcVips[protocol] {
+			for _, oLb := range vpcLbs[protocol] {
+				if oLb == lb {
+					continue
+				}
+				if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, oLb); err != nil {
+					klog.Errorf("failed to delete lb %s form %s, %v", vip, oLb, err)
+					return err
+				}
+			}
+			if _, ok := vips[vip]; !ok {
+				klog.Infof("add vip %s to %s lb %s", vip, proto, lb)
+				c.updateEndpointQueue.Add(key)
+				break
+			}
 		}
-	}
 
-	for vip := range vips {
-		if parseVipAddr(vip) == ip && !util.IsStringIn(vip, tcpVips) {
-			klog.Infof("remove stall vip %s", vip)
-			err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, tcpLb)
-			if err != nil {
-				klog.Errorf("failed to delete vip %s from tcp lb %v", vip, err)
-				return err
+		for vip := range vips {
+			if util.IsStringIn(parseVipAddr(vip), ips) && !util.IsStringIn(vip, svcVips[protocol]) {
+				klog.Infof("remove stall vip %s", vip)
+				if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, lb); err != nil {
+					klog.Errorf("failed to delete vip %s from %s lb %v", vip, proto, err)
+					return err
+				}
 			}
 		}
 	}
 
-	lbUuid, err = c.ovnLegacyClient.FindLoadbalancer(udpLb)
+	return nil
+}
+
+// listVpcLoadBalancers returns all load balancers of the vpc grouped by protocol
+func (c *Controller) listVpcLoadBalancers(vpcName string) (map[v1.Protocol][]string, error) {
+	if vpcName == "" {
+		vpcName = util.DefaultVpc
+	}
+	affinityLbs, err := c.ovnLegacyClient.ListVpcLoadBalancers(vpcName)
 	if err != nil {
-		klog.Errorf("failed to get lb %v", err)
-		return err
+		return nil, err
+	}
+
+	vpcLbs := []*VpcLoadBalancer{c.GenVpcLoadBalancer(vpcName)}
+	if c.nativeLbSvcEnabled() {
+		vpcLbs = append(vpcLbs, c.genLbSvcVpcLoadBalancer(vpcName))
+	}
+	lbs := make(map[v1.Protocol][]string, len(lbProtocols))
+	for _, protocol := range lbProtocols {
+		for _, vpcLb := range vpcLbs {
+			lb, sessionLb := vpcLb.protocolLoadBalancers(protocol)
+			lbs[protocol] = append(lbs[protocol], lb, sessionLb)
+			for _, affinityLb := range affinityLbs {
+				// dedicated session load balancers and template load balancers
+				if strings.HasPrefix(affinityLb, sessionLb+"-") || strings.HasPrefix(affinityLb, lb+"-") {
+					lbs[protocol] = append(lbs[protocol], affinityLb)
+				}
+			}
+		}
 	}
-	vips, err = c.ovnLegacyClient.GetLoadBalancerVips(lbUuid)
+	return lbs, nil
+}
+
+// deleteServiceVips removes the vips of the service from all load balancers of the vpc,
+// including the last vip of a load balancer which DeleteLoadBalancerVip keeps
+func (c *Controller) deleteServiceVips(svc *v1.Service, lbIPs []string, vpcName string) error {
+	vpcLbs, err := c.listVpcLoadBalancers(vpcName)
 	if err != nil {
-		klog.Errorf("failed to get udp lb vips %v", err)
 		return err
 	}
-	klog.Infof("exist udp vips are %v", vips)
-	for _, vip := range udpVips {
-		if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, oUdpLb); err != nil {
-			klog.Errorf("failed to delete lb %s form %s, %v", vip, oUdpLb, err)
-			return err
-		}
-		if _, ok := vips[vip]; !ok {
-			klog.Infof("add vip %s to udp lb %s", vip, oUdpLb)
-			c.updateEndpointQueue.Add(key)
-			break
-		}
-	}
 
-	for vip := range vips {
-		if parseVipAddr(vip) == ip && !util.IsStringIn(vip, udpVips) {
-			klog.Infof("remove stall vip %s", vip)
-			if err := c.ovnLegacyClient.DeleteLoadBalancerVip(vip, udpLb); err != nil {
-				klog.Errorf("failed to delete vip %s from udp lb %v", vip, err)
-				return err
+	for _, port := range svc.Spec.Ports {
+		for _, ip := range lbIPs {
+			vip := util.JoinHostPort(ip, port.Port)
+			for _, lb := range vpcLbs[port.Protocol] {
+				if err = c.ovnLegacyClient.RemoveLoadBalancerVip(lb, vip); err != nil {
+					klog.Errorf("failed to delete vip %s from lb %s, %v", vip, lb, err)
+					return err
+				}
+				if err = c.ovnLegacyClient.DeleteLoadBalancerHealthCheck(lb, vip); err != nil {
+					return err
+				}
 			}
 		}
 	}
-
 	return nil
 }
 
@@ -421,6 +473,9 @@ func (c *Controller) handleAddService(key string) error {
 		}
 		return err
 	}
+	if c.nativeLbSvcEnabled() {
+		return c.handleAddNativeLbSvc(svc)
+	}
 	if svc.Spec.Type != v1.ServiceTypeLoadBalancer || !c.config.EnableLbSvc {
 		return nil
 	}
@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	ovnipam "github.com/kubeovn/kube-ovn/pkg/ipam"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// nativeLbSvcEnabled returns whether load balancer services are implemented by ovn load balancers
+// with ips allocated from LoadBalancerIPPools
+func (c *Controller) nativeLbSvcEnabled() bool {
+	return c.config.EnableLb && c.config.EnableLbSvc && c.config.LbSvcMode == util.LbSvcModeNative
+}
+
+// isNativeLbSvc returns whether the service is a load balancer service not claimed by other implementations
+func isNativeLbSvc(svc *v1.Service) bool {
+	return svc.Spec.Type == v1.ServiceTypeLoadBalancer && svc.Spec.LoadBalancerClass == nil
+}
+
+func lbSvcIPAMKey(svc *v1.Service) string {
+	return fmt.Sprintf("svc/%s/%s", svc.Namespace, svc.Name)
+}
+
+func lbSvcIngressIPs(svc *v1.Service) []string {
+	ips := make([]string, 0, len(svc.Status.LoadBalancer.Ingress))
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			ips = append(ips, ingress.IP)
+		}
+	}
+	return ips
+}
+
+// genLbSvcVpcLoadBalancer returns the load balancers serving the ingress ips of load balancer services in the vpc.
+// They are in the load balancer group of the vpc, so that traffic from the external network to the ingress ips
+// is load balanced on the join switch of the nodes, or on the gateway of the vpc router if it has one.
+func (c *Controller) genLbSvcVpcLoadBalancer(vpcName string) *VpcLoadBalancer {
+	vpcLb := c.GenVpcLoadBalancer(vpcName)
+	return &VpcLoadBalancer{
+		TcpLoadBalancer:      "lb-svc-" + vpcLb.TcpLoadBalancer,
+		TcpSessLoadBalancer:  "lb-svc-" + vpcLb.TcpSessLoadBalancer,
+		UdpLoadBalancer:      "lb-svc-" + vpcLb.UdpLoadBalancer,
+		UdpSessLoadBalancer:  "lb-svc-" + vpcLb.UdpSessLoadBalancer,
+		SctpLoadBalancer:     "lb-svc-" + vpcLb.SctpLoadBalancer,
+		SctpSessLoadBalancer: "lb-svc-" + vpcLb.SctpSessLoadBalancer,
+		LoadBalancerGroup:    vpcLb.LoadBalancerGroup,
+	}
+}
+
+// initLbSvcLoadBalancers creates the load balancers for ingress ips of the vpc and adds them to the load balancer group
+func (c *Controller) initLbSvcLoadBalancers(vpcName string) error {
+	vpcLb := c.genLbSvcVpcLoadBalancer(vpcName)
+	if err := c.createVpcLoadBalancers(vpcLb); err != nil {
+		return err
+	}
+
+	lbs := vpcLb.loadBalancers()
+	if err := c.ovnLegacyClient.AddLoadBalancersToGroup(vpcLb.LoadBalancerGroup, lbs...); err != nil {
+		klog.Errorf("failed to add load balancers to group %s: %v", vpcLb.LoadBalancerGroup, err)
+		return err
+	}
+	// previous versions attached the load balancers to the vpc router one by one
+	if err := c.ovnLegacyClient.MigrateLoadBalancersToGroup(vpcLb.LoadBalancerGroup, lbs...); err != nil {
+		klog.Errorf("failed to migrate load balancers to group %s: %v", vpcLb.LoadBalancerGroup, err)
+		return err
+	}
+	return nil
+}
+
+// getLbSvcLoadBalancers returns the load balancers the ingress vips of the service belong to by protocol
+func (c *Controller) getLbSvcLoadBalancers(svc *v1.Service, vpc *kubeovnv1.Vpc) (map[v1.Protocol]string, error) {
+	return c.getVpcServiceLoadBalancers(svc, vpc, c.genLbSvcVpcLoadBalancer(vpc.Name))
+}
+
+// handleAddNativeLbSvc allocates ingress ips for the load balancer service from LoadBalancerIPPools,
+// the ingress vips are programmed to ovn load balancers on endpoint update
+func (c *Controller) handleAddNativeLbSvc(svc *v1.Service) error {
+	key := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+	if !isNativeLbSvc(svc) {
+		// the ingress of services claimed by other implementations is left to them,
+		// only the ips allocated from the pools before are released and removed
+		released, err := c.releaseLbSvcIPs(svc)
+		if err != nil {
+			return err
+		}
+		ingress := removeLbIngressIPs(svc.Status.LoadBalancer.Ingress, released)
+		if len(ingress) == len(svc.Status.LoadBalancer.Ingress) {
+			return nil
+		}
+		newSvc := svc.DeepCopy()
+		newSvc.Status.LoadBalancer.Ingress = ingress
+		if _, err = c.config.KubeClient.CoreV1().Services(svc.Namespace).UpdateStatus(context.Background(), newSvc, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("failed to remove released ips %v from ingress of service %s: %v", released, key, err)
+			return err
+		}
+		return nil
+	}
+	klog.Infof("add load balancer svc %s", key)
+
+	ips, err := c.acquireLbSvcIPs(svc)
+	if err != nil {
+		klog.Errorf("failed to allocate load balancer ip for service %s: %v", key, err)
+		c.recorder.Eventf(svc, v1.EventTypeWarning, "AllocateLoadBalancerIPFailed", err.Error())
+		return err
+	}
+
+	ingress := lbSvcIngress(ips, svc.Spec.IPFamilies)
+	if !reflect.DeepEqual(svc.Status.LoadBalancer.Ingress, ingress) {
+		oldIPs := lbSvcIngressIPs(svc)
+		var staleIPs []string
+		for _, ip := range oldIPs {
+			if !util.ContainsString(ips, ip) {
+				staleIPs = append(staleIPs, ip)
+			}
+		}
+		if len(staleIPs) != 0 {
+			if err = c.deleteServiceVips(svc, staleIPs, svc.Annotations[util.VpcAnnotation]); err != nil {
+				klog.Errorf("failed to delete stale ingress vips of service %s: %v", key, err)
+				return err
+			}
+		}
+
+		newSvc := svc.DeepCopy()
+		newSvc.Status.LoadBalancer.Ingress = ingress
+		if _, err = c.config.KubeClient.CoreV1().Services(svc.Namespace).UpdateStatus(context.Background(), newSvc, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("update service %s status failed: %v", key, err)
+			return err
+		}
+		c.enqueueLbIPPoolsStatus(append(oldIPs, ips...)...)
+	}
+
+	c.updateEndpointQueue.Add(key)
+	return nil
+}
+
+// acquireLbSvcIPs returns the ips allocated to the service, the ips are reallocated if the pool
+// they belong to is no longer available to the service or the requested load balancer ip changes
+func (c *Controller) acquireLbSvcIPs(svc *v1.Service) ([]string, error) {
+	key := lbSvcIPAMKey(svc)
+	pools, err := c.listLbIPPools(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	if addresses := c.ipam.GetPodAddress(key); len(addresses) != 0 {
+		ips := make([]string, 0, len(addresses))
+		for _, address := range addresses {
+			ips = append(ips, address.Ip)
+		}
+		valid := svc.Spec.LoadBalancerIP == "" || util.ContainsString(ips, svc.Spec.LoadBalancerIP)
+		if valid {
+			valid = false
+			for _, pool := range pools {
+				if lbIPPoolIPAMName(pool.Name) == addresses[0].Subnet.Name {
+					valid = true
+					break
+				}
+			}
+		}
+		if valid {
+			return ips, nil
+		}
+		klog.Infof("release ips %v of service %s/%s", ips, svc.Namespace, svc.Name)
+		c.ipam.ReleaseAddressByPod(key)
+	}
+
+	if len(pools) == 0 {
+		return nil, fmt.Errorf("no load balancer ip pool available for service %s/%s", svc.Namespace, svc.Name)
+	}
+
+	if ip := svc.Spec.LoadBalancerIP; ip != "" {
+		for _, pool := range pools {
+			if !util.CIDRContainIP(pool.Spec.CIDRBlock, ip) {
+				continue
+			}
+			v4IP, v6IP, _, err := c.ipam.GetStaticAddress(key, key, ip, "", lbIPPoolIPAMName(pool.Name), true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to allocate ip %s from load balancer ip pool %s: %v", ip, pool.Name, err)
+			}
+			return nonEmptyIPs(v4IP, v6IP), nil
+		}
+		return nil, fmt.Errorf("load balancer ip %s does not belong to any load balancer ip pool available for the service", ip)
+	}
+
+	for _, pool := range pools {
+		v4IP, v6IP, _, err := c.ipam.GetRandomAddress(key, key, "", lbIPPoolIPAMName(pool.Name), nil, true)
+		if err != nil {
+			if errors.Is(err, ovnipam.ErrNoAvailable) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to allocate ip from load balancer ip pool %s: %v", pool.Name, err)
+		}
+		return nonEmptyIPs(v4IP, v6IP), nil
+	}
+	return nil, fmt.Errorf("no available ip in load balancer ip pools for service %s/%s", svc.Namespace, svc.Name)
+}
+
+// lbSvcIngress returns the ingress of the allocated ips, ips of dual stack pools are allocated in pairs,
+// only those of the service ip families are used
+func lbSvcIngress(ips []string, families []v1.IPFamily) []v1.LoadBalancerIngress {
+	var ingress []v1.LoadBalancerIngress
+	for _, ip := range ips {
+		if len(families) == 0 {
+			ingress = append(ingress, v1.LoadBalancerIngress{IP: ip})
+			continue
+		}
+		protocol := util.CheckProtocol(ip)
+		for _, family := range families {
+			if string(family) == protocol {
+				ingress = append(ingress, v1.LoadBalancerIngress{IP: ip})
+				break
+			}
+		}
+	}
+	return ingress
+}
+
+// removeLbIngressIPs returns the ingress without the ips, hostname ingress is kept
+func removeLbIngressIPs(ingress []v1.LoadBalancerIngress, ips []string) []v1.LoadBalancerIngress {
+	result := make([]v1.LoadBalancerIngress, 0, len(ingress))
+	for _, item := range ingress {
+		if item.IP != "" && util.ContainsString(ips, item.IP) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func nonEmptyIPs(ips ...string) []string {
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if ip != "" {
+			result = append(result, ip)
+		}
+	}
+	return result
+}
+
+// releaseLbSvcIPs removes the ingress vips of the service and releases the ips allocated to it,
+// the released ips are returned
+func (c *Controller) releaseLbSvcIPs(svc *v1.Service) ([]string, error) {
+	key := lbSvcIPAMKey(svc)
+	addresses := c.ipam.GetPodAddress(key)
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	ips := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		ips = append(ips, address.Ip)
+	}
+
+	if err := c.deleteServiceVips(svc, ips, svc.Annotations[util.VpcAnnotation]); err != nil {
+		klog.Errorf("failed to delete ingress vips of service %s/%s: %v", svc.Namespace, svc.Name, err)
+		return nil, err
+	}
+	klog.Infof("release ips %v of service %s/%s", ips, svc.Namespace, svc.Name)
+	c.ipam.ReleaseAddressByPod(key)
+	c.enqueueLbIPPoolsStatus(ips...)
+	return ips, nil
+}
+
+// enqueueNativeLbSvcs enqueues all load balancer services to reconcile their ingress ips
+func (c *Controller) enqueueNativeLbSvcs() {
+	svcs, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services: %v", err)
+		return
+	}
+	for _, svc := range svcs {
+		if isNativeLbSvc(svc) {
+			c.addServiceQueue.Add(fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
+		}
+	}
+}
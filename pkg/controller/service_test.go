@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+)
+
+// fakeOvnNbCtl installs an ovn-nbctl which keeps the vips of each load balancer in a file of the state directory,
+// it only removes vips and returns nothing for all other commands
+func fakeOvnNbCtl(t *testing.T, lbVips map[string][]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	state := filepath.Join(dir, "lb")
+	if err := os.Mkdir(state, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for lb, vips := range lbVips {
+		if err := os.WriteFile(filepath.Join(state, lb), []byte(strings.Join(vips, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --*) shift ;;
+    *) break ;;
+  esac
+done
+if [ "$1" = remove ] && [ "$2" = load_balancer ] && [ "$4" = vips ]; then
+  f="%s/$3"
+  vip=$(echo "$5" | tr -d '"')
+  if [ -f "$f" ]; then
+    grep -vxF "$vip" "$f" > "$f.new" || true
+    mv "$f.new" "$f"
+  fi
+fi
+`, state)
+	if err := os.WriteFile(filepath.Join(dir, ovs.OvnNbCtl), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return state
+}
+
+func TestDeleteServiceVips(t *testing.T) {
+	state := fakeOvnNbCtl(t, map[string][]string{
+		"cluster-tcp-loadbalancer":         {"10.96.0.10:80"},
+		"cluster-tcp-session-loadbalancer": {"10.96.0.10:80", "10.96.0.11:80"},
+	})
+	c := &Controller{
+		config: &Configuration{
+			ClusterRouter:                  "ovn-cluster",
+			ClusterTcpLoadBalancer:         "cluster-tcp-loadbalancer",
+			ClusterTcpSessionLoadBalancer:  "cluster-tcp-session-loadbalancer",
+			ClusterUdpLoadBalancer:         "cluster-udp-loadbalancer",
+			ClusterUdpSessionLoadBalancer:  "cluster-udp-session-loadbalancer",
+			ClusterSctpLoadBalancer:        "cluster-sctp-loadbalancer",
+			ClusterSctpSessionLoadBalancer: "cluster-sctp-session-loadbalancer",
+		},
+		ovnLegacyClient: &ovs.LegacyClient{OvnTimeout: 60},
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80}}},
+	}
+
+	if err := c.deleteServiceVips(svc, []string{"10.96.0.10"}, ""); err != nil {
+		t.Fatalf("deleteServiceVips() error = %v", err)
+	}
+	for lb, want := range map[string]string{
+		// the only vip of a load balancer is removed as well
+		"cluster-tcp-loadbalancer":         "",
+		"cluster-tcp-session-loadbalancer": "10.96.0.11:80",
+	} {
+		got, err := os.ReadFile(filepath.Join(state, lb))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(got)) != want {
+			t.Errorf("vips of %s = %q, want %q", lb, strings.TrimSpace(string(got)), want)
+		}
+	}
+}
+
+func TestDeleteServiceVipsOfCustomVpc(t *testing.T) {
+	// the only service of the vpc has been moved to another vpc
+	state := fakeOvnNbCtl(t, map[string][]string{
+		"vpc-test-tcp-load":  {"10.96.0.10:80"},
+		"vpc-other-tcp-load": {"10.96.0.20:80"},
+	})
+	c := &Controller{
+		config:          &Configuration{},
+		ovnLegacyClient: &ovs.LegacyClient{OvnTimeout: 60},
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80}}},
+	}
+
+	if err := c.deleteServiceVips(svc, []string{"10.96.0.10"}, "test"); err != nil {
+		t.Fatalf("deleteServiceVips() error = %v", err)
+	}
+	for lb, want := range map[string]string{
+		"vpc-test-tcp-load":  "",
+		"vpc-other-tcp-load": "10.96.0.20:80",
+	} {
+		got, err := os.ReadFile(filepath.Join(state, lb))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(got)) != want {
+			t.Errorf("vips of %s = %q, want %q", lb, strings.TrimSpace(string(got)), want)
+		}
+	}
+}
@@ -676,7 +676,7 @@ func (c *Controller) handleAddOrUpdateSubnet(key string) error {
 	}
 
 	if c.config.EnableLb && subnet.Name != c.config.NodeSwitch {
-		if err := c.ovnLegacyClient.AddLbToLogicalSwitch(vpc.Status.TcpLoadBalancer, vpc.Status.TcpSessionLoadBalancer, vpc.Status.UdpLoadBalancer, vpc.Status.UdpSessionLoadBalancer, subnet.Name); err != nil {
+		if err := c.ovnLegacyClient.AddLoadBalancerGroupToLogicalSwitch(c.GenVpcLoadBalancer(vpc.Name).LoadBalancerGroup, subnet.Name); err != nil {
 			c.patchSubnetStatus(subnet, "AddLbToLogicalSwitchFailed", err.Error())
 			return err
 		}
@@ -764,6 +764,18 @@ func (c *Controller) handleDeleteLogicalSwitch(key string) (err error) {
 		return err
 	}
 
+	hcVip, err := c.virtualIpsLister.Get(genHealthCheckVipName(key))
+	if err != nil && !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to get health check vip of subnet %s, %v", key, err)
+		return err
+	}
+	if err == nil && isHealthCheckVip(hcVip, key) {
+		if err = c.config.KubeOvnClient.KubeovnV1().Vips().Delete(context.Background(), hcVip.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to delete health check vip %s, %v", hcVip.Name, err)
+			return err
+		}
+	}
+
 	nss, err := c.namespacesLister.List(labels.Everything())
 	if err != nil {
 		klog.Errorf("failed to list namespaces, %v", err)
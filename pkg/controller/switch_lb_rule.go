@@ -3,7 +3,9 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -142,6 +144,11 @@ func (c *Controller) handleAddOrUpdateSwitchLBRule(key string) error {
 		}
 		return err
 	}
+	if err = validateSwitchLBRule(slr); err != nil {
+		klog.Errorf("invalid SwitchLBRule %s: %v", key, err)
+		c.recorder.Eventf(slr, corev1.EventTypeWarning, "ValidateSwitchLBRuleFailed", err.Error())
+		return nil
+	}
 
 	needToCreate := false
 	name := genSvcName(slr.Name)
@@ -181,6 +188,9 @@ func (c *Controller) handleAddOrUpdateSwitchLBRule(key string) error {
 		formatPorts = fmt.Sprintf("%s,%d/%s", formatPorts, port.Port, protocol)
 	}
 	newSlr.Status.Ports = strings.TrimPrefix(formatPorts, ",")
+	if newSlr.Spec.HealthCheck == nil {
+		newSlr.Status.BackendHealth = nil
+	}
 
 	_, err = c.config.KubeOvnClient.KubeovnV1().SwitchLBRules().UpdateStatus(context.Background(), newSlr, metav1.UpdateOptions{})
 	if err != nil {
@@ -255,6 +265,12 @@ func genHeadlessService(slr *kubeovnv1.SwitchLBRule, oldSvc *corev1.Service) *co
 		resourceVersion = oldSvc.ResourceVersion
 	}
 	annotations[util.SwitchLBRuleVipsAnnotation] = slr.Spec.Vip
+	if len(slr.Spec.Backends) != 0 {
+		annotations[util.SwitchLBRuleBackendsAnnotation] = formatSlrBackends(slr.Spec.Backends)
+	} else {
+		delete(annotations, util.SwitchLBRuleBackendsAnnotation)
+	}
+	setSlrHealthCheckAnnotations(annotations, slr.Spec.HealthCheck)
 
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -271,5 +287,142 @@ func genHeadlessService(slr *kubeovnv1.SwitchLBRule, oldSvc *corev1.Service) *co
 			SessionAffinity: corev1.ServiceAffinity(slr.Spec.SessionAffinity),
 		},
 	}
+	if svc.Spec.SessionAffinity == corev1.ServiceAffinityClientIP && slr.Spec.SessionAffinityTimeout > 0 {
+		timeout := slr.Spec.SessionAffinityTimeout
+		svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+		}
+	}
 	return svc
 }
+
+// maxSlrBackendWeight caps backend weights since each backend is repeated weight times in the OVN load balancer
+const maxSlrBackendWeight = 100
+
+func validateSwitchLBRule(slr *kubeovnv1.SwitchLBRule) error {
+	vips := strings.Split(slr.Spec.Vip, ",")
+	if len(vips) > 2 {
+		return fmt.Errorf("at most one ipv4 and one ipv6 vip are allowed, got %s", slr.Spec.Vip)
+	}
+	for _, vip := range vips {
+		if net.ParseIP(vip) == nil {
+			return fmt.Errorf("invalid vip %s", vip)
+		}
+	}
+	if len(vips) == 2 && util.CheckProtocol(slr.Spec.Vip) != kubeovnv1.ProtocolDual {
+		return fmt.Errorf("dual stack vips %s must be of different ip families", slr.Spec.Vip)
+	}
+	for _, port := range slr.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = string(corev1.ProtocolTCP)
+		}
+		if port.TargetProtocol != "" && !strings.EqualFold(port.TargetProtocol, protocol) {
+			return fmt.Errorf("target protocol %s of port %s differs from protocol %s, which is not supported by ovn load balancers", port.TargetProtocol, port.Name, protocol)
+		}
+	}
+	if len(slr.Spec.Selector) == 0 && len(slr.Spec.Backends) == 0 {
+		return fmt.Errorf("either selector or backends must be specified")
+	}
+	for _, backend := range slr.Spec.Backends {
+		if net.ParseIP(backend.IP) == nil {
+			return fmt.Errorf("invalid backend ip %s", backend.IP)
+		}
+		if backend.Weight < 0 || backend.Weight > maxSlrBackendWeight {
+			return fmt.Errorf("invalid weight %d of backend %s, must be in the range 1 to %d", backend.Weight, backend.IP, maxSlrBackendWeight)
+		}
+	}
+	return nil
+}
+
+// slrVips returns the vips of the service created for a SwitchLBRule
+func slrVips(svc *corev1.Service) ([]string, bool) {
+	vip, ok := svc.Annotations[util.SwitchLBRuleVipsAnnotation]
+	if !ok {
+		return nil, false
+	}
+	return strings.Split(vip, ","), true
+}
+
+// formatSlrBackends formats static backends as ip=weight separated by comma
+func formatSlrBackends(backends []kubeovnv1.SlrBackend) string {
+	items := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		weight := backend.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		items = append(items, fmt.Sprintf("%s=%d", backend.IP, weight))
+	}
+	return strings.Join(items, ",")
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// getSlrBackends returns the static backends of the service port in the same ip family with the vip.
+// OVN load balancers select backends evenly, so each backend is repeated in proportion to its weight.
+func getSlrBackends(svc *corev1.Service, port corev1.ServicePort, vip string) []string {
+	value := svc.Annotations[util.SwitchLBRuleBackendsAnnotation]
+	if value == "" {
+		return nil
+	}
+
+	targetPort := port.Port
+	if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0 {
+		targetPort = port.TargetPort.IntVal
+	}
+
+	protocol := util.CheckProtocol(vip)
+	var ips []string
+	var weights []int
+	divisor := 0
+	for _, item := range strings.Split(value, ",") {
+		ip, weightStr, _ := strings.Cut(item, "=")
+		if util.CheckProtocol(ip) != protocol {
+			continue
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil || weight <= 0 {
+			weight = 1
+		} else if weight > maxSlrBackendWeight {
+			weight = maxSlrBackendWeight
+		}
+		ips = append(ips, ip)
+		weights = append(weights, weight)
+		divisor = gcd(divisor, weight)
+	}
+
+	var backends []string
+	for i, ip := range ips {
+		backend := util.JoinHostPort(ip, targetPort)
+		for j := 0; j < weights[i]/divisor; j++ {
+			backends = append(backends, backend)
+		}
+	}
+	return backends
+}
+
+// updateSlrBackends records the backends the vips of a SwitchLBRule are resolved to
+func (c *Controller) updateSlrBackends(name, svcKey string, backends []kubeovnv1.SlrVipBackends) {
+	slr, err := c.switchLBRuleLister.Get(name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to get SwitchLBRule %s, %v", name, err)
+		}
+		return
+	}
+	if slr.Status.Service != svcKey || reflect.DeepEqual(slr.Status.Backends, backends) {
+		return
+	}
+
+	newSlr := slr.DeepCopy()
+	newSlr.Status.Backends = backends
+	if _, err = c.config.KubeOvnClient.KubeovnV1().SwitchLBRules().UpdateStatus(context.Background(), newSlr, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("failed to update backends of SwitchLBRule %s, %v", name, err)
+	}
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func TestValidateSwitchLBRule(t *testing.T) {
+	ports := []kubeovnv1.SlrPort{{Name: "http", Port: 80, TargetPort: 8080, Protocol: "TCP"}}
+	backends := []kubeovnv1.SlrBackend{{IP: "10.0.0.2", Weight: 2}}
+	tests := []struct {
+		name    string
+		spec    kubeovnv1.SwitchLBRuleSpec
+		wantErr bool
+	}{
+		{
+			name: "selector",
+			spec: kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Selector: []string{"app:nginx"}, Ports: ports},
+		},
+		{
+			name: "dual stack vips with backends",
+			spec: kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1,fd00::1", Backends: backends, Ports: ports},
+		},
+		{
+			name:    "dual stack vips of the same family",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1,10.0.0.3", Backends: backends, Ports: ports},
+			wantErr: true,
+		},
+		{
+			name:    "too many vips",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1,fd00::1,10.0.0.3", Backends: backends, Ports: ports},
+			wantErr: true,
+		},
+		{
+			name:    "invalid vip",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.256", Backends: backends, Ports: ports},
+			wantErr: true,
+		},
+		{
+			name:    "no selector or backends",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Ports: ports},
+			wantErr: true,
+		},
+		{
+			name:    "invalid backend ip",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Backends: []kubeovnv1.SlrBackend{{IP: "backend"}}, Ports: ports},
+			wantErr: true,
+		},
+		{
+			name:    "negative weight",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Backends: []kubeovnv1.SlrBackend{{IP: "10.0.0.2", Weight: -1}}, Ports: ports},
+			wantErr: true,
+		},
+		{
+			name:    "weight too large",
+			spec:    kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Backends: []kubeovnv1.SlrBackend{{IP: "10.0.0.2", Weight: 101}}, Ports: ports},
+			wantErr: true,
+		},
+		{
+			name: "same target protocol",
+			spec: kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Backends: backends,
+				Ports: []kubeovnv1.SlrPort{{Name: "dns", Port: 53, Protocol: "UDP", TargetProtocol: "UDP"}}},
+		},
+		{
+			name: "default protocol with tcp target protocol",
+			spec: kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Backends: backends,
+				Ports: []kubeovnv1.SlrPort{{Name: "http", Port: 80, TargetProtocol: "TCP"}}},
+		},
+		{
+			name: "different target protocol",
+			spec: kubeovnv1.SwitchLBRuleSpec{Vip: "10.0.0.1", Backends: backends,
+				Ports: []kubeovnv1.SlrPort{{Name: "dns", Port: 53, Protocol: "UDP", TargetProtocol: "TCP"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slr := &kubeovnv1.SwitchLBRule{ObjectMeta: metav1.ObjectMeta{Name: "slr"}, Spec: tt.spec}
+			if err := validateSwitchLBRule(slr); (err != nil) != tt.wantErr {
+				t.Errorf("validateSwitchLBRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatSlrBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []kubeovnv1.SlrBackend
+		want     string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "default weight",
+			backends: []kubeovnv1.SlrBackend{{IP: "10.0.0.2"}, {IP: "fd00::2"}},
+			want:     "10.0.0.2=1,fd00::2=1",
+		},
+		{
+			name:     "weights",
+			backends: []kubeovnv1.SlrBackend{{IP: "10.0.0.2", Weight: 3}, {IP: "10.0.0.3", Weight: 1}},
+			want:     "10.0.0.2=3,10.0.0.3=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSlrBackends(tt.backends); got != tt.want {
+				t.Errorf("formatSlrBackends() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGcd(t *testing.T) {
+	tests := []struct {
+		a, b, want int
+	}{
+		{0, 0, 0},
+		{0, 4, 4},
+		{4, 0, 4},
+		{4, 6, 2},
+		{6, 4, 2},
+		{3, 5, 1},
+		{10, 10, 10},
+	}
+	for _, tt := range tests {
+		if got := gcd(tt.a, tt.b); got != tt.want {
+			t.Errorf("gcd(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGetSlrBackends(t *testing.T) {
+	port := corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}
+	tests := []struct {
+		name     string
+		backends string
+		port     corev1.ServicePort
+		vip      string
+		want     []string
+	}{
+		{
+			name: "no backends",
+			port: port,
+			vip:  "10.0.0.1",
+		},
+		{
+			name:     "equal weights",
+			backends: "10.0.0.2=1,10.0.0.3=1",
+			port:     port,
+			vip:      "10.0.0.1",
+			want:     []string{"10.0.0.2:8080", "10.0.0.3:8080"},
+		},
+		{
+			name:     "weights reduced by gcd",
+			backends: "10.0.0.2=4,10.0.0.3=2",
+			port:     port,
+			vip:      "10.0.0.1",
+			want:     []string{"10.0.0.2:8080", "10.0.0.2:8080", "10.0.0.3:8080"},
+		},
+		{
+			name:     "coprime weights",
+			backends: "10.0.0.2=3,10.0.0.3=2",
+			port:     port,
+			vip:      "10.0.0.1",
+			want:     []string{"10.0.0.2:8080", "10.0.0.2:8080", "10.0.0.2:8080", "10.0.0.3:8080", "10.0.0.3:8080"},
+		},
+		{
+			name:     "invalid weight defaults to 1",
+			backends: "10.0.0.2=x,10.0.0.3=0",
+			port:     port,
+			vip:      "10.0.0.1",
+			want:     []string{"10.0.0.2:8080", "10.0.0.3:8080"},
+		},
+		{
+			name:     "weight capped at 100",
+			backends: "10.0.0.2=200,10.0.0.3=50",
+			port:     port,
+			vip:      "10.0.0.1",
+			want:     []string{"10.0.0.2:8080", "10.0.0.2:8080", "10.0.0.3:8080"},
+		},
+		{
+			name:     "backends of the vip family",
+			backends: "10.0.0.2=2,fd00::2=1,fd00::3=3",
+			port:     port,
+			vip:      "fd00::1",
+			want:     []string{"[fd00::2]:8080", "[fd00::3]:8080", "[fd00::3]:8080", "[fd00::3]:8080"},
+		},
+		{
+			name:     "no target port",
+			backends: "10.0.0.2=1",
+			port:     corev1.ServicePort{Port: 80},
+			vip:      "10.0.0.1",
+			want:     []string{"10.0.0.2:80"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tt.backends != "" {
+				svc.Annotations[util.SwitchLBRuleBackendsAnnotation] = tt.backends
+			}
+			if got := getSlrBackends(svc, tt.port, tt.vip); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getSlrBackends() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
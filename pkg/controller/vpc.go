@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -62,6 +63,7 @@ func (c *Controller) enqueueUpdateVpc(old, new interface{}) {
 		!reflect.DeepEqual(oldVpc.Spec.StaticRoutes, newVpc.Spec.StaticRoutes) ||
 		!reflect.DeepEqual(oldVpc.Spec.PolicyRoutes, newVpc.Spec.PolicyRoutes) ||
 		!reflect.DeepEqual(oldVpc.Spec.VpcPeerings, newVpc.Spec.VpcPeerings) ||
+		!reflect.DeepEqual(oldVpc.Status.LearnedRoutes, newVpc.Status.LearnedRoutes) ||
 		!reflect.DeepEqual(oldVpc.Annotations, newVpc.Annotations) {
 		klog.V(3).Infof("enqueue update vpc %s", key)
 		c.addOrUpdateVpcQueue.Add(key)
@@ -229,91 +231,132 @@ func (c *Controller) reconcileRouterPortBySubnet(vpc *kubeovnv1.Vpc, subnet *kub
 }
 
 type VpcLoadBalancer struct {
-	TcpLoadBalancer     string
-	TcpSessLoadBalancer string
-	UdpLoadBalancer     string
-	UdpSessLoadBalancer string
+	TcpLoadBalancer      string
+	TcpSessLoadBalancer  string
+	UdpLoadBalancer      string
+	UdpSessLoadBalancer  string
+	SctpLoadBalancer     string
+	SctpSessLoadBalancer string
+	// LoadBalancerGroup contains all load balancers of the vpc and is referenced by the logical switches of the vpc
+	LoadBalancerGroup string
+}
+
+// protocolLoadBalancers returns the load balancer and the session load balancer for the protocol
+func (lb *VpcLoadBalancer) protocolLoadBalancers(protocol v1.Protocol) (string, string) {
+	switch protocol {
+	case v1.ProtocolTCP:
+		return lb.TcpLoadBalancer, lb.TcpSessLoadBalancer
+	case v1.ProtocolUDP:
+		return lb.UdpLoadBalancer, lb.UdpSessLoadBalancer
+	case v1.ProtocolSCTP:
+		return lb.SctpLoadBalancer, lb.SctpSessLoadBalancer
+	}
+	return "", ""
 }
 
 func (c *Controller) GenVpcLoadBalancer(vpcKey string) *VpcLoadBalancer {
 	if vpcKey == util.DefaultVpc || vpcKey == "" {
 		return &VpcLoadBalancer{
-			TcpLoadBalancer:     c.config.ClusterTcpLoadBalancer,
-			TcpSessLoadBalancer: c.config.ClusterTcpSessionLoadBalancer,
-			UdpLoadBalancer:     c.config.ClusterUdpLoadBalancer,
-			UdpSessLoadBalancer: c.config.ClusterUdpSessionLoadBalancer,
+			TcpLoadBalancer:      c.config.ClusterTcpLoadBalancer,
+			TcpSessLoadBalancer:  c.config.ClusterTcpSessionLoadBalancer,
+			UdpLoadBalancer:      c.config.ClusterUdpLoadBalancer,
+			UdpSessLoadBalancer:  c.config.ClusterUdpSessionLoadBalancer,
+			SctpLoadBalancer:     c.config.ClusterSctpLoadBalancer,
+			SctpSessLoadBalancer: c.config.ClusterSctpSessionLoadBalancer,
+			LoadBalancerGroup:    fmt.Sprintf("%s-lb-group", c.config.ClusterRouter),
 		}
 	} else {
 		return &VpcLoadBalancer{
-			TcpLoadBalancer:     fmt.Sprintf("vpc-%s-tcp-load", vpcKey),
-			TcpSessLoadBalancer: fmt.Sprintf("vpc-%s-tcp-sess-load", vpcKey),
-			UdpLoadBalancer:     fmt.Sprintf("vpc-%s-udp-load", vpcKey),
-			UdpSessLoadBalancer: fmt.Sprintf("vpc-%s-udp-sess-load", vpcKey),
+			TcpLoadBalancer:      fmt.Sprintf("vpc-%s-tcp-load", vpcKey),
+			TcpSessLoadBalancer:  fmt.Sprintf("vpc-%s-tcp-sess-load", vpcKey),
+			UdpLoadBalancer:      fmt.Sprintf("vpc-%s-udp-load", vpcKey),
+			UdpSessLoadBalancer:  fmt.Sprintf("vpc-%s-udp-sess-load", vpcKey),
+			SctpLoadBalancer:     fmt.Sprintf("vpc-%s-sctp-load", vpcKey),
+			SctpSessLoadBalancer: fmt.Sprintf("vpc-%s-sctp-sess-load", vpcKey),
+			LoadBalancerGroup:    fmt.Sprintf("vpc-%s-lb-group", vpcKey),
 		}
 	}
 }
 
-func (c *Controller) addLoadBalancer(vpc string) (*VpcLoadBalancer, error) {
-	vpcLbConfig := c.GenVpcLoadBalancer(vpc)
-
-	tcpLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLbConfig.TcpLoadBalancer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find tcp lb %v", err)
-	}
-	if tcpLb == "" {
-		klog.Infof("init cluster tcp load balancer %s", vpcLbConfig.TcpLoadBalancer)
-		err := c.ovnLegacyClient.CreateLoadBalancer(vpcLbConfig.TcpLoadBalancer, util.ProtocolTCP, "")
-		if err != nil {
-			klog.Errorf("failed to create cluster tcp load balancer %v", err)
-			return nil, err
-		}
-	} else {
-		klog.Infof("tcp load balancer %s exists", tcpLb)
+// syncRouterLoadBalancerGroup makes the load balancer group of the vpc apply to the vpc router as well.
+// Ovn only applies load balancers of gateway routers and routers with distributed gateway ports,
+// so the group is detached from other routers to keep the nb database consistent with what takes effect.
+func (c *Controller) syncRouterLoadBalancerGroup(vpcName, router string) error {
+	lbg := c.GenVpcLoadBalancer(vpcName).LoadBalancerGroup
+	lbgUuid, err := c.ovnLegacyClient.FindLoadBalancerGroup(lbg)
+	if err != nil || lbgUuid == "" {
+		// the group will be synced once the load balancers of the vpc are initialized
+		return err
 	}
-
-	tcpSessionLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLbConfig.TcpSessLoadBalancer)
+	hasGateway, err := c.ovnLegacyClient.LogicalRouterHasGateway(router)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find tcp session lb %v", err)
+		return err
 	}
-	if tcpSessionLb == "" {
-		klog.Infof("init cluster tcp session load balancer %s", vpcLbConfig.TcpSessLoadBalancer)
-		err := c.ovnLegacyClient.CreateLoadBalancer(vpcLbConfig.TcpSessLoadBalancer, util.ProtocolTCP, "ip_src")
-		if err != nil {
-			klog.Errorf("failed to create cluster tcp session load balancer %v", err)
-			return nil, err
+	if hasGateway {
+		if err = c.ovnLegacyClient.AddLoadBalancerGroupToLogicalRouter(lbg, router); err != nil {
+			klog.Errorf("failed to add load balancer group %s to router %s: %v", lbg, router, err)
+			return err
 		}
-	} else {
-		klog.Infof("tcp session load balancer %s exists", tcpSessionLb)
+		return nil
+	}
+	if err = c.ovnLegacyClient.RemoveLoadBalancerGroupFromLogicalRouter(lbg, router); err != nil {
+		klog.Errorf("failed to remove load balancer group %s from router %s: %v", lbg, router, err)
+		return err
 	}
+	return nil
+}
 
-	udpLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLbConfig.UdpLoadBalancer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find udp lb %v", err)
+// loadBalancers returns the load balancers and session load balancers of all protocols
+func (lb *VpcLoadBalancer) loadBalancers() []string {
+	lbs := make([]string, 0, 2*len(lbProtocols))
+	for _, protocol := range lbProtocols {
+		l, sl := lb.protocolLoadBalancers(protocol)
+		lbs = append(lbs, l, sl)
 	}
-	if udpLb == "" {
-		klog.Infof("init cluster udp load balancer %s", vpcLbConfig.UdpLoadBalancer)
-		err := c.ovnLegacyClient.CreateLoadBalancer(vpcLbConfig.UdpLoadBalancer, util.ProtocolUDP, "")
-		if err != nil {
-			klog.Errorf("failed to create cluster udp load balancer %v", err)
-			return nil, err
+	return lbs
+}
+
+// createVpcLoadBalancers creates the load balancers and session load balancers of all protocols if not exist
+func (c *Controller) createVpcLoadBalancers(vpcLb *VpcLoadBalancer) error {
+	for _, protocol := range lbProtocols {
+		proto := strings.ToLower(string(protocol))
+		lb, sessionLb := vpcLb.protocolLoadBalancers(protocol)
+		for _, name := range []string{lb, sessionLb} {
+			lbUuid, err := c.ovnLegacyClient.FindLoadbalancer(name)
+			if err != nil {
+				return fmt.Errorf("failed to find %s lb %s: %v", proto, name, err)
+			}
+			if lbUuid != "" {
+				klog.Infof("%s load balancer %s exists", proto, name)
+				continue
+			}
+
+			var selectFields string
+			if name == sessionLb {
+				selectFields = "ip_src"
+			}
+			klog.Infof("init %s load balancer %s", proto, name)
+			if err = c.ovnLegacyClient.CreateLoadBalancer(name, proto, selectFields); err != nil {
+				klog.Errorf("failed to create %s load balancer %s: %v", proto, name, err)
+				return err
+			}
+		}
+		if err := c.ovnLegacyClient.SetLoadBalancerAffinityTimeout(sessionLb, v1.DefaultClientIPServiceAffinitySeconds); err != nil {
+			klog.Errorf("failed to set affinity timeout of session load balancer %s: %v", sessionLb, err)
+			return err
 		}
-	} else {
-		klog.Infof("udp load balancer %s exists", udpLb)
 	}
+	return nil
+}
 
-	udpSessionLb, err := c.ovnLegacyClient.FindLoadbalancer(vpcLbConfig.UdpSessLoadBalancer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find udp session lb %v", err)
+func (c *Controller) addLoadBalancer(vpc string) (*VpcLoadBalancer, error) {
+	vpcLbConfig := c.GenVpcLoadBalancer(vpc)
+	if err := c.createVpcLoadBalancers(vpcLbConfig); err != nil {
+		return nil, err
 	}
-	if udpSessionLb == "" {
-		klog.Infof("init cluster udp session load balancer %s", vpcLbConfig.UdpSessLoadBalancer)
-		err := c.ovnLegacyClient.CreateLoadBalancer(vpcLbConfig.UdpSessLoadBalancer, util.ProtocolUDP, "ip_src")
-		if err != nil {
-			klog.Errorf("failed to create cluster udp session load balancer %v", err)
-			return nil, err
-		}
-	} else {
-		klog.Infof("udp session load balancer %s exists", udpSessionLb)
+	if err := c.ovnLegacyClient.AddLoadBalancersToGroup(vpcLbConfig.LoadBalancerGroup, vpcLbConfig.loadBalancers()...); err != nil {
+		klog.Errorf("failed to init load balancer group %s: %v", vpcLbConfig.LoadBalancerGroup, err)
+		return nil, err
 	}
 
 	return vpcLbConfig, nil
@@ -372,20 +415,41 @@ func (c *Controller) handleAddOrUpdateVpc(key string) error {
 			return err
 		}
 
-		routeNeedDel, routeNeedAdd, err := diffStaticRoute(existRoute, vpc.Spec.StaticRoutes)
+		speakerNodes, err := c.speakerNodes()
+		if err != nil {
+			return err
+		}
+		learnedRoutes := learnedStaticRoutes(activeLearnedRoutes(vpc.Status.LearnedRoutes, speakerNodes))
+		targetRoutes := make([]*kubeovnv1.StaticRoute, 0, len(vpc.Spec.StaticRoutes)+len(learnedRoutes))
+		targetRoutes = append(targetRoutes, vpc.Spec.StaticRoutes...)
+		targetRoutes = append(targetRoutes, learnedRoutes...)
+		routeNeedDel, routeNeedAdd, err := diffStaticRoute(existRoute, targetRoutes)
 		if err != nil {
 			klog.Errorf("failed to diff vpc %s static route, %v", vpc.Name, err)
 			return err
 		}
+		deletedCIDRs := make(map[string]bool, len(routeNeedDel))
 		for _, item := range routeNeedDel {
 			if err = c.ovnLegacyClient.DeleteStaticRoute(item.CIDR, vpc.Name); err != nil {
 				klog.Errorf("del vpc %s static route failed, %v", vpc.Name, err)
 				return err
 			}
+			deletedCIDRs[item.CIDR] = true
+		}
+		// routes of the deleted prefixes to other next hops are deleted as well
+		for _, item := range targetRoutes {
+			if deletedCIDRs[item.CIDR] && !containsStaticRoute(routeNeedAdd, item) {
+				routeNeedAdd = append(routeNeedAdd, item)
+			}
 		}
 
 		for _, item := range routeNeedAdd {
-			if err = c.ovnLegacyClient.AddStaticRoute(convertPolicy(item.Policy), item.CIDR, item.NextHopIP, vpc.Name, util.NormalRouteType); err != nil {
+			routeType := util.NormalRouteType
+			if containsStaticRoute(learnedRoutes, item) {
+				// a prefix may be learned from several next hops
+				routeType = util.EcmpRouteType
+			}
+			if err = c.ovnLegacyClient.AddStaticRoute(convertPolicy(item.Policy), item.CIDR, item.NextHopIP, vpc.Name, routeType); err != nil {
 				klog.Errorf("add static route to vpc %s failed, %v", vpc.Name, err)
 				return err
 			}
@@ -425,10 +489,21 @@ func (c *Controller) handleAddOrUpdateVpc(key string) error {
 		if err != nil {
 			return err
 		}
+		if err = c.syncRouterLoadBalancerGroup(key, key); err != nil {
+			return err
+		}
+		if c.nativeLbSvcEnabled() {
+			if err = c.initLbSvcLoadBalancers(key); err != nil {
+				klog.Errorf("failed to init load balancers for load balancer services of vpc %s: %v", key, err)
+				return err
+			}
+		}
 		vpc.Status.TcpLoadBalancer = vpcLb.TcpLoadBalancer
 		vpc.Status.TcpSessionLoadBalancer = vpcLb.TcpSessLoadBalancer
 		vpc.Status.UdpLoadBalancer = vpcLb.UdpLoadBalancer
 		vpc.Status.UdpSessionLoadBalancer = vpcLb.UdpSessLoadBalancer
+		vpc.Status.SctpLoadBalancer = vpcLb.SctpLoadBalancer
+		vpc.Status.SctpSessionLoadBalancer = vpcLb.SctpSessLoadBalancer
 	}
 	bytes, err := vpc.Status.Bytes()
 	if err != nil {
@@ -439,7 +514,15 @@ func (c *Controller) handleAddOrUpdateVpc(key string) error {
 		return err
 	}
 
-	if len(vpc.Annotations) != 0 && strings.ToLower(vpc.Annotations[util.VpcLbAnnotation]) == "on" {
+	if c.nativeLbSvcEnabled() {
+		// ovn load balancers of the vpc serve both cluster ips and ingress ips, the vpc lb pod is not needed
+		if len(vpc.Annotations) != 0 && strings.ToLower(vpc.Annotations[util.VpcLbAnnotation]) == "on" {
+			klog.Infof("ignore annotation %s of vpc %s as load balancer services are served by ovn load balancers", util.VpcLbAnnotation, vpc.Name)
+		}
+		if err = c.deleteVpcLb(vpc); err != nil {
+			return err
+		}
+	} else if len(vpc.Annotations) != 0 && strings.ToLower(vpc.Annotations[util.VpcLbAnnotation]) == "on" {
 		if err = c.createVpcLb(vpc); err != nil {
 			return err
 		}
@@ -528,6 +611,77 @@ func diffStaticRoute(exist []*ovs.StaticRoute, target []*kubeovnv1.StaticRoute)
 	return
 }
 
+// speakerAppLabel is the app label of kube-ovn-speaker pods
+const speakerAppLabel = "kube-ovn-speaker"
+
+// speakerNodes returns the existing nodes with an alive speaker pod
+func (c *Controller) speakerNodes() (map[string]bool, error) {
+	pods, err := c.podsLister.Pods(c.config.PodNamespace).List(labels.SelectorFromSet(labels.Set{"app": speakerAppLabel}))
+	if err != nil {
+		klog.Errorf("failed to list speaker pods, %v", err)
+		return nil, err
+	}
+	nodes := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || !isPodAlive(pod) {
+			continue
+		}
+		if _, err = c.nodesLister.Get(pod.Spec.NodeName); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			klog.Errorf("failed to get node %s, %v", pod.Spec.NodeName, err)
+			return nil, err
+		}
+		nodes[pod.Spec.NodeName] = true
+	}
+	return nodes, nil
+}
+
+// activeLearnedRoutes returns the learned routes of the nodes still running a speaker,
+// the speaker of a node only updates its own routes and routes of the other nodes stay otherwise
+func activeLearnedRoutes(routes []*kubeovnv1.LearnedRoute, speakerNodes map[string]bool) []*kubeovnv1.LearnedRoute {
+	active := make([]*kubeovnv1.LearnedRoute, 0, len(routes))
+	for _, route := range routes {
+		if speakerNodes[route.Node] {
+			active = append(active, route)
+		}
+	}
+	return active
+}
+
+// learnedStaticRoutes returns the static routes of the routes learned by the speakers
+func learnedStaticRoutes(learnedRoutes []*kubeovnv1.LearnedRoute) []*kubeovnv1.StaticRoute {
+	var routes []*kubeovnv1.StaticRoute
+	keys := make(map[string]bool, len(learnedRoutes))
+	for _, item := range learnedRoutes {
+		if util.CheckProtocol(item.CIDR) != util.CheckProtocol(item.NextHopIP) {
+			continue
+		}
+		route := &kubeovnv1.StaticRoute{
+			Policy:    kubeovnv1.PolicyDst,
+			CIDR:      item.CIDR,
+			NextHopIP: item.NextHopIP,
+		}
+		// the same route may be learned by several speakers
+		if key := getStaticRouteItemKey(route); !keys[key] {
+			keys[key] = true
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func containsStaticRoute(routes []*kubeovnv1.StaticRoute, route *kubeovnv1.StaticRoute) bool {
+	key := getStaticRouteItemKey(route)
+	for _, item := range routes {
+		if getStaticRouteItemKey(item) == key {
+			return true
+		}
+	}
+	return false
+}
+
 func getStaticRouteItemKey(item *kubeovnv1.StaticRoute) (key string) {
 	if item.Policy == kubeovnv1.PolicyDst {
 		return fmt.Sprintf("dst:%s=>%s", item.CIDR, item.NextHopIP)
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func TestActiveLearnedRoutes(t *testing.T) {
+	routes := []*kubeovnv1.LearnedRoute{
+		{Node: "node1", Source: "bgp-peer", CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.10"},
+		{Node: "node2", Source: "bgp-peer", CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.10"},
+		{Node: "node3", Source: "bgp-peer", CIDR: "192.168.101.0/24", NextHopIP: "10.0.1.11"},
+	}
+	tests := []struct {
+		name         string
+		speakerNodes map[string]bool
+		want         []*kubeovnv1.LearnedRoute
+	}{
+		{
+			name:         "all speakers running",
+			speakerNodes: map[string]bool{"node1": true, "node2": true, "node3": true},
+			want:         routes,
+		},
+		{
+			name:         "node deleted or speaker stopped",
+			speakerNodes: map[string]bool{"node1": true},
+			want:         routes[:1],
+		},
+		{
+			name: "no speakers",
+			want: []*kubeovnv1.LearnedRoute{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := activeLearnedRoutes(routes, tt.speakerNodes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("activeLearnedRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLearnedStaticRoutes(t *testing.T) {
+	routes := []*kubeovnv1.LearnedRoute{
+		{Node: "node1", CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.10"},
+		// learned by another speaker
+		{Node: "node2", CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.10"},
+		{Node: "node2", CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.11"},
+		// next hop of another family
+		{Node: "node1", CIDR: "fd00:100::/64", NextHopIP: "10.0.1.10"},
+	}
+	want := []*kubeovnv1.StaticRoute{
+		{Policy: kubeovnv1.PolicyDst, CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.10"},
+		{Policy: kubeovnv1.PolicyDst, CIDR: "192.168.100.0/24", NextHopIP: "10.0.1.11"},
+	}
+	if got := learnedStaticRoutes(routes); !reflect.DeepEqual(got, want) {
+		t.Errorf("learnedStaticRoutes() = %v, want %v", got, want)
+	}
+}
@@ -51,6 +51,9 @@ type Controller struct {
 	nodesLister listerv1.NodeLister
 	nodesSynced cache.InformerSynced
 
+	servicesLister listerv1.ServiceLister
+	servicesSynced cache.InformerSynced
+
 	htbQosLister kubeovnlister.HtbQosLister
 	htbQosSynced cache.InformerSynced
 
@@ -72,6 +75,7 @@ func NewController(config *Configuration, podInformerFactory informers.SharedInf
 	subnetInformer := kubeovnInformerFactory.Kubeovn().V1().Subnets()
 	podInformer := podInformerFactory.Core().V1().Pods()
 	nodeInformer := nodeInformerFactory.Core().V1().Nodes()
+	serviceInformer := nodeInformerFactory.Core().V1().Services()
 	htbQosInformer := kubeovnInformerFactory.Kubeovn().V1().HtbQoses()
 
 	controller := &Controller{
@@ -93,6 +97,9 @@ func NewController(config *Configuration, podInformerFactory informers.SharedInf
 		nodesLister: nodeInformer.Lister(),
 		nodesSynced: nodeInformer.Informer().HasSynced,
 
+		servicesLister: serviceInformer.Lister(),
+		servicesSynced: serviceInformer.Informer().HasSynced,
+
 		htbQosLister: htbQosInformer.Lister(),
 		htbQosSynced: htbQosInformer.Informer().HasSynced,
 
@@ -658,7 +665,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	go wait.Until(rotateLog, 1*time.Hour, stopCh)
 	go wait.Until(c.operateMod, 10*time.Second, stopCh)
 
-	if ok := cache.WaitForCacheSync(stopCh, c.providerNetworksSynced, c.subnetsSynced, c.podsSynced, c.nodesSynced, c.htbQosSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.providerNetworksSynced, c.subnetsSynced, c.podsSynced, c.nodesSynced, c.servicesSynced, c.htbQosSynced); !ok {
 		klog.Fatalf("failed to wait for caches to sync")
 		return
 	}
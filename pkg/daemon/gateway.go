@@ -136,6 +136,57 @@ func (c *Controller) getServicesCIDR(protocol string) []string {
 	return ret
 }
 
+// getLocalTrafficPolicyServices returns ip,protocol:port members of services with external traffic policy
+// set to local, including node ports on this node, external ips and load balancer ingress ips
+func (c *Controller) getLocalTrafficPolicyServices(protocol string) ([]string, error) {
+	svcs, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services: %v", err)
+		return nil, err
+	}
+	node, err := c.nodesLister.Get(c.config.NodeName)
+	if err != nil {
+		klog.Errorf("failed to get node %s: %v", c.config.NodeName, err)
+		return nil, err
+	}
+	nodeIPv4, nodeIPv6 := util.GetNodeInternalIP(*node)
+	nodeIP := nodeIPv4
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		nodeIP = nodeIPv6
+	}
+
+	var ret []string
+	for _, svc := range svcs {
+		if svc.Spec.ExternalTrafficPolicy != v1.ServiceExternalTrafficPolicyTypeLocal {
+			continue
+		}
+
+		ips := make([]string, 0, len(svc.Spec.ExternalIPs)+len(svc.Status.LoadBalancer.Ingress))
+		ips = append(ips, svc.Spec.ExternalIPs...)
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				ips = append(ips, ingress.IP)
+			}
+		}
+		for _, port := range svc.Spec.Ports {
+			// ipset hash:ip,port only supports tcp and udp here
+			if port.Protocol != v1.ProtocolTCP && port.Protocol != v1.ProtocolUDP {
+				continue
+			}
+			proto := strings.ToLower(string(port.Protocol))
+			if port.NodePort != 0 && nodeIP != "" {
+				ret = append(ret, fmt.Sprintf("%s,%s:%d", nodeIP, proto, port.NodePort))
+			}
+			for _, ip := range ips {
+				if util.CheckProtocol(ip) == protocol {
+					ret = append(ret, fmt.Sprintf("%s,%s:%d", ip, proto, port.Port))
+				}
+			}
+		}
+	}
+	return ret, nil
+}
+
 func (c *Controller) getDefaultVpcSubnetsCIDR(protocol string) ([]string, error) {
 	subnets, err := c.subnetsLister.List(labels.Everything())
 	if err != nil {
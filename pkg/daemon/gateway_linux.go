@@ -29,6 +29,7 @@ const (
 	SubnetNatSet           = "subnets-nat"
 	SubnetDistributedGwSet = "subnets-distributed-gw"
 	LocalPodSet            = "local-pod-ip-nat"
+	LocalTrafficPolicySet  = "local-traffic-policy"
 	OtherNodeSet           = "other-node"
 	IPSetPrefix            = "ovn"
 )
@@ -83,6 +84,11 @@ func (c *Controller) setIPSet() error {
 			klog.Errorf("failed to get node, %+v", err)
 			return err
 		}
+		localTrafficPolicyServices, err := c.getLocalTrafficPolicyServices(protocol)
+		if err != nil {
+			klog.Errorf("failed to get services with local traffic policy, %+v", err)
+			return err
+		}
 		c.ipsets[protocol].AddOrReplaceIPSet(ipsets.IPSetMetadata{
 			MaxSize: 1048576,
 			SetID:   ServiceSet,
@@ -113,6 +119,11 @@ func (c *Controller) setIPSet() error {
 			SetID:   OtherNodeSet,
 			Type:    ipsets.IPSetTypeHashNet,
 		}, otherNode)
+		c.ipsets[protocol].AddOrReplaceIPSet(ipsets.IPSetMetadata{
+			MaxSize: 1048576,
+			SetID:   LocalTrafficPolicySet,
+			Type:    ipsets.IPSetTypeHashIPPort,
+		}, localTrafficPolicyServices)
 		c.ipsets[protocol].ApplyUpdates()
 	}
 	return nil
@@ -454,6 +465,10 @@ func (c *Controller) setIptables() error {
 		v4Rules = []util.IPTableRule{
 			// mark packets from pod to service
 			{Table: NAT, Chain: OvnPrerouting, Rule: strings.Fields(`-i ovn0 -m set --match-set ovn40subnets src -m set --match-set ovn40services dst -j MARK --set-xmark 0x4000/0x4000`)},
+			// mark external traffic to services with external traffic policy set to local, so that client ip is preserved
+			{Table: NAT, Chain: OvnPrerouting, Rule: strings.Fields(`-m set --match-set ovn40local-traffic-policy dst,dst -j MARK --set-xmark 0x100000/0x100000`)},
+			// do not nat external traffic to services with external traffic policy set to local
+			{Table: NAT, Chain: OvnPostrouting, Rule: strings.Fields(`-m mark --mark 0x100000/0x100000 -j RETURN`)},
 			// nat packets marked by kube-proxy or kube-ovn
 			{Table: NAT, Chain: OvnPostrouting, Rule: strings.Fields(`-m mark --mark 0x4000/0x4000 -j MASQUERADE`)},
 			// nat service traffic
@@ -484,6 +499,10 @@ func (c *Controller) setIptables() error {
 		v6Rules = []util.IPTableRule{
 			// mark packets from pod to service
 			{Table: NAT, Chain: OvnPrerouting, Rule: strings.Fields(`-i ovn0 -m set --match-set ovn60subnets src -m set --match-set ovn60services dst -j MARK --set-xmark 0x4000/0x4000`)},
+			// mark external traffic to services with external traffic policy set to local, so that client ip is preserved
+			{Table: NAT, Chain: OvnPrerouting, Rule: strings.Fields(`-m set --match-set ovn60local-traffic-policy dst,dst -j MARK --set-xmark 0x100000/0x100000`)},
+			// do not nat external traffic to services with external traffic policy set to local
+			{Table: NAT, Chain: OvnPostrouting, Rule: strings.Fields(`-m mark --mark 0x100000/0x100000 -j RETURN`)},
 			// nat packets marked by kube-proxy or kube-ovn
 			{Table: NAT, Chain: OvnPostrouting, Rule: strings.Fields(`-m mark --mark 0x4000/0x4000 -j MASQUERADE`)},
 			// nat service traffic
@@ -21,6 +21,27 @@ func (c OvnClient) GetPortGroup(name string, ignoreNotFound bool) (*ovnnb.PortGr
 	return pg, nil
 }
 
+// GetPortGroupPorts returns the names of the logical switch ports in the port group
+func (c OvnClient) GetPortGroupPorts(name string) ([]string, error) {
+	pg, err := c.GetPortGroup(name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := make([]string, 0, len(pg.Ports))
+	for _, uuid := range pg.Ports {
+		lsp := &ovnnb.LogicalSwitchPort{UUID: uuid}
+		if err = c.ovnNbClient.Get(context.TODO(), lsp); err != nil {
+			if err == client.ErrNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get logical switch port %s of port group %s: %v", uuid, name, err)
+		}
+		ports = append(ports, lsp.Name)
+	}
+	return ports, nil
+}
+
 func (c OvnClient) CreatePortGroup(name string, externalIDs map[string]string) error {
 	pg, err := c.GetPortGroup(name, true)
 	if err != nil {
@@ -51,10 +72,15 @@ func (c OvnClient) portGroupPortOp(pgName, portName string, opIsAdd bool) error
 		return err
 	}
 
-	lsp, err := c.GetLogicalSwitchPort(portName, false)
+	// a deleted logical switch port has been removed from the port group
+	// as the ports of a port group are weak references
+	lsp, err := c.GetLogicalSwitchPort(portName, !opIsAdd)
 	if err != nil {
 		return err
 	}
+	if lsp == nil {
+		return nil
+	}
 
 	portMap := make(map[string]struct{}, len(pg.Ports))
 	for _, port := range pg.Ports {
@@ -2,12 +2,14 @@ package ovs
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -469,30 +471,6 @@ func (c LegacyClient) CreateLogicalSwitch(ls, lr, subnet, gateway string, needRo
 	return nil
 }
 
-func (c LegacyClient) AddLbToLogicalSwitch(tcpLb, tcpSessLb, udpLb, udpSessLb, ls string) error {
-	if err := c.addLoadBalancerToLogicalSwitch(tcpLb, ls); err != nil {
-		klog.Errorf("failed to add tcp lb to %s, %v", ls, err)
-		return err
-	}
-
-	if err := c.addLoadBalancerToLogicalSwitch(udpLb, ls); err != nil {
-		klog.Errorf("failed to add udp lb to %s, %v", ls, err)
-		return err
-	}
-
-	if err := c.addLoadBalancerToLogicalSwitch(tcpSessLb, ls); err != nil {
-		klog.Errorf("failed to add tcp session lb to %s, %v", ls, err)
-		return err
-	}
-
-	if err := c.addLoadBalancerToLogicalSwitch(udpSessLb, ls); err != nil {
-		klog.Errorf("failed to add udp session lb to %s, %v", ls, err)
-		return err
-	}
-
-	return nil
-}
-
 func (c LegacyClient) RemoveLbFromLogicalSwitch(tcpLb, tcpSessLb, udpLb, udpSessLb, ls string) error {
 	if err := c.removeLoadBalancerFromLogicalSwitch(tcpLb, ls); err != nil {
 		klog.Errorf("failed to remove tcp lb from %s, %v", ls, err)
@@ -525,7 +503,20 @@ func (c LegacyClient) DeleteLoadBalancer(lbs ...string) error {
 			klog.Warningf("failed to find load_balancer '%s', %v", lb, err)
 			continue
 		}
-		if _, err := c.ovnNbCommand(IfExists, "destroy", "load_balancer", lbid); err != nil {
+		if lbid == "" {
+			continue
+		}
+		// load balancers are strongly referenced by groups and must be removed from them first
+		groups, err := c.findRowsReferring("load_balancer_group", "load_balancer", lbid)
+		if err != nil {
+			return err
+		}
+		cmd := []string{}
+		for _, lbg := range groups {
+			cmd = append(cmd, "remove", "load_balancer_group", lbg, "load_balancer", lbid, "--")
+		}
+		cmd = append(cmd, IfExists, "destroy", "load_balancer", lbid)
+		if _, err := c.ovnNbCommand(cmd...); err != nil {
 			return err
 		}
 	}
@@ -1263,11 +1254,279 @@ func (c LegacyClient) CreateLoadBalancerRule(lb, vip, ips, protocol string) erro
 	return err
 }
 
-func (c LegacyClient) addLoadBalancerToLogicalSwitch(lb, ls string) error {
-	_, err := c.ovnNbCommand(MayExist, "ls-lb-add", ls, lb)
+// CreateTemplateLoadBalancer create a loadbalancer whose vips and backends are expanded
+// by chassis template variables on each chassis
+func (c LegacyClient) CreateTemplateLoadBalancer(lb, protocol, selectFields, addressFamily string) error {
+	cmd := []string{"create", "load_balancer", fmt.Sprintf("name=%s", lb), fmt.Sprintf("protocol=%s", protocol),
+		"options:template=true", fmt.Sprintf("options:address-family=%s", addressFamily)}
+	if selectFields != "" {
+		cmd = append(cmd, fmt.Sprintf("selection_fields=%s", selectFields))
+	}
+	_, err := c.ovnNbCommand(cmd...)
+	return err
+}
+
+// SetLoadBalancerTemplateVip set the backends of a vip in a template loadbalancer,
+// lb-add can not be used as the backends refer to template variables
+func (c LegacyClient) SetLoadBalancerTemplateVip(lb, vip, backends string) error {
+	_, err := c.ovnNbCommand("set", "load_balancer", lb, fmt.Sprintf("vips:%q=%q", vip, backends))
 	return err
 }
 
+// RemoveLoadBalancerVip delete a vip from loadbalancer, unlike lb-del the loadbalancer
+// is kept when the last vip is removed
+func (c LegacyClient) RemoveLoadBalancerVip(lb, vip string) error {
+	_, err := c.ovnNbCommand(IfExists, "remove", "load_balancer", lb, "vips", fmt.Sprintf("%q", vip))
+	return err
+}
+
+// SetLoadBalancerAffinityTimeout set the timeout of client ip affinity of a session loadbalancer
+func (c LegacyClient) SetLoadBalancerAffinityTimeout(lb string, timeout int32) error {
+	_, err := c.ovnNbCommand("set", "load_balancer", lb, fmt.Sprintf("options:affinity_timeout=%d", timeout))
+	return err
+}
+
+// SetLoadBalancerVpc mark a loadbalancer as one of the dedicated session loadbalancers of the vpc
+func (c LegacyClient) SetLoadBalancerVpc(lb, vpc string) error {
+	_, err := c.ovnNbCommand("set", "load_balancer", lb, fmt.Sprintf("external_ids:vpc=%s", vpc))
+	return err
+}
+
+// ListVpcLoadBalancers list dedicated session loadbalancers of the vpc
+func (c LegacyClient) ListVpcLoadBalancers(vpc string) ([]string, error) {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=name", "find", "load_balancer", fmt.Sprintf("external_ids:vpc=%s", vpc))
+	if err != nil {
+		klog.Errorf("failed to list load balancers of vpc %s: %v", vpc, err)
+		return nil, err
+	}
+	lines := strings.Split(output, "\n")
+	result := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if len(strings.TrimSpace(l)) == 0 {
+			continue
+		}
+		result = append(result, strings.TrimSpace(l))
+	}
+	return result, nil
+}
+
+// findRowsReferring returns uuids of rows in table whose column contains the uuid
+func (c LegacyClient) findRowsReferring(table, column, uuid string) ([]string, error) {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=_uuid", "find", table, fmt.Sprintf("%s{>=}%s", column, uuid))
+	if err != nil {
+		klog.Errorf("failed to find %s referring %s, %v", table, uuid, err)
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
+// FindLoadBalancerGroup returns the uuid of the load balancer group
+func (c LegacyClient) FindLoadBalancerGroup(lbg string) (string, error) {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=_uuid",
+		"find", "load_balancer_group", fmt.Sprintf("name=%s", lbg))
+	if err != nil {
+		klog.Errorf("failed to find load balancer group %s, %v", lbg, err)
+		return "", err
+	}
+	if count := len(strings.Fields(output)); count > 1 {
+		return "", fmt.Errorf("%s has %d load balancer group entries", lbg, count)
+	}
+	return output, nil
+}
+
+// ListLoadBalancerGroups list names of load balancer groups created by kube-ovn
+func (c LegacyClient) ListLoadBalancerGroups() ([]string, error) {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=name",
+		"find", "load_balancer_group", fmt.Sprintf("external_ids:vendor=%s", util.CniTypeName))
+	if err != nil {
+		klog.Errorf("failed to list load balancer groups, %v", err)
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
+// AddLoadBalancersToGroup creates the load balancer group if not exists and adds the load balancers to it
+func (c LegacyClient) AddLoadBalancersToGroup(lbg string, lbs ...string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil {
+		return err
+	}
+	if lbgUuid == "" {
+		if lbgUuid, err = c.ovnNbCommand("create", "load_balancer_group", fmt.Sprintf("name=%s", lbg),
+			fmt.Sprintf("external_ids:vendor=%s", util.CniTypeName)); err != nil {
+			klog.Errorf("failed to create load balancer group %s, %v", lbg, err)
+			return err
+		}
+	}
+
+	var cmd []string
+	for _, lb := range lbs {
+		lbUuid, err := c.FindLoadbalancer(lb)
+		if err != nil {
+			return err
+		}
+		if lbUuid == "" {
+			return fmt.Errorf("load balancer %s not found", lb)
+		}
+		if len(cmd) != 0 {
+			cmd = append(cmd, "--")
+		}
+		cmd = append(cmd, "add", "load_balancer_group", lbgUuid, "load_balancer", lbUuid)
+	}
+	if len(cmd) == 0 {
+		return nil
+	}
+	if _, err = c.ovnNbCommand(cmd...); err != nil {
+		klog.Errorf("failed to add load balancers %v to group %s, %v", lbs, lbg, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteLoadBalancerGroup detaches the load balancer group from logical switches and routers and deletes it
+func (c LegacyClient) DeleteLoadBalancerGroup(lbg string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil || lbgUuid == "" {
+		return err
+	}
+	cmd := []string{}
+	for _, table := range []string{"logical_switch", "logical_router"} {
+		rows, err := c.findRowsReferring(table, "load_balancer_group", lbgUuid)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			cmd = append(cmd, "remove", table, row, "load_balancer_group", lbgUuid, "--")
+		}
+	}
+	cmd = append(cmd, IfExists, "destroy", "load_balancer_group", lbgUuid)
+	if _, err = c.ovnNbCommand(cmd...); err != nil {
+		klog.Errorf("failed to delete load balancer group %s, %v", lbg, err)
+		return err
+	}
+	return nil
+}
+
+// AddLoadBalancerGroupToLogicalSwitch makes all load balancers in the group apply to the logical switch
+func (c LegacyClient) AddLoadBalancerGroupToLogicalSwitch(lbg, ls string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil {
+		return err
+	}
+	if lbgUuid == "" {
+		return fmt.Errorf("load balancer group %s not found", lbg)
+	}
+	_, err = c.ovnNbCommand("add", "logical_switch", ls, "load_balancer_group", lbgUuid)
+	return err
+}
+
+// RemoveLoadBalancerGroupFromLogicalSwitch detaches the load balancer group from the logical switch
+func (c LegacyClient) RemoveLoadBalancerGroupFromLogicalSwitch(lbg, ls string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil || lbgUuid == "" {
+		return err
+	}
+	_, err = c.ovnNbCommand(IfExists, "remove", "logical_switch", ls, "load_balancer_group", lbgUuid)
+	return err
+}
+
+// LogicalRouterHasGateway returns whether the logical router is a gateway router or has distributed gateway ports
+func (c LegacyClient) LogicalRouterHasGateway(lr string) (bool, error) {
+	output, err := c.ovnNbCommand(IfExists, "get", "logical_router", lr, "options:chassis")
+	if err != nil {
+		klog.Errorf("failed to get chassis of logical router %s, %v", lr, err)
+		return false, err
+	}
+	if strings.Trim(output, "\"") != "" {
+		return true, nil
+	}
+
+	output, err = c.ovnNbCommand("--data=bare", "--no-heading", IfExists, "get", "logical_router", lr, "ports")
+	if err != nil {
+		klog.Errorf("failed to get ports of logical router %s, %v", lr, err)
+		return false, err
+	}
+	ports := strings.Fields(output)
+	if len(ports) == 0 {
+		return false, nil
+	}
+	cmd := []string{"--data=bare", "--no-heading", "--columns=gateway_chassis,ha_chassis_group", "list", "logical_router_port"}
+	if output, err = c.ovnNbCommand(append(cmd, ports...)...); err != nil {
+		klog.Errorf("failed to list ports of logical router %s, %v", lr, err)
+		return false, err
+	}
+	return len(strings.Fields(output)) != 0, nil
+}
+
+// AddLoadBalancerGroupToLogicalRouter makes all load balancers in the group apply to the logical router
+func (c LegacyClient) AddLoadBalancerGroupToLogicalRouter(lbg, lr string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil {
+		return err
+	}
+	if lbgUuid == "" {
+		return fmt.Errorf("load balancer group %s not found", lbg)
+	}
+	_, err = c.ovnNbCommand("add", "logical_router", lr, "load_balancer_group", lbgUuid)
+	return err
+}
+
+// RemoveLoadBalancerGroupFromLogicalRouter detaches the load balancer group from the logical router
+func (c LegacyClient) RemoveLoadBalancerGroupFromLogicalRouter(lbg, lr string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil || lbgUuid == "" {
+		return err
+	}
+	_, err = c.ovnNbCommand(IfExists, "remove", "logical_router", lr, "load_balancer_group", lbgUuid)
+	return err
+}
+
+// MigrateLoadBalancersToGroup replaces the load balancers attached to logical switches individually
+// with the load balancer group which contains them, load balancers attached to logical routers
+// individually are removed as the group is attached to the routers where it takes effect
+func (c LegacyClient) MigrateLoadBalancersToGroup(lbg string, lbs ...string) error {
+	lbgUuid, err := c.FindLoadBalancerGroup(lbg)
+	if err != nil {
+		return err
+	}
+	if lbgUuid == "" {
+		return fmt.Errorf("load balancer group %s not found", lbg)
+	}
+	for _, lb := range lbs {
+		lbUuid, err := c.FindLoadbalancer(lb)
+		if err != nil {
+			return err
+		}
+		if lbUuid == "" {
+			continue
+		}
+		switches, err := c.findRowsReferring("logical_switch", "load_balancer", lbUuid)
+		if err != nil {
+			return err
+		}
+		for _, ls := range switches {
+			klog.Infof("migrate load balancer %s of logical switch %s to group %s", lb, ls, lbg)
+			if _, err = c.ovnNbCommand("add", "logical_switch", ls, "load_balancer_group", lbgUuid, "--",
+				"remove", "logical_switch", ls, "load_balancer", lbUuid); err != nil {
+				klog.Errorf("failed to migrate load balancer %s of logical switch %s to group %s, %v", lb, ls, lbg, err)
+				return err
+			}
+		}
+		routers, err := c.findRowsReferring("logical_router", "load_balancer", lbUuid)
+		if err != nil {
+			return err
+		}
+		for _, lr := range routers {
+			klog.Infof("remove load balancer %s of logical router %s in favor of group %s", lb, lr, lbg)
+			if _, err = c.ovnNbCommand("remove", "logical_router", lr, "load_balancer", lbUuid); err != nil {
+				klog.Errorf("failed to remove load balancer %s from logical router %s, %v", lb, lr, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (c LegacyClient) removeLoadBalancerFromLogicalSwitch(lb, ls string) error {
 	if lb == "" {
 		return nil
@@ -1291,6 +1550,9 @@ func (c LegacyClient) DeleteLoadBalancerVip(vip, lb string) error {
 		klog.Errorf("failed to get lb: %v", err)
 		return err
 	}
+	if lbUuid == "" {
+		return nil
+	}
 
 	existVips, err := c.GetLoadBalancerVips(lbUuid)
 	if err != nil {
@@ -1301,8 +1563,235 @@ func (c LegacyClient) DeleteLoadBalancerVip(vip, lb string) error {
 	if vip == "" || len(existVips) == 1 {
 		return nil
 	}
-	_, err = c.ovnNbCommand(IfExists, "lb-del", lb, vip)
-	return err
+	if _, ok := existVips[vip]; !ok {
+		return nil
+	}
+	if _, err = c.ovnNbCommand(IfExists, "lb-del", lb, vip); err != nil {
+		return err
+	}
+	return c.DeleteLoadBalancerHealthCheck(lb, vip)
+}
+
+func (c LegacyClient) findLoadBalancerHealthCheck(lb, vip string) (string, error) {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=_uuid", "find", "load_balancer_health_check",
+		fmt.Sprintf("external_ids:lb=%s", lb), fmt.Sprintf("vip=%q", vip))
+	if err != nil {
+		klog.Errorf("failed to find health check of vip %s in lb %s: %v", vip, lb, err)
+		return "", err
+	}
+	return output, nil
+}
+
+// SetLoadBalancerHealthCheck create or update the health check of a vip in loadbalancer,
+// ipPortMappings maps backend ip to the logical port and the source ip used by service monitor
+func (c LegacyClient) SetLoadBalancerHealthCheck(lb, vip, svc string, options, ipPortMappings map[string]string) error {
+	hcUuid, err := c.findLoadBalancerHealthCheck(lb, vip)
+	if err != nil {
+		return err
+	}
+
+	optionArgs := make([]string, 0, len(options))
+	for k, v := range options {
+		optionArgs = append(optionArgs, fmt.Sprintf("options:%s=%s", k, v))
+	}
+	sort.Strings(optionArgs)
+
+	var cmd []string
+	if hcUuid == "" {
+		cmd = append(cmd, "--", "--id=@hc", "create", "load_balancer_health_check", fmt.Sprintf("vip=%q", vip),
+			fmt.Sprintf("external_ids:lb=%s", lb), fmt.Sprintf("external_ids:svc=%q", svc))
+		cmd = append(cmd, optionArgs...)
+		cmd = append(cmd, "--", "add", "load_balancer", lb, "health_check", "@hc")
+	} else {
+		cmd = append(cmd, "--", "clear", "load_balancer_health_check", hcUuid, "options")
+		if len(optionArgs) != 0 {
+			cmd = append(cmd, "--", "set", "load_balancer_health_check", hcUuid)
+			cmd = append(cmd, optionArgs...)
+		}
+	}
+	for ip, mapping := range ipPortMappings {
+		cmd = append(cmd, "--", "set", "load_balancer", lb, fmt.Sprintf("ip_port_mappings:%q=%q", ip, mapping))
+	}
+
+	if _, err = c.ovnNbCommand(cmd...); err != nil {
+		klog.Errorf("failed to set health check of vip %s in lb %s: %v", vip, lb, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteLoadBalancerHealthCheck delete the health check of a vip from loadbalancer
+func (c LegacyClient) DeleteLoadBalancerHealthCheck(lb, vip string) error {
+	hcUuid, err := c.findLoadBalancerHealthCheck(lb, vip)
+	if err != nil {
+		return err
+	}
+	if hcUuid == "" {
+		return nil
+	}
+	if _, err = c.ovnNbCommand(IfExists, "remove", "load_balancer", lb, "health_check", hcUuid); err != nil {
+		klog.Errorf("failed to delete health check of vip %s in lb %s: %v", vip, lb, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteServiceHealthChecks delete all health checks created for a service
+func (c LegacyClient) DeleteServiceHealthChecks(svc string) error {
+	output, err := c.ovnNbCommand("--format=csv", "--data=bare", "--no-heading", "--columns=_uuid,external_ids",
+		"find", "load_balancer_health_check", fmt.Sprintf("external_ids:svc=%q", svc))
+	if err != nil {
+		klog.Errorf("failed to find health checks of service %s: %v", svc, err)
+		return err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ",")
+		if len(fields) != 2 {
+			continue
+		}
+		var lb string
+		for _, kv := range strings.Fields(fields[1]) {
+			if strings.HasPrefix(kv, "lb=") {
+				lb = strings.TrimPrefix(kv, "lb=")
+				break
+			}
+		}
+		if lb == "" {
+			continue
+		}
+		if _, err = c.ovnNbCommand(IfExists, "remove", "load_balancer", lb, "health_check", fields[0]); err != nil {
+			klog.Errorf("failed to delete health check %s of service %s: %v", fields[0], svc, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNorthdVersion returns the internal version of ovn-northd, such as 22.03.1-20.21.0-56.4,
+// an empty string is returned if ovn-northd has not connected to the nb database yet
+func (c LegacyClient) GetNorthdVersion() (string, error) {
+	output, err := c.ovnNbCommand(IfExists, "get", "NB_Global", ".", "options:northd_internal_version")
+	if err != nil {
+		klog.Errorf("failed to get northd version: %v", err)
+		return "", err
+	}
+	return strings.Trim(output, "\""), nil
+}
+
+// ListChassisTemplateVars returns names of template variables created by kube-ovn by chassis
+func (c LegacyClient) ListChassisTemplateVars() (map[string][]string, error) {
+	output, err := c.ovnNbCommand("--format=csv", "--data=bare", "--no-heading", "--columns=chassis,variables",
+		"find", "chassis_template_var", fmt.Sprintf("external_ids:vendor=%s", util.CniTypeName))
+	if err != nil {
+		klog.Errorf("failed to list chassis template vars: %v", err)
+		return nil, err
+	}
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chassis template vars %q: %v", output, err)
+	}
+
+	result := make(map[string][]string, len(records))
+	for _, record := range records {
+		if len(record) != 2 {
+			continue
+		}
+		names := []string{}
+		for _, kv := range strings.Fields(record[1]) {
+			names = append(names, strings.SplitN(kv, "=", 2)[0])
+		}
+		result[record[0]] = names
+	}
+	return result, nil
+}
+
+// SetChassisTemplateVars set template variables of the chassis, variables with empty value are removed
+func (c LegacyClient) SetChassisTemplateVars(chassis string, vars map[string]string) error {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=_uuid",
+		"find", "chassis_template_var", fmt.Sprintf("chassis=%q", chassis))
+	if err != nil {
+		klog.Errorf("failed to find template vars of chassis %s: %v", chassis, err)
+		return err
+	}
+	if output == "" {
+		if output, err = c.ovnNbCommand("create", "chassis_template_var", fmt.Sprintf("chassis=%q", chassis),
+			fmt.Sprintf("external_ids:vendor=%s", util.CniTypeName)); err != nil {
+			klog.Errorf("failed to create template vars of chassis %s: %v", chassis, err)
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var cmd []string
+	for _, name := range names {
+		if len(cmd) != 0 {
+			cmd = append(cmd, "--")
+		}
+		if vars[name] == "" {
+			cmd = append(cmd, IfExists, "remove", "chassis_template_var", output, "variables", fmt.Sprintf("%q", name))
+		} else {
+			cmd = append(cmd, "set", "chassis_template_var", output, fmt.Sprintf("variables:%q=%q", name, vars[name]))
+		}
+	}
+	if len(cmd) == 0 {
+		return nil
+	}
+	if _, err = c.ovnNbCommand(cmd...); err != nil {
+		klog.Errorf("failed to set template vars of chassis %s: %v", chassis, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteChassisTemplateVars destroy template variables of the chassis
+func (c LegacyClient) DeleteChassisTemplateVars(chassis string) error {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading", "--columns=_uuid",
+		"find", "chassis_template_var", fmt.Sprintf("chassis=%q", chassis))
+	if err != nil {
+		klog.Errorf("failed to find template vars of chassis %s: %v", chassis, err)
+		return err
+	}
+	for _, uuid := range strings.Fields(output) {
+		if _, err = c.ovnNbCommand(IfExists, "destroy", "chassis_template_var", uuid); err != nil {
+			klog.Errorf("failed to delete template vars of chassis %s: %v", chassis, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLoadBalancerIpPortMappings return ip_port_mappings of a loadbalancer
+func (c LegacyClient) GetLoadBalancerIpPortMappings(lb string) (map[string]string, error) {
+	output, err := c.ovnNbCommand("--data=bare", "--no-heading",
+		"get", "load_balancer", lb, "ip_port_mappings")
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	err = json.Unmarshal([]byte(strings.Replace(output, "=", ":", -1)), &result)
+	return result, err
+}
+
+// RemoveLoadBalancerIpPortMappings remove ip_port_mappings of the backend ips from a loadbalancer
+func (c LegacyClient) RemoveLoadBalancerIpPortMappings(lb string, ips []string) error {
+	if len(ips) == 0 {
+		return nil
+	}
+	cmd := make([]string, 0, len(ips)+4)
+	cmd = append(cmd, IfExists, "remove", "load_balancer", lb, "ip_port_mappings")
+	for _, ip := range ips {
+		cmd = append(cmd, fmt.Sprintf("%q", ip))
+	}
+	if _, err := c.ovnNbCommand(cmd...); err != nil {
+		klog.Errorf("failed to remove ip_port_mappings %v from lb %s: %v", ips, lb, err)
+		return err
+	}
+	return nil
 }
 
 // GetLoadBalancerVips return vips of a loadbalancer
@@ -2075,41 +2564,70 @@ func (c LegacyClient) ListSgRuleAddressSet(sgName string, direction AclDirection
 }
 
 func (c LegacyClient) createSgRuleACL(sgName string, direction AclDirection, rule *kubeovnv1.SgRule, index int) error {
+	// the webhook may be skipped, validate the rule again before it is rendered into the acl match
+	if err := util.ValidateSgRule(rule); err != nil {
+		klog.Errorf("invalid rule %d of sg %s, %v", index, sgName, err)
+		return err
+	}
+
 	ipSuffix := "ip4"
 	if rule.IPVersion == "ipv6" {
 		ipSuffix = "ip6"
 	}
 
-	sgPortGroupName := GetSgPortGroupName(sgName)
-	var matchArgs []string
-	if rule.RemoteType == kubeovnv1.SgRemoteTypeAddress {
-		if direction == SgAclIngressDirection {
-			matchArgs = append(matchArgs, fmt.Sprintf("outport==@%s && %s && %s.src==%s", sgPortGroupName, ipSuffix, ipSuffix, rule.RemoteAddress))
+	var remote string
+	switch rule.RemoteType {
+	case kubeovnv1.SgRemoteTypeAddress:
+		remote = rule.RemoteAddress
+	case kubeovnv1.SgRemoteTypeAddressSet:
+		remote = "$" + rule.RemoteAddressSet
+	default:
+		if ipSuffix == "ip4" {
+			remote = "$" + GetSgV4AssociatedName(rule.RemoteSecurityGroup)
 		} else {
-			matchArgs = append(matchArgs, fmt.Sprintf("inport==@%s && %s && %s.dst==%s", sgPortGroupName, ipSuffix, ipSuffix, rule.RemoteAddress))
+			remote = "$" + GetSgV6AssociatedName(rule.RemoteSecurityGroup)
 		}
+	}
+
+	sgPortGroupName := GetSgPortGroupName(sgName)
+	var matchArgs []string
+	if direction == SgAclIngressDirection {
+		matchArgs = append(matchArgs, fmt.Sprintf("outport==@%s && %s && %s.src==%s", sgPortGroupName, ipSuffix, ipSuffix, remote))
 	} else {
-		if direction == SgAclIngressDirection {
-			matchArgs = append(matchArgs, fmt.Sprintf("outport==@%s && %s && %s.src==$%s", sgPortGroupName, ipSuffix, ipSuffix, GetSgV4AssociatedName(rule.RemoteSecurityGroup)))
-		} else {
-			matchArgs = append(matchArgs, fmt.Sprintf("inport==@%s && %s && %s.dst==$%s", sgPortGroupName, ipSuffix, ipSuffix, GetSgV4AssociatedName(rule.RemoteSecurityGroup)))
-		}
+		matchArgs = append(matchArgs, fmt.Sprintf("inport==@%s && %s && %s.dst==%s", sgPortGroupName, ipSuffix, ipSuffix, remote))
 	}
 
 	if rule.Protocol == kubeovnv1.ProtocolICMP {
-		if ipSuffix == "ip4" {
-			matchArgs = append(matchArgs, "icmp4")
+		icmp := "icmp4"
+		if ipSuffix == "ip6" {
+			icmp = "icmp6"
+		}
+		matchArgs = append(matchArgs, icmp)
+		if rule.IcmpType != nil {
+			matchArgs = append(matchArgs, fmt.Sprintf("%s.type==%d", icmp, *rule.IcmpType))
+		}
+		if rule.IcmpCode != nil {
+			matchArgs = append(matchArgs, fmt.Sprintf("%s.code==%d", icmp, *rule.IcmpCode))
+		}
+	} else if rule.Protocol == kubeovnv1.ProtocolTCP || rule.Protocol == kubeovnv1.ProtocolUDP || rule.Protocol == kubeovnv1.ProtocolSCTP {
+		if len(rule.Ports) != 0 {
+			ports := make([]string, 0, len(rule.Ports))
+			for _, port := range rule.Ports {
+				ports = append(ports, strconv.Itoa(port))
+			}
+			matchArgs = append(matchArgs, fmt.Sprintf("%s.dst=={%s}", rule.Protocol, strings.Join(ports, ",")))
 		} else {
-			matchArgs = append(matchArgs, "icmp6")
+			matchArgs = append(matchArgs, fmt.Sprintf("%d<=%s.dst<=%d", rule.PortRangeMin, rule.Protocol, rule.PortRangeMax))
 		}
-	} else if rule.Protocol == kubeovnv1.ProtocolTCP || rule.Protocol == kubeovnv1.ProtocolUDP {
-		matchArgs = append(matchArgs, fmt.Sprintf("%d<=%s.dst<=%d", rule.PortRangeMin, rule.Protocol, rule.PortRangeMax))
 	}
 
 	matchStr := strings.Join(matchArgs, " && ")
 	action := "drop"
 	if rule.Policy == kubeovnv1.PolicyAllow {
 		action = "allow-related"
+		if rule.Stateless {
+			action = "allow-stateless"
+		}
 	}
 	highestPriority, err := strconv.Atoi(util.SecurityGroupHighestPriority)
 	if err != nil {
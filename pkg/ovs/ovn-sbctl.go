@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -132,3 +133,43 @@ func (c LegacyClient) GetAllChassis() ([]string, error) {
 	}
 	return result, nil
 }
+
+type ServiceMonitor struct {
+	IP          string
+	Port        int32
+	Protocol    string
+	LogicalPort string
+	Status      string
+}
+
+// ListServiceMonitors list the health status of all loadbalancer backends probed by ovn-controller
+func (c LegacyClient) ListServiceMonitors() ([]ServiceMonitor, error) {
+	output, err := c.ovnSbCommand("--format=csv", "--no-heading", "--data=bare", "--columns=ip,port,protocol,logical_port,status", "list", "service_monitor")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service monitors, %v", err)
+	}
+
+	var monitors []ServiceMonitor
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ",")
+		if len(fields) != 5 {
+			continue
+		}
+		port, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		protocol := fields[2]
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		monitors = append(monitors, ServiceMonitor{
+			IP:          fields[0],
+			Port:        int32(port),
+			Protocol:    protocol,
+			LogicalPort: fields[3],
+			Status:      fields[4],
+		})
+	}
+	return monitors, nil
+}
@@ -3,7 +3,9 @@ package pinger
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -13,27 +15,34 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
+	clientset "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
 type Configuration struct {
-	KubeConfigFile     string
-	KubeClient         kubernetes.Interface
-	Port               int
-	DaemonSetNamespace string
-	DaemonSetName      string
-	Interval           int
-	Mode               string
-	ExitCode           int
-	InternalDNS        string
-	ExternalDNS        string
-	NodeName           string
-	HostIP             string
-	PodName            string
-	PodIP              string
-	PodProtocols       []string
-	ExternalAddress    string
-	NetworkMode        string
+	KubeConfigFile      string
+	KubeClient          kubernetes.Interface
+	KubeOvnClient       clientset.Interface
+	Port                int
+	DaemonSetNamespace  string
+	DaemonSetName       string
+	Interval            int
+	Mode                string
+	ExitCode            int
+	InternalDNS         string
+	ExternalDNS         string
+	NodeName            string
+	HostIP              string
+	PodName             string
+	PodIP               string
+	PodProtocols        []string
+	ExternalAddress     string
+	NetworkMode         string
+	ProbePort           int
+	ProbeProtocols      []string
+	ProbeTargets        []*ProbeTarget
+	EnablePathMtuCheck  bool
+	EnableNodeCondition bool
 
 	// Used for OVS Monitor
 	PollTimeout                     int
@@ -53,17 +62,22 @@ type Configuration struct {
 
 func ParseFlags() (*Configuration, error) {
 	var (
-		argPort               = pflag.Int("port", 8080, "metrics port")
-		argKubeConfigFile     = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information. If not set use the inCluster token.")
-		argDaemonSetNameSpace = pflag.String("ds-namespace", "kube-system", "kube-ovn-pinger daemonset namespace")
-		argDaemonSetName      = pflag.String("ds-name", "kube-ovn-pinger", "kube-ovn-pinger daemonset name")
-		argInterval           = pflag.Int("interval", 5, "interval seconds between consecutive pings")
-		argMode               = pflag.String("mode", "server", "server or job Mode")
-		argExitCode           = pflag.Int("exit-code", 0, "exit code when failure happens")
-		argInternalDns        = pflag.String("internal-dns", "kubernetes.default", "check dns from pod")
-		argExternalDns        = pflag.String("external-dns", "", "check external dns resolve from pod")
-		argExternalAddress    = pflag.String("external-address", "", "check ping connection to an external address, default: 114.114.114.114")
-		argNetworkMode        = pflag.String("network-mode", "kube-ovn", "The cni plugin current cluster used, default: kube-ovn")
+		argPort                = pflag.Int("port", 8080, "metrics port")
+		argKubeConfigFile      = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information. If not set use the inCluster token.")
+		argDaemonSetNameSpace  = pflag.String("ds-namespace", "kube-system", "kube-ovn-pinger daemonset namespace")
+		argDaemonSetName       = pflag.String("ds-name", "kube-ovn-pinger", "kube-ovn-pinger daemonset name")
+		argInterval            = pflag.Int("interval", 5, "interval seconds between consecutive pings")
+		argMode                = pflag.String("mode", "server", "server or job Mode")
+		argExitCode            = pflag.Int("exit-code", 0, "exit code when failure happens")
+		argInternalDns         = pflag.String("internal-dns", "kubernetes.default", "check dns from pod")
+		argExternalDns         = pflag.String("external-dns", "", "check external dns resolve from pod")
+		argExternalAddress     = pflag.String("external-address", "", "check ping connection to an external address, default: 114.114.114.114")
+		argNetworkMode         = pflag.String("network-mode", "kube-ovn", "The cni plugin current cluster used, default: kube-ovn")
+		argProbePort           = pflag.Int("probe-port", 8090, "The port to serve tcp, udp and http probes of other pingers")
+		argProbeProtocols      = pflag.String("probe-protocols", "", "Comma separated protocols of probes to other pingers in addition to ping, tcp, udp or http")
+		argEnablePathMtuCheck  = pflag.Bool("enable-pmtu-check", false, "Probe the path mtu to nodes, pods and external addresses with packets of the pod mtu and fragmentation disallowed")
+		argEnableNodeCondition = pflag.Bool("enable-node-condition", true, "Set the KubeOvnNetworkReady condition of the node and record events by the results of ovs, ovn-controller, port binding and mesh checks in server mode")
+		argProbeTargets        = pflag.String("probe-targets", "", "Comma separated targets of tcp connect, udp echo and http get probes, e.g. tcp://10.96.0.10:53,udp://10.96.0.10:53,http://1.1.1.1:80/")
 
 		argPollTimeout                     = pflag.Int("ovs.timeout", 2, "Timeout on JSON-RPC requests to OVS.")
 		argPollInterval                    = pflag.Int("ovs.poll-interval", 15, "The minimum interval (in seconds) between collections from OVS server.")
@@ -99,22 +113,25 @@ func ParseFlags() (*Configuration, error) {
 	pflag.Parse()
 
 	config := &Configuration{
-		KubeConfigFile:     *argKubeConfigFile,
-		KubeClient:         nil,
-		Port:               *argPort,
-		DaemonSetNamespace: *argDaemonSetNameSpace,
-		DaemonSetName:      *argDaemonSetName,
-		Interval:           *argInterval,
-		Mode:               *argMode,
-		ExitCode:           *argExitCode,
-		InternalDNS:        *argInternalDns,
-		ExternalDNS:        *argExternalDns,
-		PodIP:              os.Getenv("POD_IP"),
-		HostIP:             os.Getenv("HOST_IP"),
-		NodeName:           os.Getenv("NODE_NAME"),
-		PodName:            os.Getenv("POD_NAME"),
-		ExternalAddress:    *argExternalAddress,
-		NetworkMode:        *argNetworkMode,
+		KubeConfigFile:      *argKubeConfigFile,
+		KubeClient:          nil,
+		Port:                *argPort,
+		DaemonSetNamespace:  *argDaemonSetNameSpace,
+		DaemonSetName:       *argDaemonSetName,
+		Interval:            *argInterval,
+		Mode:                *argMode,
+		ExitCode:            *argExitCode,
+		InternalDNS:         *argInternalDns,
+		ExternalDNS:         *argExternalDns,
+		PodIP:               os.Getenv("POD_IP"),
+		HostIP:              os.Getenv("HOST_IP"),
+		NodeName:            os.Getenv("NODE_NAME"),
+		PodName:             os.Getenv("POD_NAME"),
+		ExternalAddress:     *argExternalAddress,
+		NetworkMode:         *argNetworkMode,
+		ProbePort:           *argProbePort,
+		EnablePathMtuCheck:  *argEnablePathMtuCheck,
+		EnableNodeCondition: *argEnableNodeCondition,
 
 		// OVS Monitor
 		PollTimeout:                     *argPollTimeout,
@@ -131,6 +148,25 @@ func ParseFlags() (*Configuration, error) {
 		ServiceOvnControllerFileLogPath: *argServiceOvnControllerFileLogPath,
 		ServiceOvnControllerFilePidPath: *argServiceOvnControllerFilePidPath,
 	}
+	if *argProbeProtocols != "" {
+		for _, protocol := range strings.Split(*argProbeProtocols, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if protocol != ProbeProtocolTCP && protocol != ProbeProtocolUDP && protocol != ProbeProtocolHTTP {
+				return nil, fmt.Errorf("invalid probe protocol %s, must be %s, %s or %s", protocol, ProbeProtocolTCP, ProbeProtocolUDP, ProbeProtocolHTTP)
+			}
+			config.ProbeProtocols = append(config.ProbeProtocols, protocol)
+		}
+	}
+	if *argProbeTargets != "" {
+		for _, t := range strings.Split(*argProbeTargets, ",") {
+			target, err := ParseProbeTarget(strings.TrimSpace(t))
+			if err != nil {
+				return nil, err
+			}
+			config.ProbeTargets = append(config.ProbeTargets, target)
+		}
+	}
+
 	if err := config.initKubeClient(); err != nil {
 		return nil, err
 	}
@@ -186,6 +222,14 @@ func (config *Configuration) initKubeClient() error {
 	cfg.Timeout = 15 * time.Second
 	cfg.QPS = 1000
 	cfg.Burst = 2000
+
+	kubeOvnClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Errorf("init kubeovn client failed %v", err)
+		return err
+	}
+	config.KubeOvnClient = kubeOvnClient
+
 	cfg.ContentType = "application/vnd.kubernetes.protobuf"
 	cfg.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
 	kubeClient, err := kubernetes.NewForConfig(cfg)
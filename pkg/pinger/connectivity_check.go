@@ -0,0 +1,362 @@
+package pinger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	goping "github.com/oilbeater/go-ping"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovninformer "github.com/kubeovn/kube-ovn/pkg/client/informers/externalversions"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	CheckProtocolICMP = "ICMP"
+	CheckProtocolTCP  = "TCP"
+	CheckProtocolUDP  = "UDP"
+	CheckProtocolHTTP = "HTTP"
+
+	// maxCheckAddresses is the maximum number of addresses of a cidr destination to check
+	maxCheckAddresses = 16
+)
+
+// StartConnectivityChecks runs the ConnectivityChecks whose sources are on the node
+func StartConnectivityChecks(config *Configuration) {
+	// only pods on the node can be sources of the checks run by the pinger
+	podInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, 0,
+		kubeinformers.WithTweakListOptions(func(listOption *metav1.ListOptions) {
+			listOption.FieldSelector = fmt.Sprintf("spec.nodeName=%s", config.NodeName)
+			listOption.AllowWatchBookmarks = true
+		}))
+	kubeovnInformerFactory := kubeovninformer.NewSharedInformerFactoryWithOptions(config.KubeOvnClient, 0,
+		kubeovninformer.WithTweakListOptions(func(listOption *metav1.ListOptions) {
+			listOption.AllowWatchBookmarks = true
+		}))
+	podInformer := podInformerFactory.Core().V1().Pods()
+	checkInformer := kubeovnInformerFactory.Kubeovn().V1().ConnectivityChecks()
+	podsLister, checksLister := podInformer.Lister(), checkInformer.Lister()
+	podsSynced, checksSynced := podInformer.Informer().HasSynced, checkInformer.Informer().HasSynced
+
+	podInformerFactory.Start(wait.NeverStop)
+	kubeovnInformerFactory.Start(wait.NeverStop)
+	if !cache.WaitForCacheSync(wait.NeverStop, podsSynced, checksSynced) {
+		klog.Errorf("failed to wait for caches of connectivity checks to sync")
+		return
+	}
+
+	for {
+		runConnectivityChecks(config, checksLister, podsLister)
+		time.Sleep(time.Duration(config.Interval) * time.Second)
+	}
+}
+
+func runConnectivityChecks(config *Configuration, checksLister kubeovnlister.ConnectivityCheckLister, podsLister listerv1.PodLister) {
+	checks, err := checksLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list connectivity checks, %v", err)
+		return
+	}
+
+	for _, check := range checks {
+		sources, err := checkSources(config, podsLister, check)
+		if err != nil {
+			klog.Errorf("failed to get sources of connectivity check %s/%s, %v", check.Namespace, check.Name, err)
+			continue
+		}
+		if checkResultsUpToDate(config, check, sources) {
+			continue
+		}
+
+		var results []kubeovnv1.ConnectivityCheckResult
+		if len(sources) != 0 {
+			klog.Infof("start to run connectivity check %s/%s from %v", check.Namespace, check.Name, sources)
+			results = runConnectivityCheck(config, check, sources)
+		}
+		if err = updateConnectivityCheckResults(config, check, results); err != nil {
+			klog.Errorf("failed to update results of connectivity check %s/%s, %v", check.Namespace, check.Name, err)
+		}
+	}
+}
+
+// checkSources returns the pinger as the source if the check runs on the node, the probes are sent
+// from the network namespace of the pinger rather than those of the selected pods
+func checkSources(config *Configuration, podsLister listerv1.PodLister, check *kubeovnv1.ConnectivityCheck) ([]string, error) {
+	source := check.Spec.Source
+	if source.Node != "" {
+		if source.Node == config.NodeName {
+			return []string{config.PodName}, nil
+		}
+		return nil, nil
+	}
+	if source.PodSelector == nil {
+		return nil, fmt.Errorf("neither pod selector nor node of the source is set")
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(source.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+	// the lister only caches pods on the node
+	pods, err := podsLister.Pods(check.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == config.NodeName && pod.Status.Phase == v1.PodRunning {
+			return []string{config.PodName}, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkResultsUpToDate returns whether the results of the node are of the current generation and sources
+func checkResultsUpToDate(config *Configuration, check *kubeovnv1.ConnectivityCheck, sources []string) bool {
+	var resultSources []string
+	for _, result := range check.Status.Results {
+		if result.Node != config.NodeName {
+			continue
+		}
+		if result.ObservedGeneration != check.Generation {
+			return false
+		}
+		if !util.ContainsString(resultSources, result.Source) {
+			resultSources = append(resultSources, result.Source)
+		}
+	}
+	sort.Strings(resultSources)
+	return strings.Join(resultSources, ",") == strings.Join(sources, ",")
+}
+
+// checkDestinations returns the addresses of the destination of the check
+func checkDestinations(config *Configuration, check *kubeovnv1.ConnectivityCheck) ([]string, error) {
+	var addresses []string
+	destination := check.Spec.Destination
+	switch {
+	case destination.Pod != "":
+		pod, err := config.KubeClient.CoreV1().Pods(check.Namespace).Get(context.Background(), destination.Pod, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			addresses = append(addresses, podIP.IP)
+		}
+	case destination.Service != "":
+		svc, err := config.KubeClient.CoreV1().Services(check.Namespace).Get(context.Background(), destination.Service, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range svc.Spec.ClusterIPs {
+			if ip != v1.ClusterIPNone {
+				addresses = append(addresses, ip)
+			}
+		}
+	case destination.CIDR != "":
+		if !strings.Contains(destination.CIDR, "/") {
+			if net.ParseIP(destination.CIDR) == nil {
+				return nil, fmt.Errorf("invalid ip %s", destination.CIDR)
+			}
+			addresses = append(addresses, destination.CIDR)
+			break
+		}
+		_, cidr, err := net.ParseCIDR(destination.CIDR)
+		if err != nil {
+			return nil, err
+		}
+		addresses = cidrAddresses(cidr, maxCheckAddresses)
+	case destination.FQDN != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		var r net.Resolver
+		ips, err := r.LookupHost(ctx, destination.FQDN)
+		if err != nil {
+			return nil, err
+		}
+		addresses = ips
+	default:
+		return nil, fmt.Errorf("no destination is set")
+	}
+
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if util.ContainsString(config.PodProtocols, util.CheckProtocol(address)) {
+			result = append(result, address)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no address of the destination in protocols %v", config.PodProtocols)
+	}
+	return result, nil
+}
+
+// cidrAddresses returns up to limit host addresses of the cidr
+func cidrAddresses(cidr *net.IPNet, limit int) []string {
+	var addresses []string
+	ones, bits := cidr.Mask.Size()
+	ip := make(net.IP, len(cidr.IP))
+	copy(ip, cidr.IP)
+	// skip the network address of ipv4 cidrs which have one
+	if bits == 32 && ones < 31 {
+		ip = nextIP(ip)
+	}
+	for ; cidr.Contains(ip) && len(addresses) < limit; ip = nextIP(ip) {
+		if bits == 32 && ones < 31 && !cidr.Contains(nextIP(ip)) {
+			// broadcast address
+			break
+		}
+		addresses = append(addresses, ip.String())
+	}
+	return addresses
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// hopCount estimates the number of routers on the path from the ttl of replies,
+// assuming the initial ttl is the smallest common default no less than the ttl
+func hopCount(ttl int) int32 {
+	for _, initial := range []int{64, 128, 255} {
+		if ttl <= initial {
+			return int32(initial - ttl)
+		}
+	}
+	return 0
+}
+
+func pingAddress(address string) (time.Duration, int, int32, error) {
+	pinger, err := goping.NewPinger(address)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	var ttl int
+	pinger.OnRecv = func(p *goping.Packet) {
+		ttl = p.Ttl
+	}
+	pinger.SetPrivileged(true)
+	pinger.Timeout = 5 * time.Second
+	pinger.Count = probeCount
+	pinger.Interval = 100 * time.Millisecond
+	pinger.Run()
+	stats := pinger.Statistics()
+	lost := stats.PacketsSent - stats.PacketsRecv
+	if lost < 0 {
+		lost = 0
+	}
+	if stats.PacketsRecv == 0 {
+		return 0, lost, 0, fmt.Errorf("no echo reply received")
+	}
+	return stats.AvgRtt, lost, hopCount(ttl), nil
+}
+
+func runConnectivityCheck(config *Configuration, check *kubeovnv1.ConnectivityCheck, sources []string) []kubeovnv1.ConnectivityCheckResult {
+	now := metav1.Now()
+	var results []kubeovnv1.ConnectivityCheckResult
+	addResult := func(destination string, latency time.Duration, lost int, hops int32, err error) {
+		for _, source := range sources {
+			result := kubeovnv1.ConnectivityCheckResult{
+				Source:             source,
+				Node:               config.NodeName,
+				Destination:        destination,
+				Success:            err == nil && lost == 0,
+				HopCount:           hops,
+				ObservedGeneration: check.Generation,
+				LastUpdateTime:     now,
+			}
+			if latency != 0 {
+				result.Latency = latency.String()
+			}
+			var messages []string
+			if lost != 0 {
+				messages = append(messages, fmt.Sprintf("%d/%d probes lost", lost, probeCount))
+			}
+			if err != nil {
+				messages = append(messages, err.Error())
+			}
+			result.Message = strings.Join(messages, ", ")
+			results = append(results, result)
+		}
+	}
+
+	addresses, err := checkDestinations(config, check)
+	if err != nil {
+		addResult("", 0, 0, 0, fmt.Errorf("failed to get addresses of the destination: %v", err))
+		return results
+	}
+
+	protocol := strings.ToUpper(check.Spec.Protocol)
+	for _, address := range addresses {
+		switch protocol {
+		case CheckProtocolICMP:
+			latency, lost, hops, err := pingAddress(address)
+			addResult(address, latency, lost, hops, err)
+		case CheckProtocolTCP, CheckProtocolUDP, CheckProtocolHTTP:
+			if check.Spec.Port <= 0 || check.Spec.Port > 65535 {
+				addResult(address, 0, 0, 0, fmt.Errorf("invalid port %d", check.Spec.Port))
+				continue
+			}
+			target := &ProbeTarget{
+				Protocol: strings.ToLower(protocol),
+				Address:  net.JoinHostPort(address, strconv.Itoa(int(check.Spec.Port))),
+				Path:     check.Spec.Path,
+			}
+			latency, lost, err := probe(target, probeCount, probeTimeout)
+			if lost != probeCount {
+				err = nil
+			}
+			addResult(address, latency, lost, 0, err)
+		default:
+			addResult(address, 0, 0, 0, fmt.Errorf("invalid protocol %s", check.Spec.Protocol))
+		}
+	}
+	return results
+}
+
+// updateConnectivityCheckResults replaces the results of the node in the status of the check
+func updateConnectivityCheckResults(config *Configuration, check *kubeovnv1.ConnectivityCheck, results []kubeovnv1.ConnectivityCheckResult) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := config.KubeOvnClient.KubeovnV1().ConnectivityChecks(check.Namespace).Get(context.Background(), check.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		newResults := make([]kubeovnv1.ConnectivityCheckResult, 0, len(latest.Status.Results)+len(results))
+		for _, result := range latest.Status.Results {
+			if result.Node != config.NodeName {
+				newResults = append(newResults, result)
+			}
+		}
+		newResults = append(newResults, results...)
+		sort.Slice(newResults, func(i, j int) bool {
+			if newResults[i].Source != newResults[j].Source {
+				return newResults[i].Source < newResults[j].Source
+			}
+			return newResults[i].Destination < newResults[j].Destination
+		})
+		latest.Status.Results = newResults
+		_, err = config.KubeOvnClient.KubeovnV1().ConnectivityChecks(check.Namespace).UpdateStatus(context.Background(), latest, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,237 @@
+package pinger
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func TestCidrAddresses(t *testing.T) {
+	tests := []struct {
+		name  string
+		cidr  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "ipv4 network and broadcast skipped",
+			cidr:  "10.0.0.0/29",
+			limit: 16,
+			want:  []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6"},
+		},
+		{
+			name:  "ipv4 limited",
+			cidr:  "10.0.0.0/16",
+			limit: 3,
+			want:  []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+		{
+			name:  "ipv4 /31",
+			cidr:  "10.0.0.0/31",
+			limit: 16,
+			want:  []string{"10.0.0.0", "10.0.0.1"},
+		},
+		{
+			name:  "ipv4 /32",
+			cidr:  "10.0.0.1/32",
+			limit: 16,
+			want:  []string{"10.0.0.1"},
+		},
+		{
+			name:  "ipv4 /30 crossing octet",
+			cidr:  "10.0.0.252/30",
+			limit: 16,
+			want:  []string{"10.0.0.253", "10.0.0.254"},
+		},
+		{
+			name:  "ipv6",
+			cidr:  "fd00::/126",
+			limit: 16,
+			want:  []string{"fd00::", "fd00::1", "fd00::2", "fd00::3"},
+		},
+		{
+			name:  "ipv6 limited",
+			cidr:  "fd00::/64",
+			limit: 2,
+			want:  []string{"fd00::", "fd00::1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cidr, err := net.ParseCIDR(tt.cidr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cidrAddresses(cidr, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cidrAddresses(%s, %d) = %v, want %v", tt.cidr, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"10.0.0.1", "10.0.0.2"},
+		{"10.0.0.255", "10.0.1.0"},
+		{"10.255.255.255", "11.0.0.0"},
+		{"fd00::ffff", "fd00::1:0"},
+	}
+	for _, tt := range tests {
+		if got := nextIP(net.ParseIP(tt.ip)); got.String() != tt.want {
+			t.Errorf("nextIP(%s) = %s, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestHopCount(t *testing.T) {
+	tests := []struct {
+		ttl  int
+		want int32
+	}{
+		{64, 0},
+		{63, 1},
+		{1, 63},
+		{128, 0},
+		{120, 8},
+		{65, 63},
+		{255, 0},
+		{250, 5},
+		{129, 126},
+		{256, 0},
+	}
+	for _, tt := range tests {
+		if got := hopCount(tt.ttl); got != tt.want {
+			t.Errorf("hopCount(%d) = %d, want %d", tt.ttl, got, tt.want)
+		}
+	}
+}
+
+func TestCheckResultsUpToDate(t *testing.T) {
+	config := &Configuration{NodeName: "node1", PodName: "pinger1"}
+	check := &kubeovnv1.ConnectivityCheck{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	tests := []struct {
+		name    string
+		results []kubeovnv1.ConnectivityCheckResult
+		sources []string
+		want    bool
+	}{
+		{
+			name: "no results and no sources",
+			want: true,
+		},
+		{
+			name:    "no results of the node",
+			results: []kubeovnv1.ConnectivityCheckResult{{Source: "pinger2", Node: "node2", ObservedGeneration: 2}},
+			sources: []string{"pinger1"},
+		},
+		{
+			name: "results of the current generation",
+			results: []kubeovnv1.ConnectivityCheckResult{
+				{Source: "pinger1", Node: "node1", Destination: "10.0.0.1", ObservedGeneration: 2},
+				{Source: "pinger1", Node: "node1", Destination: "10.0.0.2", ObservedGeneration: 2},
+				{Source: "pinger2", Node: "node2", ObservedGeneration: 1},
+			},
+			sources: []string{"pinger1"},
+			want:    true,
+		},
+		{
+			name:    "results of an old generation",
+			results: []kubeovnv1.ConnectivityCheckResult{{Source: "pinger1", Node: "node1", ObservedGeneration: 1}},
+			sources: []string{"pinger1"},
+		},
+		{
+			name:    "results of a node no longer running the check",
+			results: []kubeovnv1.ConnectivityCheckResult{{Source: "pinger1", Node: "node1", ObservedGeneration: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.DeepCopy()
+			c.Status.Results = tt.results
+			if got := checkResultsUpToDate(config, c, tt.sources); got != tt.want {
+				t.Errorf("checkResultsUpToDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSources(t *testing.T) {
+	config := &Configuration{NodeName: "node1", PodName: "pinger1"}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "frontend-1", Labels: map[string]string{"app": "frontend"}},
+			Spec:       v1.PodSpec{NodeName: "node1"},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "backend-1", Labels: map[string]string{"app": "backend"}},
+			Spec:       v1.PodSpec{NodeName: "node1"},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+	} {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	podsLister := listerv1.NewPodLister(indexer)
+
+	tests := []struct {
+		name    string
+		source  kubeovnv1.ConnectivityCheckSource
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "node of the pinger",
+			source: kubeovnv1.ConnectivityCheckSource{Node: "node1"},
+			want:   []string{"pinger1"},
+		},
+		{
+			name:   "other node",
+			source: kubeovnv1.ConnectivityCheckSource{Node: "node2"},
+		},
+		{
+			name:   "running pod on the node",
+			source: kubeovnv1.ConnectivityCheckSource{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}},
+			want:   []string{"pinger1"},
+		},
+		{
+			name:   "pod not running",
+			source: kubeovnv1.ConnectivityCheckSource{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "backend"}}},
+		},
+		{
+			name:   "no pod selected",
+			source: kubeovnv1.ConnectivityCheckSource{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+		},
+		{
+			name:    "no source",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &kubeovnv1.ConnectivityCheck{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "check"},
+				Spec:       kubeovnv1.ConnectivityCheckSpec{Source: tt.source},
+			}
+			got, err := checkSources(config, podsLister, check)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package pinger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+	"golang.org/x/sys/unix"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+var icmpEchoSeq uint32
+
+// icmpEcho sends an icmp echo request of the packet size and returns the rtt and whether the echo reply is received.
+// The request is sent through the interface if set, and with fragmentation disallowed if dontFragment is set.
+func icmpEcho(address, iface string, size int, dontFragment bool, timeout time.Duration) (time.Duration, bool, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return 0, false, fmt.Errorf("invalid ip %s", address)
+	}
+
+	network, proto, headerLen := "ip4:icmp", 1, ipv4.HeaderLen
+	var echoType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if util.CheckProtocol(address) == kubeovnv1.ProtocolIPv6 {
+		network, proto, headerLen = "ip6:ipv6-icmp", 58, ipv6.HeaderLen
+		echoType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+	if size < headerLen+8 {
+		return 0, false, fmt.Errorf("invalid packet size %d", size)
+	}
+
+	conn, err := net.ListenPacket(network, "")
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	rawConn, err := conn.(*net.IPConn).SyscallConn()
+	if err != nil {
+		return 0, false, err
+	}
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		if iface != "" {
+			if sockErr = unix.BindToDevice(int(fd), iface); sockErr != nil {
+				sockErr = fmt.Errorf("failed to bind to interface %s, %v", iface, sockErr)
+				return
+			}
+		}
+		if !dontFragment {
+			return
+		}
+		// the probe option sets DF and ignores the cached path mtu, so that packets larger
+		// than the path mtu are sent and dropped on the path instead of rejected locally
+		if proto == 1 {
+			sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_PROBE)
+		} else if sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, unix.IPV6_PMTUDISC_PROBE); sockErr == nil {
+			sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_DONTFRAG, 1)
+		}
+		if sockErr != nil {
+			sockErr = fmt.Errorf("failed to disallow fragmentation, %v", sockErr)
+		}
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if sockErr != nil {
+		return 0, false, sockErr
+	}
+
+	id, seq := os.Getpid()&0xffff, int(atomic.AddUint32(&icmpEchoSeq, 1)&0xffff)
+	msg := icmp.Message{
+		Type: echoType,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: make([]byte, size-headerLen-8)},
+	}
+	data, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, false, err
+	}
+	t1 := time.Now()
+	if _, err = conn.WriteTo(data, &net.IPAddr{IP: ip}); err != nil {
+		if errors.Is(err, syscall.EMSGSIZE) {
+			return 0, false, fmt.Errorf("packet size %d exceeds the mtu of the local route", size)
+		}
+		return 0, false, err
+	}
+
+	if err = conn.SetReadDeadline(t1.Add(timeout)); err != nil {
+		return 0, false, err
+	}
+	buf := make([]byte, size+headerLen)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return 0, false, nil
+			}
+			return 0, false, err
+		}
+		if peerIP := peer.(*net.IPAddr).IP; !peerIP.Equal(ip) {
+			continue
+		}
+		reply, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		if echo, ok := reply.Body.(*icmp.Echo); ok && echo.ID == id && echo.Seq == seq {
+			return time.Since(t1), true, nil
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package pinger
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 var (
 	ovsUpGauge = prometheus.NewGaugeVec(
@@ -213,6 +217,115 @@ var (
 			"src_pod_ip",
 			"target_address",
 		})
+	probeLatencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "pinger_probe_latency_ms",
+			Help:    "The latency ms histogram for tcp, udp and http probes",
+			Buckets: []float64{.25, .5, 1, 2, 5, 10, 30, 50, 100, 500},
+		},
+		[]string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"protocol",
+			"target_name",
+			"target_address",
+		})
+	probeLostCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pinger_probe_lost_total",
+			Help: "The lost count for tcp, udp and http probes",
+		}, []string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"protocol",
+			"target_name",
+			"target_address",
+		})
+	probeTotalCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pinger_probe_count_total",
+			Help: "The total count for tcp, udp and http probes",
+		}, []string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"protocol",
+			"target_name",
+			"target_address",
+		})
+	subnetPingLatencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "pinger_subnet_ping_latency_ms",
+			Help:    "The latency ms histogram for pings in the attached subnets of custom vpcs and underlay vlans",
+			Buckets: []float64{.25, .5, 1, 2, 5, 10, 30},
+		},
+		[]string{
+			"src_node_name",
+			"src_pod_ip",
+			"vpc",
+			"subnet",
+			"target_type",
+			"target_node_name",
+			"target_name",
+			"target_ip",
+		})
+	subnetPingLostCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pinger_subnet_ping_lost_total",
+			Help: "The lost count for pings in the attached subnets of custom vpcs and underlay vlans",
+		}, []string{
+			"src_node_name",
+			"src_pod_ip",
+			"vpc",
+			"subnet",
+			"target_type",
+			"target_node_name",
+			"target_name",
+			"target_ip",
+		})
+	subnetPingTotalCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pinger_subnet_ping_count_total",
+			Help: "The total count for pings in the attached subnets of custom vpcs and underlay vlans",
+		}, []string{
+			"src_node_name",
+			"src_pod_ip",
+			"vpc",
+			"subnet",
+			"target_type",
+			"target_node_name",
+			"target_name",
+			"target_ip",
+		})
+	pathMtuGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_path_mtu",
+			Help: "The largest packet size reaching the target with fragmentation disallowed, 0 if unreachable",
+		},
+		[]string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"target_type",
+			"target_name",
+			"target_address",
+			"pod_mtu",
+		})
+	pathMtuFailedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pinger_path_mtu_failed_total",
+			Help: "The count of packets of the pod mtu with fragmentation disallowed not reaching the target",
+		}, []string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"target_type",
+			"target_name",
+			"target_address",
+			"pod_mtu",
+		})
 
 	// OVS basic info
 	metricOvsHealthyStatus = prometheus.NewGaugeVec(
@@ -650,6 +763,14 @@ func InitPingerMetrics() {
 	prometheus.MustRegister(nodePingTotalCounter)
 	prometheus.MustRegister(externalPingLatencyHistogram)
 	prometheus.MustRegister(externalPingLostCounter)
+	prometheus.MustRegister(probeLatencyHistogram)
+	prometheus.MustRegister(probeLostCounter)
+	prometheus.MustRegister(probeTotalCounter)
+	prometheus.MustRegister(subnetPingLatencyHistogram)
+	prometheus.MustRegister(subnetPingLostCounter)
+	prometheus.MustRegister(subnetPingTotalCounter)
+	prometheus.MustRegister(pathMtuGauge)
+	prometheus.MustRegister(pathMtuFailedCounter)
 
 	// ovs status metrics
 	prometheus.MustRegister(metricOvsHealthyStatus)
@@ -814,3 +935,91 @@ func SetExternalPingMetrics(srcNodeName, srcNodeIP, srcPodIP, targetAddress stri
 		targetAddress,
 	).Add(float64(lost))
 }
+
+func SetProbeMetrics(srcNodeName, srcNodeIP, srcPodIP, protocol, targetName, targetAddress string, latency float64, lost, total int) {
+	if lost != total {
+		probeLatencyHistogram.WithLabelValues(
+			srcNodeName,
+			srcNodeIP,
+			srcPodIP,
+			protocol,
+			targetName,
+			targetAddress,
+		).Observe(latency)
+	}
+	probeLostCounter.WithLabelValues(
+		srcNodeName,
+		srcNodeIP,
+		srcPodIP,
+		protocol,
+		targetName,
+		targetAddress,
+	).Add(float64(lost))
+	probeTotalCounter.WithLabelValues(
+		srcNodeName,
+		srcNodeIP,
+		srcPodIP,
+		protocol,
+		targetName,
+		targetAddress,
+	).Add(float64(total))
+}
+
+func SetSubnetPingMetrics(srcNodeName, srcPodIP, vpc, subnet, targetType, targetNodeName, targetName, targetIP string, latency float64, lost, total int) {
+	if lost != total {
+		subnetPingLatencyHistogram.WithLabelValues(
+			srcNodeName,
+			srcPodIP,
+			vpc,
+			subnet,
+			targetType,
+			targetNodeName,
+			targetName,
+			targetIP,
+		).Observe(latency)
+	}
+	subnetPingLostCounter.WithLabelValues(
+		srcNodeName,
+		srcPodIP,
+		vpc,
+		subnet,
+		targetType,
+		targetNodeName,
+		targetName,
+		targetIP,
+	).Add(float64(lost))
+	subnetPingTotalCounter.WithLabelValues(
+		srcNodeName,
+		srcPodIP,
+		vpc,
+		subnet,
+		targetType,
+		targetNodeName,
+		targetName,
+		targetIP,
+	).Add(float64(total))
+}
+
+func SetPathMtuMetrics(srcNodeName, srcNodeIP, srcPodIP, targetType, targetName, targetAddress string, pathMtu, podMtu int, failed bool) {
+	pathMtuGauge.WithLabelValues(
+		srcNodeName,
+		srcNodeIP,
+		srcPodIP,
+		targetType,
+		targetName,
+		targetAddress,
+		strconv.Itoa(podMtu),
+	).Set(float64(pathMtu))
+	counter := pathMtuFailedCounter.WithLabelValues(
+		srcNodeName,
+		srcNodeIP,
+		srcPodIP,
+		targetType,
+		targetName,
+		targetAddress,
+		strconv.Itoa(podMtu),
+	)
+	if failed {
+		counter.Inc()
+	}
+}
@@ -0,0 +1,128 @@
+package pinger
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	pmtuProbeTimeout = time.Second
+	// minimum mtu of ipv4 hosts according to RFC791 and of ipv6 links according to RFC8200
+	minIPv4Mtu = 576
+	minIPv6Mtu = 1280
+)
+
+// pmtuProbe sends an icmp echo request of the packet size with fragmentation disallowed
+// and returns whether the echo reply is received
+func pmtuProbe(address string, size int) (bool, error) {
+	_, ok, err := icmpEcho(address, "", size, true, pmtuProbeTimeout)
+	return ok, err
+}
+
+// pathMtu returns the largest packet size up to the mtu that reaches the address with fragmentation disallowed
+func pathMtu(address string, mtu int) (int, error) {
+	ok, err := pmtuProbe(address, mtu)
+	if ok {
+		return mtu, nil
+	}
+	if err != nil {
+		klog.Warningf("failed to probe %s with %d bytes packets, %v", address, mtu, err)
+	}
+
+	minMtu := minIPv4Mtu
+	if util.CheckProtocol(address) == kubeovnv1.ProtocolIPv6 {
+		minMtu = minIPv6Mtu
+	}
+	if minMtu >= mtu {
+		return 0, fmt.Errorf("no reply of %d bytes packets", mtu)
+	}
+	if ok, err = pmtuProbe(address, minMtu); !ok {
+		if err == nil {
+			err = fmt.Errorf("no reply of %d bytes packets", minMtu)
+		}
+		return 0, err
+	}
+
+	// binary search the largest size replied
+	low, high := minMtu, mtu-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		if ok, _ = pmtuProbe(address, mid); ok {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, fmt.Errorf("%d bytes packets with fragmentation disallowed are dropped, path mtu is %d", mtu, low)
+}
+
+// podMtu returns the mtu of the pod nic
+func podMtu() (int, error) {
+	iface, err := net.InterfaceByName("eth0")
+	if err != nil {
+		return 0, err
+	}
+	return iface.MTU, nil
+}
+
+// checkPathMtu probes the path mtu to nodes, pods and external addresses at the mtu of the pod nic
+func checkPathMtu(config *Configuration) error {
+	klog.Infof("start to check path mtu")
+	mtu, err := podMtu()
+	if err != nil {
+		klog.Errorf("failed to get mtu of the pod nic, %v", err)
+		return err
+	}
+
+	var checkErr error
+	check := func(targetType, targetName, address string) {
+		if !util.ContainsString(config.PodProtocols, util.CheckProtocol(address)) {
+			return
+		}
+		pmtu, err := pathMtu(address, mtu)
+		if err != nil {
+			klog.Errorf("path mtu check to %s %s %s failed, %v", targetType, targetName, address, err)
+			checkErr = err
+		} else {
+			klog.Infof("path mtu to %s %s %s is %d", targetType, targetName, address, pmtu)
+		}
+		SetPathMtuMetrics(config.NodeName, config.HostIP, config.PodIP, targetType, targetName, address, pmtu, mtu, err != nil)
+	}
+
+	nodes, err := listNodes(config)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == v1.NodeInternalIP {
+				check("node", node.Name, addr.Address)
+			}
+		}
+	}
+
+	pods, err := listPeerPods(config)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		for _, podIP := range pod.Status.PodIPs {
+			check("pod", pod.Name, podIP.IP)
+		}
+	}
+
+	if config.ExternalAddress != "" {
+		for _, addr := range strings.Split(config.ExternalAddress, ",") {
+			check("external", addr, addr)
+		}
+	}
+	return checkErr
+}
@@ -0,0 +1,79 @@
+package pinger
+
+import (
+	"context"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// NetworkNodeConditionType is the node condition reporting the results of the pinger on the node
+const NetworkNodeConditionType v1.NodeConditionType = "KubeOvnNetworkReady"
+
+// nodeConditionFailureThreshold is the number of consecutive rounds with failures before the condition
+// is set to False, so that a single lost packet does not flip the condition
+const nodeConditionFailureThreshold = 3
+
+// failures of the checks reflected in the node condition, the details of which are only logged
+// so that the message does not change with the peers
+const (
+	failureOvs         = "ovs-vswitchd or ovsdb is not running"
+	failureOvnCtl      = "ovn-controller is not running"
+	failurePortBinding = "port binding check failed"
+	failurePodMesh     = "some pinger pods are unreachable"
+	failureNodeMesh    = "some nodes are unreachable"
+)
+
+func newEventRecorder(config *Configuration) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: config.KubeClient.CoreV1().Events("")})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "kube-ovn-pinger", Host: config.NodeName})
+}
+
+// networkCondition returns the network condition of the node by the failures of the checks in the latest round
+// and the number of consecutive rounds with failures, ok is false if the condition is to be left unchanged
+func networkCondition(failures []string, failedRounds int) (condition v1.NodeCondition, ok bool) {
+	condition.Type = NetworkNodeConditionType
+	if len(failures) == 0 {
+		condition.Status, condition.Reason = v1.ConditionTrue, "NetworkReady"
+		condition.Message = "ovs, ovn-controller, port binding and mesh checks passed"
+		return condition, true
+	}
+	if failedRounds < nodeConditionFailureThreshold {
+		return condition, false
+	}
+	condition.Status, condition.Reason = v1.ConditionFalse, "NetworkNotReady"
+	condition.Message = strings.Join(failures, "; ")
+	return condition, true
+}
+
+// updateNodeCondition sets the network condition of the node and records an event when the condition changes
+func updateNodeCondition(config *Configuration, recorder record.EventRecorder, condition v1.NodeCondition) error {
+	node, err := config.KubeClient.CoreV1().Nodes().Get(context.Background(), config.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	patch, err := util.GenNodeConditionPatch(node, condition)
+	if err != nil || patch == nil {
+		return err
+	}
+	if _, err = config.KubeClient.CoreV1().Nodes().Patch(context.Background(), node.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		return err
+	}
+
+	if condition.Status == v1.ConditionTrue {
+		recorder.Event(node, v1.EventTypeNormal, condition.Reason, condition.Message)
+	} else {
+		recorder.Event(node, v1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package pinger
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNetworkCondition(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     []string
+		failedRounds int
+		wantOk       bool
+		wantStatus   v1.ConditionStatus
+		wantMessage  string
+	}{
+		{
+			name:        "all checks passed",
+			wantOk:      true,
+			wantStatus:  v1.ConditionTrue,
+			wantMessage: "ovs, ovn-controller, port binding and mesh checks passed",
+		},
+		{
+			name:         "single failed round",
+			failures:     []string{failurePodMesh},
+			failedRounds: 1,
+		},
+		{
+			name:         "failed rounds below threshold",
+			failures:     []string{failurePodMesh},
+			failedRounds: nodeConditionFailureThreshold - 1,
+		},
+		{
+			name:         "failed rounds reach threshold",
+			failures:     []string{failureOvnCtl, failurePodMesh},
+			failedRounds: nodeConditionFailureThreshold,
+			wantOk:       true,
+			wantStatus:   v1.ConditionFalse,
+			wantMessage:  failureOvnCtl + "; " + failurePodMesh,
+		},
+		{
+			name:         "failed rounds above threshold",
+			failures:     []string{failureNodeMesh},
+			failedRounds: nodeConditionFailureThreshold + 5,
+			wantOk:       true,
+			wantStatus:   v1.ConditionFalse,
+			wantMessage:  failureNodeMesh,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, ok := networkCondition(tt.failures, tt.failedRounds)
+			if ok != tt.wantOk {
+				t.Fatalf("networkCondition() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if condition.Type != NetworkNodeConditionType || condition.Status != tt.wantStatus || condition.Message != tt.wantMessage {
+				t.Errorf("networkCondition() = %+v, want status %s message %q", condition, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -13,30 +13,63 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
 func StartPinger(config *Configuration, e *Exporter) {
+	var recorder record.EventRecorder
+	if config.Mode == "server" && config.EnableNodeCondition {
+		recorder = newEventRecorder(config)
+	}
+
 	errHappens := false
+	failedRounds := 0
 	for {
+		// failures of the node and the mesh reflected in the node condition
+		var failures []string
 		if config.NetworkMode == "kube-ovn" {
 			if checkOvs(config) != nil {
 				errHappens = true
+				failures = append(failures, failureOvs)
 			}
 			if checkOvnController(config) != nil {
 				errHappens = true
+				failures = append(failures, failureOvnCtl)
 			}
 			if checkPortBindings(config) != nil {
 				errHappens = true
+				failures = append(failures, failurePortBinding)
 			}
 			e.ovsMetricsUpdate()
 		}
 
+		if pingPods(config) != nil {
+			errHappens = true
+			failures = append(failures, failurePodMesh)
+		}
+		if pingNodes(config) != nil {
+			errHappens = true
+			failures = append(failures, failureNodeMesh)
+		}
 		if ping(config) != nil {
 			errHappens = true
 		}
+		if recorder != nil {
+			if len(failures) == 0 {
+				failedRounds = 0
+			} else {
+				failedRounds++
+				klog.Warningf("network checks failed in %d consecutive rounds: %s", failedRounds, strings.Join(failures, "; "))
+			}
+			if condition, ok := networkCondition(failures, failedRounds); ok {
+				if err := updateNodeCondition(config, recorder, condition); err != nil {
+					klog.Errorf("failed to update condition %s of node %s, %v", NetworkNodeConditionType, config.NodeName, err)
+				}
+			}
+		}
 		if config.Mode != "server" {
 			break
 		}
@@ -52,10 +85,10 @@ func ping(config *Configuration) error {
 	if checkApiServer(config) != nil {
 		errHappens = true
 	}
-	if pingPods(config) != nil {
+	if pingSubnets(config) != nil {
 		errHappens = true
 	}
-	if pingNodes(config) != nil {
+	if probePods(config) != nil {
 		errHappens = true
 	}
 	if internalNslookup(config) != nil {
@@ -73,22 +106,57 @@ func ping(config *Configuration) error {
 			errHappens = true
 		}
 	}
+
+	if probeTargets(config) != nil {
+		errHappens = true
+	}
+
+	if config.EnablePathMtuCheck {
+		if checkPathMtu(config) != nil {
+			errHappens = true
+		}
+	}
 	if errHappens {
 		return fmt.Errorf("ping failed")
 	}
 	return nil
 }
 
-func pingNodes(config *Configuration) error {
-	klog.Infof("start to check node connectivity")
+// listNodes returns all nodes of the cluster
+func listNodes(config *Configuration) ([]v1.Node, error) {
 	nodes, err := config.KubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		klog.Errorf("failed to list nodes, %v", err)
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// listPeerPods returns the pods of the pinger daemonset
+func listPeerPods(config *Configuration) ([]v1.Pod, error) {
+	ds, err := config.KubeClient.AppsV1().DaemonSets(config.DaemonSetNamespace).Get(context.Background(), config.DaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("failed to get peer ds: %v", err)
+		return nil, err
+	}
+	pods, err := config.KubeClient.CoreV1().Pods(config.DaemonSetNamespace).List(context.Background(), metav1.ListOptions{LabelSelector: labels.Set(ds.Spec.Selector.MatchLabels).String()})
+	if err != nil {
+		klog.Errorf("failed to list peer pods: %v", err)
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func pingNodes(config *Configuration) error {
+	klog.Infof("start to check node connectivity")
+	nodes, err := listNodes(config)
+	if err != nil {
 		return err
 	}
 
 	var pingErr error
-	for _, no := range nodes.Items {
+	var unreachable []string
+	for _, no := range nodes {
 		for _, addr := range no.Status.Addresses {
 			if addr.Type == v1.NodeInternalIP && util.ContainsString(config.PodProtocols, util.CheckProtocol(addr.Address)) {
 				func(nodeIP, nodeName string) {
@@ -108,7 +176,7 @@ func pingNodes(config *Configuration) error {
 					klog.Infof("ping node: %s %s, count: %d, loss count %d, average rtt %.2fms",
 						nodeName, nodeIP, pinger.Count, int(math.Abs(float64(stats.PacketsSent-stats.PacketsRecv))), float64(stats.AvgRtt)/float64(time.Millisecond))
 					if int(math.Abs(float64(stats.PacketsSent-stats.PacketsRecv))) != 0 {
-						pingErr = fmt.Errorf("ping failed")
+						unreachable = append(unreachable, fmt.Sprintf("%s %s", nodeName, nodeIP))
 					}
 					SetNodePingMetrics(
 						config.NodeName,
@@ -122,24 +190,22 @@ func pingNodes(config *Configuration) error {
 			}
 		}
 	}
+	if len(unreachable) != 0 {
+		return fmt.Errorf("nodes unreachable: %s", strings.Join(unreachable, ", "))
+	}
 	return pingErr
 }
 
 func pingPods(config *Configuration) error {
 	klog.Infof("start to check pod connectivity")
-	ds, err := config.KubeClient.AppsV1().DaemonSets(config.DaemonSetNamespace).Get(context.Background(), config.DaemonSetName, metav1.GetOptions{})
-	if err != nil {
-		klog.Errorf("failed to get peer ds: %v", err)
-		return err
-	}
-	pods, err := config.KubeClient.CoreV1().Pods(config.DaemonSetNamespace).List(context.Background(), metav1.ListOptions{LabelSelector: labels.Set(ds.Spec.Selector.MatchLabels).String()})
+	pods, err := listPeerPods(config)
 	if err != nil {
-		klog.Errorf("failed to list peer pods: %v", err)
 		return err
 	}
 
 	var pingErr error
-	for _, pod := range pods.Items {
+	var unreachable []string
+	for _, pod := range pods {
 		for _, podIP := range pod.Status.PodIPs {
 			if util.ContainsString(config.PodProtocols, util.CheckProtocol(podIP.IP)) {
 				func(podIp, podName, nodeIP, nodeName string) {
@@ -159,7 +225,7 @@ func pingPods(config *Configuration) error {
 					klog.Infof("ping pod: %s %s, count: %d, loss count %d, average rtt %.2fms",
 						podName, podIp, pinger.Count, int(math.Abs(float64(stats.PacketsSent-stats.PacketsRecv))), float64(stats.AvgRtt)/float64(time.Millisecond))
 					if int(math.Abs(float64(stats.PacketsSent-stats.PacketsRecv))) != 0 {
-						pingErr = fmt.Errorf("ping failed")
+						unreachable = append(unreachable, fmt.Sprintf("%s %s", podName, podIp))
 					}
 					SetPodPingMetrics(
 						config.NodeName,
@@ -175,6 +241,9 @@ func pingPods(config *Configuration) error {
 			}
 		}
 	}
+	if len(unreachable) != 0 {
+		return fmt.Errorf("pods unreachable: %s", strings.Join(unreachable, ", "))
+	}
 	return pingErr
 }
 
@@ -0,0 +1,228 @@
+package pinger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	ProbeProtocolTCP  = "tcp"
+	ProbeProtocolUDP  = "udp"
+	ProbeProtocolHTTP = "http"
+
+	probeCount   = 3
+	probeTimeout = 3 * time.Second
+	probePayload = "kube-ovn-pinger"
+)
+
+// ProbeTarget is a target of tcp connect, udp echo or http get probes
+type ProbeTarget struct {
+	Protocol string
+	// Address is the host and port of the target
+	Address string
+	// Path is the path of http get probes
+	Path string
+}
+
+func (t *ProbeTarget) String() string {
+	return fmt.Sprintf("%s://%s%s", t.Protocol, t.Address, t.Path)
+}
+
+// ParseProbeTarget parses targets in the form of tcp://host:port, udp://host:port or http://host:port/path
+func ParseProbeTarget(target string) (*ProbeTarget, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe target %s: %v", target, err)
+	}
+	switch u.Scheme {
+	case ProbeProtocolTCP, ProbeProtocolUDP, ProbeProtocolHTTP:
+	default:
+		return nil, fmt.Errorf("invalid protocol of probe target %s, must be %s, %s or %s", target, ProbeProtocolTCP, ProbeProtocolUDP, ProbeProtocolHTTP)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, fmt.Errorf("invalid probe target %s, host and port are required", target)
+	}
+	return &ProbeTarget{Protocol: u.Scheme, Address: u.Host, Path: u.Path}, nil
+}
+
+// probeOnce returns the latency of a single probe to the target
+func probeOnce(target *ProbeTarget, timeout time.Duration) (time.Duration, error) {
+	t1 := time.Now()
+	switch target.Protocol {
+	case ProbeProtocolTCP:
+		conn, err := net.DialTimeout("tcp", target.Address, timeout)
+		if err != nil {
+			return 0, err
+		}
+		elapsed := time.Since(t1)
+		_ = conn.Close()
+		return elapsed, nil
+	case ProbeProtocolUDP:
+		conn, err := net.DialTimeout("udp", target.Address, timeout)
+		if err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+		if err = conn.SetDeadline(t1.Add(timeout)); err != nil {
+			return 0, err
+		}
+		if _, err = conn.Write([]byte(probePayload)); err != nil {
+			return 0, err
+		}
+		buf := make([]byte, len(probePayload))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.Equal(buf[:n], []byte(probePayload)) {
+			return 0, fmt.Errorf("unexpected udp echo reply %q", buf[:n])
+		}
+		return time.Since(t1), nil
+	case ProbeProtocolHTTP:
+		client := &http.Client{Timeout: timeout}
+		resp, err := client.Get(fmt.Sprintf("http://%s%s", target.Address, target.Path))
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode >= http.StatusBadRequest {
+			return 0, fmt.Errorf("unexpected http status %s", resp.Status)
+		}
+		return time.Since(t1), nil
+	}
+	return 0, fmt.Errorf("unknown probe protocol %s", target.Protocol)
+}
+
+// probe probes the target several times and returns the average latency of succeeded probes
+// and the number of failed probes
+func probe(target *ProbeTarget, count int, timeout time.Duration) (time.Duration, int, error) {
+	var total time.Duration
+	var lost int
+	var lastErr error
+	for i := 0; i < count; i++ {
+		latency, err := probeOnce(target, timeout)
+		if err != nil {
+			lost++
+			lastErr = err
+			continue
+		}
+		total += latency
+	}
+	if lost == count {
+		return 0, lost, lastErr
+	}
+	return total / time.Duration(count-lost), lost, lastErr
+}
+
+// StartProbeServer serves http and tcp probes and echoes udp probes of other pingers on the probe port
+func StartProbeServer(config *Configuration) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	go func() {
+		server := &http.Server{
+			Addr:              fmt.Sprintf(":%d", config.ProbePort),
+			Handler:           mux,
+			ReadHeaderTimeout: 3 * time.Second,
+		}
+		klog.Fatal(server.ListenAndServe())
+	}()
+
+	go func() {
+		conn, err := net.ListenPacket("udp", fmt.Sprintf(":%d", config.ProbePort))
+		if err != nil {
+			klog.Fatalf("failed to listen on udp port %d, %v", config.ProbePort, err)
+		}
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				klog.Errorf("failed to read udp probe, %v", err)
+				continue
+			}
+			if _, err = conn.WriteTo(buf[:n], addr); err != nil {
+				klog.Errorf("failed to reply udp probe from %s, %v", addr, err)
+			}
+		}
+	}()
+}
+
+// probePods probes the probe port of other pingers with the configured protocols
+func probePods(config *Configuration) error {
+	if len(config.ProbeProtocols) == 0 {
+		return nil
+	}
+
+	klog.Infof("start to probe pods with %v", config.ProbeProtocols)
+	pods, err := listPeerPods(config)
+	if err != nil {
+		return err
+	}
+
+	var probeErr error
+	for _, pod := range pods {
+		for _, podIP := range pod.Status.PodIPs {
+			if !util.ContainsString(config.PodProtocols, util.CheckProtocol(podIP.IP)) {
+				continue
+			}
+			for _, protocol := range config.ProbeProtocols {
+				target := &ProbeTarget{
+					Protocol: protocol,
+					Address:  net.JoinHostPort(podIP.IP, strconv.Itoa(config.ProbePort)),
+				}
+				if protocol == ProbeProtocolHTTP {
+					target.Path = "/"
+				}
+				if err = probeTarget(config, target, pod.Name); err != nil {
+					probeErr = err
+				}
+			}
+		}
+	}
+	return probeErr
+}
+
+// probeTargets probes the configured targets, e.g. cluster ips of services and external addresses
+func probeTargets(config *Configuration) error {
+	var probeErr error
+	for _, target := range config.ProbeTargets {
+		if err := probeTarget(config, target, target.String()); err != nil {
+			probeErr = err
+		}
+	}
+	return probeErr
+}
+
+func probeTarget(config *Configuration, target *ProbeTarget, targetName string) error {
+	latency, lost, err := probe(target, probeCount, probeTimeout)
+	klog.Infof("%s probe %s %s, count: %d, loss count %d, average latency %.2fms",
+		strings.ToUpper(target.Protocol), targetName, target.Address, probeCount, lost, float64(latency)/float64(time.Millisecond))
+	SetProbeMetrics(
+		config.NodeName,
+		config.HostIP,
+		config.PodIP,
+		target.Protocol,
+		targetName,
+		target.Address,
+		float64(latency)/float64(time.Millisecond),
+		lost,
+		probeCount)
+	if lost != 0 {
+		klog.Errorf("%s probe %s %s failed, %v", target.Protocol, targetName, target.Address, err)
+		return fmt.Errorf("probe failed")
+	}
+	return nil
+}
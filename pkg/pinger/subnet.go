@@ -0,0 +1,178 @@
+package pinger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// attachment is a nic of the pinger pod in a subnet of a custom vpc or an underlay vlan
+type attachment struct {
+	provider string
+	subnet   string
+	vpc      string
+	gateways []string
+	ips      []string
+	iface    string
+}
+
+// podAttachmentIPs returns the ips of the attachment networks of the pod by provider
+func podAttachmentIPs(pod *v1.Pod) map[string][]string {
+	networks, err := util.ParsePodNetworkAnnotation(pod.Annotations[util.AttachmentNetworkAnnotation], pod.Namespace)
+	if err != nil {
+		klog.Errorf("failed to parse attachment networks of pod %s/%s, %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	ips := make(map[string][]string, len(networks))
+	for _, network := range networks {
+		provider := fmt.Sprintf("%s.%s.%s", network.Name, network.Namespace, util.OvnProvider)
+		if pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, provider)] != "true" {
+			continue
+		}
+		if ip := pod.Annotations[fmt.Sprintf(util.IpAddressAnnotationTemplate, provider)]; ip != "" {
+			ips[provider] = strings.Split(ip, ",")
+		}
+	}
+	return ips
+}
+
+// interfaceByIP returns the name of the nic with the ip in the pod
+func interfaceByIP(ip string) (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(net.ParseIP(ip)) {
+				return iface.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no nic with ip %s", ip)
+}
+
+// podAttachments returns the attachments of the pinger pod
+func podAttachments(config *Configuration, pod *v1.Pod) ([]*attachment, error) {
+	var attachments []*attachment
+	for provider, ips := range podAttachmentIPs(pod) {
+		subnetName := pod.Annotations[fmt.Sprintf(util.LogicalSwitchAnnotationTemplate, provider)]
+		subnet, err := config.KubeOvnClient.KubeovnV1().Subnets().Get(context.Background(), subnetName, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("failed to get subnet %s of attachment %s, %v", subnetName, provider, err)
+			return nil, err
+		}
+		iface, err := interfaceByIP(ips[0])
+		if err != nil {
+			klog.Errorf("failed to get nic of attachment %s, %v", provider, err)
+			return nil, err
+		}
+		vpc := subnet.Spec.Vpc
+		if vpc == "" {
+			vpc = util.DefaultVpc
+		}
+		attachments = append(attachments, &attachment{
+			provider: provider,
+			subnet:   subnet.Name,
+			vpc:      vpc,
+			gateways: strings.Split(subnet.Spec.Gateway, ","),
+			ips:      ips,
+			iface:    iface,
+		})
+	}
+	return attachments, nil
+}
+
+// pingSubnets pings the gateways and the pingers in the subnets of the attachments of the pinger pod
+// through the nics of the attachments, so that the mesh is also checked in custom vpcs and underlay vlans
+func pingSubnets(config *Configuration) error {
+	pods, err := listPeerPods(config)
+	if err != nil {
+		return err
+	}
+	var self *v1.Pod
+	for i := range pods {
+		if pods[i].Name == config.PodName {
+			self = &pods[i]
+			break
+		}
+	}
+	if self == nil || len(podAttachmentIPs(self)) == 0 {
+		return nil
+	}
+
+	klog.Infof("start to check connectivity of attached subnets")
+	attachments, err := podAttachments(config, self)
+	if err != nil {
+		return err
+	}
+
+	var pingErr error
+	for _, a := range attachments {
+		protocols := make([]string, 0, len(a.ips))
+		for _, ip := range a.ips {
+			protocols = append(protocols, util.CheckProtocol(ip))
+		}
+		for i, ip := range a.ips {
+			ping := func(targetType, targetNodeName, targetName, targetIP string) {
+				if util.CheckProtocol(targetIP) != protocols[i] {
+					return
+				}
+				if err := pingSubnetTarget(config, a, ip, targetType, targetNodeName, targetName, targetIP); err != nil {
+					pingErr = err
+				}
+			}
+			for _, gw := range a.gateways {
+				ping("gateway", "", a.subnet, gw)
+			}
+			for _, pod := range pods {
+				if pod.Name == config.PodName || pod.Annotations[fmt.Sprintf(util.LogicalSwitchAnnotationTemplate, a.provider)] != a.subnet {
+					continue
+				}
+				for _, podIP := range podAttachmentIPs(&pod)[a.provider] {
+					ping("pod", pod.Spec.NodeName, pod.Name, podIP)
+				}
+			}
+		}
+	}
+	return pingErr
+}
+
+func pingSubnetTarget(config *Configuration, a *attachment, srcIP, targetType, targetNodeName, targetName, targetIP string) error {
+	var total time.Duration
+	var lost int
+	for i := 0; i < probeCount; i++ {
+		rtt, ok, err := icmpEcho(targetIP, a.iface, 64, false, time.Second)
+		if err != nil {
+			klog.Errorf("failed to ping %s %s in subnet %s via %s, %v", targetType, targetIP, a.subnet, a.iface, err)
+		}
+		if !ok {
+			lost++
+			continue
+		}
+		total += rtt
+	}
+	var latency float64
+	if lost != probeCount {
+		latency = float64(total/time.Duration(probeCount-lost)) / float64(time.Millisecond)
+	}
+	klog.Infof("ping %s in vpc %s subnet %s: %s %s, count: %d, loss count %d, average rtt %.2fms",
+		targetType, a.vpc, a.subnet, targetName, targetIP, probeCount, lost, latency)
+	SetSubnetPingMetrics(config.NodeName, srcIP, a.vpc, a.subnet, targetType, targetNodeName, targetName, targetIP, latency, lost, probeCount)
+	if lost != 0 {
+		return fmt.Errorf("ping failed")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package pinger
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func TestPodAttachmentIPs(t *testing.T) {
+	provider1 := fmt.Sprintf("attach1.kube-system.%s", util.OvnProvider)
+	provider2 := fmt.Sprintf("attach2.default.%s", util.OvnProvider)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string][]string
+	}{
+		{
+			name: "no attachments",
+			want: map[string][]string{},
+		},
+		{
+			name:        "invalid attachment annotation",
+			annotations: map[string]string{util.AttachmentNetworkAnnotation: "[invalid"},
+		},
+		{
+			name: "attachments in the namespace of the pod and others",
+			annotations: map[string]string{
+				util.AttachmentNetworkAnnotation:                                "attach1, default/attach2",
+				fmt.Sprintf(util.AllocatedAnnotationTemplate, provider1):        "true",
+				fmt.Sprintf(util.IpAddressAnnotationTemplate, provider1):        "10.1.0.2",
+				fmt.Sprintf(util.AllocatedAnnotationTemplate, provider2):        "true",
+				fmt.Sprintf(util.IpAddressAnnotationTemplate, provider2):        "10.2.0.2,fd00:2::2",
+				fmt.Sprintf(util.IpAddressAnnotationTemplate, util.OvnProvider): "10.16.0.2",
+			},
+			want: map[string][]string{
+				provider1: {"10.1.0.2"},
+				provider2: {"10.2.0.2", "fd00:2::2"},
+			},
+		},
+		{
+			name: "attachment not allocated",
+			annotations: map[string]string{
+				util.AttachmentNetworkAnnotation:                         "attach1",
+				fmt.Sprintf(util.IpAddressAnnotationTemplate, provider1): "10.1.0.2",
+			},
+			want: map[string][]string{},
+		},
+		{
+			name: "attachment without ip",
+			annotations: map[string]string{
+				util.AttachmentNetworkAnnotation:                         "attach1",
+				fmt.Sprintf(util.AllocatedAnnotationTemplate, provider1): "true",
+			},
+			want: map[string][]string{},
+		},
+		{
+			name: "attachment of other cni",
+			annotations: map[string]string{
+				util.AttachmentNetworkAnnotation:                         "macvlan",
+				"macvlan.kube-system.kubernetes.io/allocated":            "true",
+				"macvlan.kube-system.kubernetes.io/ip_address":           "192.168.0.2",
+				fmt.Sprintf(util.AllocatedAnnotationTemplate, provider1): "true",
+				fmt.Sprintf(util.IpAddressAnnotationTemplate, provider1): "10.1.0.2",
+			},
+			want: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "pinger", Annotations: tt.annotations}}
+			if got := podAttachmentIPs(pod); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("podAttachmentIPs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
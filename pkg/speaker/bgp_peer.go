@@ -0,0 +1,302 @@
+package speaker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"time"
+
+	bgpapi "github.com/osrg/gobgp/v3/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// bgpPeerState is a session configured from a BgpPeer, the password secret is only read
+// again when the BgpPeer changes
+type bgpPeerState struct {
+	name            string
+	resourceVersion string
+}
+
+func (c *Controller) nodeSelectedByBgpPeer(peer *kubeovnv1.BgpPeer, nodeLabels labels.Set) (bool, error) {
+	if peer.Spec.NodeSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(peer.Spec.NodeSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(nodeLabels), nil
+}
+
+// syncBgpPeers configures sessions with the neighbors of BgpPeers selecting the node,
+// learns routes into vpcs and reports the states of the sessions in the status of BgpPeers
+func (c *Controller) syncBgpPeers() {
+	node, err := c.nodesLister.Get(c.config.NodeName)
+	if err != nil {
+		klog.Errorf("failed to get node %s, %v", c.config.NodeName, err)
+		return
+	}
+	peers, err := c.bgpPeersLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list bgp peers, %v", err)
+		return
+	}
+
+	selected := make(map[string]*kubeovnv1.BgpPeer, len(peers))
+	for _, peer := range peers {
+		ok, err := c.nodeSelectedByBgpPeer(peer, labels.Set(node.Labels))
+		if err != nil {
+			klog.Errorf("invalid node selector of bgp peer %s, %v", peer.Name, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		address := peer.Spec.NeighborAddress
+		if net.ParseIP(address) == nil {
+			klog.Errorf("invalid neighbor address %s of bgp peer %s", address, peer.Name)
+			continue
+		}
+		if address == c.config.NeighborAddress || address == c.config.NeighborIPv6Address {
+			klog.Warningf("neighbor %s of bgp peer %s is already configured by flags", address, peer.Name)
+			continue
+		}
+		if p := selected[address]; p != nil {
+			klog.Warningf("neighbor %s of bgp peer %s is already configured by bgp peer %s", address, peer.Name, p.Name)
+			continue
+		}
+		selected[address] = peer
+	}
+
+	for address, state := range c.bgpPeers {
+		if peer := selected[address]; peer != nil && peer.Name == state.name {
+			continue
+		}
+		klog.Infof("delete bgp neighbor %s of bgp peer %s", address, state.name)
+		if err = c.config.BgpServer.DeletePeer(context.Background(), &bgpapi.DeletePeerRequest{Address: address}); err != nil {
+			klog.Errorf("failed to delete bgp neighbor %s, %v", address, err)
+			continue
+		}
+		delete(c.bgpPeers, address)
+	}
+
+	for address, peer := range selected {
+		state := c.bgpPeers[address]
+		if state != nil && state.resourceVersion == peer.ResourceVersion {
+			continue
+		}
+		p, err := c.genBgpPeer(peer)
+		if err != nil {
+			klog.Errorf("failed to generate bgp neighbor of bgp peer %s, %v", peer.Name, err)
+			continue
+		}
+		if state != nil {
+			klog.Infof("update bgp neighbor %s of bgp peer %s", address, peer.Name)
+			if err = c.config.BgpServer.DeletePeer(context.Background(), &bgpapi.DeletePeerRequest{Address: address}); err != nil {
+				klog.Errorf("failed to delete bgp neighbor %s, %v", address, err)
+				continue
+			}
+			delete(c.bgpPeers, address)
+		} else {
+			klog.Infof("add bgp neighbor %s of bgp peer %s", address, peer.Name)
+		}
+		if err = c.config.BgpServer.AddPeer(context.Background(), &bgpapi.AddPeerRequest{Peer: p}); err != nil {
+			klog.Errorf("failed to add bgp neighbor %s, %v", address, err)
+			continue
+		}
+		c.bgpPeers[address] = &bgpPeerState{name: peer.Name, resourceVersion: peer.ResourceVersion}
+	}
+
+	configured := make(map[string]*kubeovnv1.BgpPeer, len(c.bgpPeers))
+	for address, peer := range selected {
+		if state := c.bgpPeers[address]; state != nil && state.name == peer.Name {
+			configured[address] = peer
+		}
+	}
+	c.syncBgpLearnedRoutes(configured)
+
+	sessions := make(map[string]*bgpapi.Peer, len(c.bgpPeers))
+	err = c.config.BgpServer.ListPeer(context.Background(), &bgpapi.ListPeerRequest{EnableAdvertised: true}, func(p *bgpapi.Peer) {
+		if p.Conf != nil {
+			sessions[p.Conf.NeighborAddress] = p
+		}
+	})
+	if err != nil {
+		klog.Errorf("failed to list bgp neighbors, %v", err)
+		return
+	}
+
+	for _, peer := range peers {
+		var session *kubeovnv1.BgpPeerSession
+		if state := c.bgpPeers[peer.Spec.NeighborAddress]; state != nil && state.name == peer.Name {
+			session = genBgpPeerSession(c.config.NodeName, sessions[peer.Spec.NeighborAddress])
+		}
+		if err = c.updateBgpPeerSession(peer, session); err != nil {
+			klog.Errorf("failed to update status of bgp peer %s, %v", peer.Name, err)
+		}
+	}
+}
+
+func (c *Controller) genBgpPeer(peer *kubeovnv1.BgpPeer) (*bgpapi.Peer, error) {
+	holdTime := c.config.HoldTime
+	if peer.Spec.HoldTime != 0 {
+		if peer.Spec.HoldTime < 3 || peer.Spec.HoldTime > 65535 {
+			return nil, fmt.Errorf("the hold time must be in the range 3s to 65535s")
+		}
+		holdTime = float64(peer.Spec.HoldTime)
+	}
+
+	p := &bgpapi.Peer{
+		Timers: &bgpapi.Timers{Config: &bgpapi.TimersConfig{HoldTime: uint64(holdTime)}},
+		Conf: &bgpapi.PeerConf{
+			NeighborAddress: peer.Spec.NeighborAddress,
+			PeerAsn:         peer.Spec.NeighborAs,
+		},
+		Transport: &bgpapi.Transport{
+			PassiveMode: peer.Spec.PassiveMode,
+		},
+	}
+	if peer.Spec.EbgpMultihop > DefaultEbgpMultiHop {
+		p.EbgpMultihop = &bgpapi.EbgpMultihop{
+			Enabled:     true,
+			MultihopTtl: uint32(peer.Spec.EbgpMultihop),
+		}
+	}
+	if ref := peer.Spec.PasswordSecret; ref != nil {
+		// the speaker is only allowed to read secrets of its own namespace
+		if ref.Namespace != c.config.PodNamespace {
+			return nil, fmt.Errorf("the password secret %s/%s must be in namespace %s", ref.Namespace, ref.Name, c.config.PodNamespace)
+		}
+		secret, err := c.config.KubeClient.CoreV1().Secrets(ref.Namespace).Get(context.Background(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("failed to get secret %s/%s, %v", ref.Namespace, ref.Name, err)
+			return nil, err
+		}
+		password, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in secret %s/%s", ref.Key, ref.Namespace, ref.Name)
+		}
+		p.Conf.AuthPassword = string(password)
+	}
+	if gr := peer.Spec.GracefulRestart; gr != nil {
+		restartTime, deferralTime := DefaultGracefulRestartTime, DefaultGracefulRestartDeferralTime
+		if gr.RestartTime != 0 {
+			restartTime = time.Duration(gr.RestartTime) * time.Second
+		}
+		if gr.DeferralTime != 0 {
+			deferralTime = time.Duration(gr.DeferralTime) * time.Second
+		}
+		if restartTime > time.Second*4095 || restartTime <= 0 {
+			return nil, fmt.Errorf("restart time should be less than 4095 seconds or more than 0")
+		}
+		if deferralTime > time.Hour*18 || deferralTime <= 0 {
+			return nil, fmt.Errorf("deferral time should be less than 18 hours or more than 0")
+		}
+		p.GracefulRestart = &bgpapi.GracefulRestart{
+			Enabled:         true,
+			RestartTime:     uint32(restartTime.Seconds()),
+			DeferralTime:    uint32(deferralTime.Seconds()),
+			LocalRestarting: true,
+		}
+	}
+
+	protocols := peer.Spec.AddressFamilies
+	if len(protocols) == 0 {
+		protocols = []string{util.CheckProtocol(peer.Spec.NeighborAddress)}
+	}
+	for _, protocol := range protocols {
+		family := bgpFamilies[protocol]
+		if protocol == EvpnFamily {
+			family = evpnFamily
+		}
+		if family == nil {
+			return nil, fmt.Errorf("invalid address family %s", protocol)
+		}
+		afiSafi := &bgpapi.AfiSafi{
+			Config: &bgpapi.AfiSafiConfig{
+				Family:  family,
+				Enabled: true,
+			},
+		}
+		if p.GracefulRestart != nil {
+			afiSafi.MpGracefulRestart = &bgpapi.MpGracefulRestart{
+				Config: &bgpapi.MpGracefulRestartConfig{
+					Enabled: true,
+				},
+			}
+		}
+		p.AfiSafis = append(p.AfiSafis, afiSafi)
+	}
+	return p, nil
+}
+
+func genBgpPeerSession(node string, p *bgpapi.Peer) *kubeovnv1.BgpPeerSession {
+	session := &kubeovnv1.BgpPeerSession{Node: node, State: bgpapi.PeerState_UNKNOWN.String()}
+	if p == nil {
+		return session
+	}
+	if p.State != nil {
+		session.State = p.State.SessionState.String()
+	}
+	for _, afiSafi := range p.AfiSafis {
+		if afiSafi.State != nil {
+			session.ReceivedPrefixes += afiSafi.State.Received
+			session.AdvertisedPrefixes += afiSafi.State.Advertised
+		}
+	}
+	return session
+}
+
+// updateBgpPeerSession sets the session of the node in the status of the BgpPeer,
+// the session is removed if it is nil
+func (c *Controller) updateBgpPeerSession(peer *kubeovnv1.BgpPeer, session *kubeovnv1.BgpPeerSession) error {
+	var current *kubeovnv1.BgpPeerSession
+	for i := range peer.Status.Sessions {
+		if peer.Status.Sessions[i].Node == c.config.NodeName {
+			current = &peer.Status.Sessions[i]
+			break
+		}
+	}
+	if current == nil && session == nil {
+		return nil
+	}
+	if current != nil && session != nil {
+		session.LastUpdateTime = current.LastUpdateTime
+		if reflect.DeepEqual(current, session) {
+			return nil
+		}
+	}
+	if session != nil {
+		session.LastUpdateTime = metav1.Now()
+	}
+
+	// speakers on all selected nodes update the status, so always start from the latest version
+	latest, err := c.config.KubeOvnClient.KubeovnV1().BgpPeers().Get(context.Background(), peer.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	newSessions := make([]kubeovnv1.BgpPeerSession, 0, len(latest.Status.Sessions)+1)
+	for _, s := range latest.Status.Sessions {
+		if s.Node != c.config.NodeName {
+			newSessions = append(newSessions, s)
+		}
+	}
+	if session != nil {
+		newSessions = append(newSessions, *session)
+	}
+	sort.Slice(newSessions, func(i, j int) bool { return newSessions[i].Node < newSessions[j].Node })
+	latest.Status.Sessions = newSessions
+	_, err = c.config.KubeOvnClient.KubeovnV1().BgpPeers().UpdateStatus(context.Background(), latest, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,83 @@
+package speaker
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func TestGenBgpPeer(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bgp-password", Namespace: "kube-system"},
+		Data:       map[string][]byte{"rack1-tor1": []byte("secret")},
+	}
+	c := &Controller{config: &Configuration{
+		HoldTime:     90,
+		PodNamespace: "kube-system",
+		KubeClient:   fake.NewSimpleClientset(secret),
+	}}
+
+	tests := []struct {
+		name         string
+		spec         kubeovnv1.BgpPeerSpec
+		wantHoldTime uint64
+		wantPassword string
+		wantErr      bool
+	}{
+		{
+			name:         "default hold time",
+			spec:         kubeovnv1.BgpPeerSpec{NeighborAddress: "10.32.32.1", NeighborAs: 65030},
+			wantHoldTime: 90,
+		},
+		{
+			name:         "maximum hold time",
+			spec:         kubeovnv1.BgpPeerSpec{NeighborAddress: "10.32.32.1", NeighborAs: 65030, HoldTime: 65535},
+			wantHoldTime: 65535,
+		},
+		{
+			name:    "hold time overflow",
+			spec:    kubeovnv1.BgpPeerSpec{NeighborAddress: "10.32.32.1", NeighborAs: 65030, HoldTime: 65536},
+			wantErr: true,
+		},
+		{
+			name: "password secret",
+			spec: kubeovnv1.BgpPeerSpec{
+				NeighborAddress: "10.32.32.1",
+				NeighborAs:      65030,
+				PasswordSecret:  &kubeovnv1.BgpPeerSecretRef{Namespace: "kube-system", Name: "bgp-password", Key: "rack1-tor1"},
+			},
+			wantHoldTime: 90,
+			wantPassword: "secret",
+		},
+		{
+			name: "password secret of other namespace",
+			spec: kubeovnv1.BgpPeerSpec{
+				NeighborAddress: "10.32.32.1",
+				NeighborAs:      65030,
+				PasswordSecret:  &kubeovnv1.BgpPeerSecretRef{Namespace: "default", Name: "bgp-password", Key: "rack1-tor1"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := c.genBgpPeer(&kubeovnv1.BgpPeer{Spec: tt.spec})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("genBgpPeer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if p.Timers.Config.HoldTime != tt.wantHoldTime {
+				t.Errorf("genBgpPeer() hold time = %d, want %d", p.Timers.Config.HoldTime, tt.wantHoldTime)
+			}
+			if p.Conf.AuthPassword != tt.wantPassword {
+				t.Errorf("genBgpPeer() password = %q, want %q", p.Conf.AuthPassword, tt.wantPassword)
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 	"time"
 
 	api "github.com/osrg/gobgp/v3/api"
@@ -18,7 +20,9 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	clientset "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned"
+	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
 const (
@@ -30,6 +34,11 @@ const (
 	DefaultGracefulRestartDeferralTime = 360 * time.Second
 	DefaultGracefulRestartTime         = 90 * time.Second
 	DefaultEbgpMultiHop                = 1
+
+	// AnnounceSvcModeCluster announces service ips from all speakers
+	AnnounceSvcModeCluster = "cluster"
+	// AnnounceSvcModeLocal announces service ips only from speakers on nodes with ready endpoints of the service
+	AnnounceSvcModeLocal = "local"
 )
 
 type Configuration struct {
@@ -37,17 +46,25 @@ type Configuration struct {
 	GrpcPort                    uint32
 	ClusterAs                   uint32
 	RouterId                    string
+	NodeName                    string
+	PodNamespace                string
+	NodeIPs                     map[string]string
 	NeighborAddress             string
+	NeighborIPv6Address         string
 	NeighborAs                  uint32
 	AuthPassword                string
 	HoldTime                    float64
 	BgpServer                   *gobgp.BgpServer
 	AnnounceClusterIP           bool
+	AnnounceLbIP                bool
+	AnnounceExternalIP          bool
+	AnnounceSvcMode             string
 	GracefulRestart             bool
 	GracefulRestartDeferralTime time.Duration
 	GracefulRestartTime         time.Duration
 	PassiveMode                 bool
 	EbgpMultihopTtl             uint8
+	EnableEvpn                  bool
 
 	KubeConfigFile string
 	KubeClient     kubernetes.Interface
@@ -62,11 +79,15 @@ func ParseFlags() (*Configuration, error) {
 		argGracefulRestartDeferralTime = pflag.Duration("graceful-restart-deferral-time", DefaultGracefulRestartDeferralTime, "BGP Graceful restart deferral time according to RFC4724 4.1, maximum 18h.")
 		argGracefulRestart             = pflag.BoolP("graceful-restart", "", false, "Enables the BGP Graceful Restart  so that routes are preserved on unexpected restarts")
 		argAnnounceClusterIP           = pflag.BoolP("announce-cluster-ip", "", false, "The Cluster IP of the service to  announce to the BGP peers.")
+		argAnnounceLbIP                = pflag.BoolP("announce-lb-ip", "", false, "The ingress IP of the LoadBalancer service to announce to the BGP peers.")
+		argAnnounceExternalIP          = pflag.BoolP("announce-external-ip", "", false, "The external IPs of the service to announce to the BGP peers.")
+		argAnnounceSvcMode             = pflag.String("announce-svc-mode", AnnounceSvcModeCluster, "The mode to announce service ips, cluster: announce from all nodes, local: announce only from nodes with ready endpoints of the service")
 		argGrpcHost                    = pflag.String("grpc-host", "127.0.0.1", "The host address for grpc to listen, default: 127.0.0.1")
 		argGrpcPort                    = pflag.Uint32("grpc-port", DefaultBGPGrpcPort, "The port for grpc to listen, default:50051")
 		argClusterAs                   = pflag.Uint32("cluster-as", DefaultBGPClusterAs, "The as number of container network, default 65000")
 		argRouterId                    = pflag.String("router-id", "", "The address for the speaker to use as router id, default the node ip")
 		argNeighborAddress             = pflag.String("neighbor-address", "", "The router address the speaker connects to.")
+		argNeighborIPv6Address         = pflag.String("neighbor-ipv6-address", "", "The router ipv6 address the speaker connects to, ipv6 routes are announced to --neighbor-address over MP-BGP if not set.")
 		argNeighborAs                  = pflag.Uint32("neighbor-as", DefaultBGPNeighborAs, "The router as number, default 65001")
 		argAuthPassword                = pflag.String("auth-password", "", "bgp peer auth password")
 		argHoldTime                    = pflag.Duration("holdtime", DefaultBGPHoldtime, "ovn-speaker goes down abnormally, the local saving time of BGP route will be affected.Holdtime must be in the range 3s to 65536s. (default 90s)")
@@ -74,6 +95,7 @@ func ParseFlags() (*Configuration, error) {
 		argKubeConfigFile              = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information. If not set use the inCluster token.")
 		argPassiveMode                 = pflag.BoolP("passivemode", "", false, "Set BGP Speaker to passive model,do not actively initiate connections to peers ")
 		argEbgpMultihopTtl             = pflag.Uint8("ebgp-multihop", DefaultEbgpMultiHop, "The TTL value of EBGP peer, default: 1")
+		argEnableEvpn                  = pflag.BoolP("enable-evpn", "", false, "Negotiate the L2VPN-EVPN family with the neighbors, EVPN routes are neither advertised nor imported")
 	)
 	klogFlags := flag.NewFlagSet("klog", flag.ExitOnError)
 	klog.InitFlags(klogFlags)
@@ -100,11 +122,15 @@ func ParseFlags() (*Configuration, error) {
 
 	config := &Configuration{
 		AnnounceClusterIP:           *argAnnounceClusterIP,
+		AnnounceLbIP:                *argAnnounceLbIP,
+		AnnounceExternalIP:          *argAnnounceExternalIP,
+		AnnounceSvcMode:             *argAnnounceSvcMode,
 		GrpcHost:                    *argGrpcHost,
 		GrpcPort:                    *argGrpcPort,
 		ClusterAs:                   *argClusterAs,
 		RouterId:                    *argRouterId,
 		NeighborAddress:             *argNeighborAddress,
+		NeighborIPv6Address:         *argNeighborIPv6Address,
 		NeighborAs:                  *argNeighborAs,
 		AuthPassword:                *argAuthPassword,
 		HoldTime:                    ht,
@@ -115,12 +141,45 @@ func ParseFlags() (*Configuration, error) {
 		GracefulRestartTime:         *argDefaultGracefulTime,
 		PassiveMode:                 *argPassiveMode,
 		EbgpMultihopTtl:             *argEbgpMultihopTtl,
+		EnableEvpn:                  *argEnableEvpn,
+		NodeName:                    strings.ToLower(os.Getenv(util.HostnameEnv)),
+		PodNamespace:                os.Getenv("KUBE_NAMESPACE"),
+	}
+	if config.PodNamespace == "" {
+		config.PodNamespace = "kube-system"
+	}
+	if config.NodeName == "" {
+		klog.Info("node name not specified in environment variables, fall back to the hostname")
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %v", err)
+		}
+		config.NodeName = strings.ToLower(hostname)
+	}
+
+	if config.AnnounceSvcMode != AnnounceSvcModeCluster && config.AnnounceSvcMode != AnnounceSvcModeLocal {
+		return nil, fmt.Errorf("invalid announce svc mode %s, must be %s or %s", config.AnnounceSvcMode, AnnounceSvcModeCluster, AnnounceSvcModeLocal)
+	}
+	if config.NeighborIPv6Address != "" && util.CheckProtocol(config.NeighborIPv6Address) != kubeovnv1.ProtocolIPv6 {
+		return nil, fmt.Errorf("invalid neighbor ipv6 address %s", config.NeighborIPv6Address)
+	}
+
+	// POD_IPS contains the node ips of all ip families as the speaker runs in host network
+	config.NodeIPs = make(map[string]string, 2)
+	for _, ip := range strings.Split(os.Getenv("POD_IPS")+","+os.Getenv("POD_IP"), ",") {
+		if net.ParseIP(ip) == nil {
+			continue
+		}
+		if protocol := util.CheckProtocol(ip); config.NodeIPs[protocol] == "" {
+			config.NodeIPs[protocol] = ip
+		}
 	}
 
 	if config.RouterId == "" {
-		config.RouterId = os.Getenv("POD_IP")
+		// bgp router id is a 32 bit number in the form of an ipv4 address
+		config.RouterId = config.NodeIPs[kubeovnv1.ProtocolIPv4]
 		if config.RouterId == "" {
-			return nil, errors.New("no router id or POD_IP")
+			return nil, errors.New("no router id or ipv4 POD_IP")
 		}
 	}
 
@@ -207,10 +266,53 @@ func (config *Configuration) initBgpServer() error {
 		return err
 	}
 
+	if config.GracefulRestart {
+		if err := config.checkGracefulRestartOptions(); err != nil {
+			return err
+		}
+	}
+
+	for _, neighborAddress := range []string{config.NeighborAddress, config.NeighborIPv6Address} {
+		if neighborAddress == "" {
+			continue
+		}
+		if err := s.AddPeer(context.Background(), &api.AddPeerRequest{
+			Peer: config.genPeer(neighborAddress),
+		}); err != nil {
+			return err
+		}
+	}
+	config.BgpServer = s
+	return nil
+}
+
+// neighborFamilies returns the address families announced to the neighbor,
+// all families are announced over MP-BGP if there is only one neighbor
+func (config *Configuration) neighborFamilies(neighborAddress string) []*api.Family {
+	ipv4 := &api.Family{Afi: api.Family_AFI_IP, Safi: api.Family_SAFI_UNICAST}
+	ipv6 := &api.Family{Afi: api.Family_AFI_IP6, Safi: api.Family_SAFI_UNICAST}
+	if config.NeighborIPv6Address == "" {
+		return []*api.Family{ipv4, ipv6}
+	}
+	if util.CheckProtocol(neighborAddress) == kubeovnv1.ProtocolIPv6 {
+		return []*api.Family{ipv6}
+	}
+	return []*api.Family{ipv4}
+}
+
+// neighborAddress returns the address of the neighbor the routes of the ip family are announced to
+func (config *Configuration) neighborAddress(protocol string) string {
+	if protocol == kubeovnv1.ProtocolIPv6 && config.NeighborIPv6Address != "" {
+		return config.NeighborIPv6Address
+	}
+	return config.NeighborAddress
+}
+
+func (config *Configuration) genPeer(neighborAddress string) *api.Peer {
 	peer := &api.Peer{
 		Timers: &api.Timers{Config: &api.TimersConfig{HoldTime: uint64(config.HoldTime)}},
 		Conf: &api.PeerConf{
-			NeighborAddress: config.NeighborAddress,
+			NeighborAddress: neighborAddress,
 			PeerAsn:         config.NeighborAs,
 		},
 		Transport: &api.Transport{
@@ -227,36 +329,32 @@ func (config *Configuration) initBgpServer() error {
 		peer.Conf.AuthPassword = config.AuthPassword
 	}
 	if config.GracefulRestart {
-
-		if err := config.checkGracefulRestartOptions(); err != nil {
-			return err
-		}
 		peer.GracefulRestart = &api.GracefulRestart{
 			Enabled:         true,
 			RestartTime:     uint32(config.GracefulRestartTime.Seconds()),
 			DeferralTime:    uint32(config.GracefulRestartDeferralTime.Seconds()),
 			LocalRestarting: true,
 		}
-		peer.AfiSafis = []*api.AfiSafi{
-			{
-				Config: &api.AfiSafiConfig{
-					Family:  &api.Family{Afi: api.Family_AFI_IP, Safi: api.Family_SAFI_UNICAST},
+	}
+	families := config.neighborFamilies(neighborAddress)
+	if config.EnableEvpn {
+		families = append(families, evpnFamily)
+	}
+	for _, family := range families {
+		afiSafi := &api.AfiSafi{
+			Config: &api.AfiSafiConfig{
+				Family:  family,
+				Enabled: true,
+			},
+		}
+		if config.GracefulRestart {
+			afiSafi.MpGracefulRestart = &api.MpGracefulRestart{
+				Config: &api.MpGracefulRestartConfig{
 					Enabled: true,
 				},
-				MpGracefulRestart: &api.MpGracefulRestart{
-					Config: &api.MpGracefulRestartConfig{
-						Enabled: true,
-					},
-				},
-			},
+			}
 		}
+		peer.AfiSafis = append(peer.AfiSafis, afiSafi)
 	}
-
-	if err := s.AddPeer(context.Background(), &api.AddPeerRequest{
-		Peer: peer,
-	}); err != nil {
-		return err
-	}
-	config.BgpServer = s
-	return nil
+	return peer
 }
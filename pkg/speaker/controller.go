@@ -11,6 +11,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	discoverylisterv1 "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -31,6 +32,23 @@ type Controller struct {
 	subnetSynced   cache.InformerSynced
 	servicesLister listerv1.ServiceLister
 	servicesSynced cache.InformerSynced
+	nodesLister    listerv1.NodeLister
+	nodesSynced    cache.InformerSynced
+	bgpPeersLister kubeovnlister.BgpPeerLister
+	bgpPeersSynced cache.InformerSynced
+
+	iptablesEipsLister   kubeovnlister.IptablesEIPLister
+	iptablesEipsSynced   cache.InformerSynced
+	vpcNatGatewaysLister kubeovnlister.VpcNatGatewayLister
+	vpcNatGatewaysSynced cache.InformerSynced
+	vpcsLister           kubeovnlister.VpcLister
+	vpcsSynced           cache.InformerSynced
+
+	endpointSlicesLister discoverylisterv1.EndpointSliceLister
+	endpointSlicesSynced cache.InformerSynced
+
+	// sessions configured from BgpPeers, keyed by neighbor address
+	bgpPeers map[string]*bgpPeerState
 
 	informerFactory        kubeinformers.SharedInformerFactory
 	kubeovnInformerFactory kubeovninformer.SharedInformerFactory
@@ -57,6 +75,11 @@ func NewController(config *Configuration) *Controller {
 	podInformer := informerFactory.Core().V1().Pods()
 	subnetInformer := kubeovnInformerFactory.Kubeovn().V1().Subnets()
 	serviceInformer := informerFactory.Core().V1().Services()
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	bgpPeerInformer := kubeovnInformerFactory.Kubeovn().V1().BgpPeers()
+	iptablesEipInformer := kubeovnInformerFactory.Kubeovn().V1().IptablesEIPs()
+	vpcNatGatewayInformer := kubeovnInformerFactory.Kubeovn().V1().VpcNatGateways()
+	vpcInformer := kubeovnInformerFactory.Kubeovn().V1().Vpcs()
 
 	controller := &Controller{
 		config: config,
@@ -67,11 +90,29 @@ func NewController(config *Configuration) *Controller {
 		subnetSynced:   subnetInformer.Informer().HasSynced,
 		servicesLister: serviceInformer.Lister(),
 		servicesSynced: serviceInformer.Informer().HasSynced,
+		nodesLister:    nodeInformer.Lister(),
+		nodesSynced:    nodeInformer.Informer().HasSynced,
+		bgpPeersLister: bgpPeerInformer.Lister(),
+		bgpPeersSynced: bgpPeerInformer.Informer().HasSynced,
+
+		iptablesEipsLister:   iptablesEipInformer.Lister(),
+		iptablesEipsSynced:   iptablesEipInformer.Informer().HasSynced,
+		vpcNatGatewaysLister: vpcNatGatewayInformer.Lister(),
+		vpcNatGatewaysSynced: vpcNatGatewayInformer.Informer().HasSynced,
+		vpcsLister:           vpcInformer.Lister(),
+		vpcsSynced:           vpcInformer.Informer().HasSynced,
+
+		bgpPeers: make(map[string]*bgpPeerState),
 
 		informerFactory:        informerFactory,
 		kubeovnInformerFactory: kubeovnInformerFactory,
 		recorder:               recorder,
 	}
+	if config.AnnounceSvcMode == AnnounceSvcModeLocal {
+		endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices()
+		controller.endpointSlicesLister = endpointSliceInformer.Lister()
+		controller.endpointSlicesSynced = endpointSliceInformer.Informer().HasSynced
+	}
 
 	return controller
 }
@@ -81,13 +122,19 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	c.informerFactory.Start(stopCh)
 	c.kubeovnInformerFactory.Start(stopCh)
 
-	if !cache.WaitForCacheSync(stopCh, c.podsSynced, c.subnetSynced, c.servicesSynced) {
+	cacheSyncs := []cache.InformerSynced{c.podsSynced, c.subnetSynced, c.servicesSynced, c.nodesSynced, c.bgpPeersSynced, c.iptablesEipsSynced, c.vpcNatGatewaysSynced, c.vpcsSynced}
+	if c.endpointSlicesSynced != nil {
+		cacheSyncs = append(cacheSyncs, c.endpointSlicesSynced)
+	}
+	if !cache.WaitForCacheSync(stopCh, cacheSyncs...) {
 		klog.Fatalf("failed to wait for caches to sync")
 		return
 	}
 
 	klog.Info("Started workers")
 	go wait.Until(c.syncSubnetRoutes, 5*time.Second, stopCh)
+	go wait.Until(c.syncBgpPeers, 5*time.Second, stopCh)
+	go wait.Until(c.syncBgpStatus, 5*time.Second, stopCh)
 
 	<-stopCh
 	klog.Info("Shutting down workers")
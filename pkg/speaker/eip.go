@@ -0,0 +1,68 @@
+package speaker
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// localNatGateways returns the names of vpc nat gateways whose pods are running on the node
+func (c *Controller) localNatGateways() (map[string]bool, error) {
+	pods, err := c.podsLister.List(labels.Set{util.VpcNatGatewayLabel: "true"}.AsSelector())
+	if err != nil {
+		klog.Errorf("failed to list vpc nat gateway pods, %v", err)
+		return nil, err
+	}
+
+	gateways := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != c.config.NodeName || pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if name := pod.Annotations[util.VpcNatGatewayAnnotation]; name != "" {
+			gateways[name] = true
+		}
+	}
+	return gateways, nil
+}
+
+// addNatGwEipRoutes adds the host routes of ready eips of the vpc nat gateways running on the node,
+// eips are announced if either the eip or its gateway is annotated with ovn.kubernetes.io/bgp=true.
+// Floating ips and dnat rules are reached through their eips.
+func (c *Controller) addNatGwEipRoutes(expected map[string]map[string]routeAttrs) error {
+	gateways, err := c.localNatGateways()
+	if err != nil || len(gateways) == 0 {
+		return err
+	}
+
+	eips, err := c.iptablesEipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list iptables eips, %v", err)
+		return err
+	}
+	for _, eip := range eips {
+		if !eip.Status.Ready || !gateways[eip.Spec.NatGwDp] {
+			continue
+		}
+		ip := eip.Status.IP
+		if ip == "" {
+			ip = eip.Spec.V4ip
+		}
+		if ip == "" {
+			continue
+		}
+
+		annotations := eip.Annotations
+		if annotations[util.BgpAnnotation] != "true" {
+			gw, err := c.vpcNatGatewaysLister.Get(eip.Spec.NatGwDp)
+			if err != nil || gw.Annotations[util.BgpAnnotation] != "true" {
+				continue
+			}
+			annotations = gw.Annotations
+		}
+		addExpectedRoute(expected, ip, annotationRouteAttrs("iptables eip", eip.Name, annotations))
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package speaker
+
+import (
+	bgpapi "github.com/osrg/gobgp/v3/api"
+)
+
+// EvpnFamily is the name of the l2vpn evpn address family of BgpPeers
+const EvpnFamily = "L2VPN-EVPN"
+
+var evpnFamily = &bgpapi.Family{Afi: bgpapi.Family_AFI_L2VPN, Safi: bgpapi.Family_SAFI_EVPN}
@@ -0,0 +1,223 @@
+package speaker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+
+	bgpapi "github.com/osrg/gobgp/v3/api"
+	bgpapiutil "github.com/osrg/gobgp/v3/pkg/apiutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// LearnedRouteSourceBgpPeer is the source of routes received from the neighbors of BgpPeers with a vpc
+const LearnedRouteSourceBgpPeer = "bgp-peer"
+
+// prefixListEntry is a parsed entry of the import prefix list of a BgpPeer
+type prefixListEntry struct {
+	cidr   *net.IPNet
+	minLen int
+	maxLen int
+}
+
+func parsePrefixList(entries []kubeovnv1.BgpPrefixListEntry) ([]prefixListEntry, error) {
+	prefixList := make([]prefixListEntry, 0, len(entries))
+	for _, entry := range entries {
+		_, cidr, err := net.ParseCIDR(entry.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix %s", entry.Prefix)
+		}
+		ones, bits := cidr.Mask.Size()
+		minLen, maxLen := ones, ones
+		if entry.Ge != 0 || entry.Le != 0 {
+			minLen, maxLen = ones, bits
+			if entry.Ge != 0 {
+				minLen = int(entry.Ge)
+			}
+			if entry.Le != 0 {
+				maxLen = int(entry.Le)
+			}
+		}
+		if minLen < ones || maxLen > bits || minLen > maxLen {
+			return nil, fmt.Errorf("invalid prefix length range ge %d le %d of prefix %s", entry.Ge, entry.Le, entry.Prefix)
+		}
+		prefixList = append(prefixList, prefixListEntry{cidr: cidr, minLen: minLen, maxLen: maxLen})
+	}
+	return prefixList, nil
+}
+
+// matchPrefixList returns whether the route matches any entry of the prefix list, an empty list matches all routes
+func matchPrefixList(prefixList []prefixListEntry, route *net.IPNet) bool {
+	if len(prefixList) == 0 {
+		return true
+	}
+	ones, bits := route.Mask.Size()
+	for _, entry := range prefixList {
+		if _, entryBits := entry.cidr.Mask.Size(); entryBits != bits {
+			continue
+		}
+		if entry.cidr.Contains(route.IP) && ones >= entry.minLen && ones <= entry.maxLen {
+			return true
+		}
+	}
+	return false
+}
+
+// syncBgpLearnedRoutes reports the unicast routes received from the neighbors of BgpPeers with a vpc
+// in the status of the vpcs, routes withdrawn or received from neighbors down are removed
+func (c *Controller) syncBgpLearnedRoutes(peers map[string]*kubeovnv1.BgpPeer) {
+	learnedRoutes := make(map[string][]*kubeovnv1.LearnedRoute)
+	for address, peer := range peers {
+		if peer.Spec.Vpc == "" {
+			continue
+		}
+		if peer.Spec.Vpc == util.DefaultVpc {
+			klog.Errorf("routes of bgp peer %s can not be learned into the default vpc", peer.Name)
+			continue
+		}
+		prefixList, err := parsePrefixList(peer.Spec.ImportPrefixes)
+		if err != nil {
+			klog.Errorf("invalid import prefixes of bgp peer %s, %v", peer.Name, err)
+			continue
+		}
+		routes, err := c.receivedRoutes(address, prefixList)
+		if err != nil {
+			continue
+		}
+		learnedRoutes[peer.Spec.Vpc] = append(learnedRoutes[peer.Spec.Vpc], routes...)
+	}
+
+	if err := c.syncVpcLearnedRoutes(LearnedRouteSourceBgpPeer, learnedRoutes); err != nil {
+		klog.Errorf("failed to sync learned routes of bgp peers, %v", err)
+	}
+}
+
+// receivedRoutes returns the unicast routes received from the neighbor matching the prefix list
+func (c *Controller) receivedRoutes(neighbor string, prefixList []prefixListEntry) ([]*kubeovnv1.LearnedRoute, error) {
+	var routes []*kubeovnv1.LearnedRoute
+	fn := func(d *bgpapi.Destination) {
+		_, cidr, err := net.ParseCIDR(d.Prefix)
+		if err != nil || !matchPrefixList(prefixList, cidr) {
+			return
+		}
+		for _, path := range d.Paths {
+			if path.IsWithdraw {
+				continue
+			}
+			attrs, err := bgpapiutil.UnmarshalPathAttributes(path.Pattrs)
+			if err != nil {
+				continue
+			}
+			nextHop := getNextHopFromPathAttributes(attrs)
+			if nextHop == nil {
+				continue
+			}
+			routes = append(routes, &kubeovnv1.LearnedRoute{
+				Node:      c.config.NodeName,
+				Source:    LearnedRouteSourceBgpPeer,
+				CIDR:      cidr.String(),
+				NextHopIP: nextHop.String(),
+			})
+		}
+	}
+	for protocol, family := range bgpFamilies {
+		listPathRequest := &bgpapi.ListPathRequest{
+			TableType: bgpapi.TableType_ADJ_IN,
+			Name:      neighbor,
+			Family:    family,
+		}
+		if err := c.config.BgpServer.ListPath(context.Background(), listPathRequest, fn); err != nil {
+			klog.Errorf("failed to list %s routes received from %s, %v", protocol, neighbor, err)
+			return nil, err
+		}
+	}
+	return routes, nil
+}
+
+// sortLearnedRoutes sorts and removes duplicates of the routes
+func sortLearnedRoutes(routes []*kubeovnv1.LearnedRoute) []*kubeovnv1.LearnedRoute {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].CIDR != routes[j].CIDR {
+			return routes[i].CIDR < routes[j].CIDR
+		}
+		return routes[i].NextHopIP < routes[j].NextHopIP
+	})
+	result := make([]*kubeovnv1.LearnedRoute, 0, len(routes))
+	for i, route := range routes {
+		if i == 0 || !reflect.DeepEqual(route, routes[i-1]) {
+			result = append(result, route)
+		}
+	}
+	return result
+}
+
+// syncVpcLearnedRoutes updates the routes of the source learned by the speaker of this node in the vpc status,
+// vpcs not in learnedRoutes have no routes of the source. A failed vpc does not block the others.
+func (c *Controller) syncVpcLearnedRoutes(source string, learnedRoutes map[string][]*kubeovnv1.LearnedRoute) error {
+	vpcs, err := c.vpcsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list vpcs, %v", err)
+		return err
+	}
+
+	var errs []error
+	for _, vpc := range vpcs {
+		routes := sortLearnedRoutes(learnedRoutes[vpc.Name])
+		if reflect.DeepEqual(routes, c.nodeLearnedRoutes(vpc, source)) {
+			continue
+		}
+		if err = c.updateVpcLearnedRoutes(vpc.Name, source, routes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// nodeLearnedRoutes returns the routes of the source learned by the speaker of this node in the vpc status
+func (c *Controller) nodeLearnedRoutes(vpc *kubeovnv1.Vpc, source string) []*kubeovnv1.LearnedRoute {
+	routes := make([]*kubeovnv1.LearnedRoute, 0, len(vpc.Status.LearnedRoutes))
+	for _, route := range vpc.Status.LearnedRoutes {
+		if route.Node == c.config.NodeName && route.Source == source {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// mergeLearnedRoutes replaces the routes of the source learned by the speaker of the node with routes
+func mergeLearnedRoutes(learnedRoutes []*kubeovnv1.LearnedRoute, node, source string, routes []*kubeovnv1.LearnedRoute) []*kubeovnv1.LearnedRoute {
+	result := make([]*kubeovnv1.LearnedRoute, 0, len(learnedRoutes)+len(routes))
+	for _, route := range learnedRoutes {
+		if route.Node != node || route.Source != source {
+			result = append(result, route)
+		}
+	}
+	return append(result, routes...)
+}
+
+func (c *Controller) updateVpcLearnedRoutes(name, source string, routes []*kubeovnv1.LearnedRoute) error {
+	// routes are learned by the speakers of all nodes, so the update is retried on the latest vpc
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		vpc, err := c.config.KubeOvnClient.KubeovnV1().Vpcs().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		vpc.Status.LearnedRoutes = mergeLearnedRoutes(vpc.Status.LearnedRoutes, c.config.NodeName, source, routes)
+		_, err = c.config.KubeOvnClient.KubeovnV1().Vpcs().UpdateStatus(context.Background(), vpc, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		klog.Errorf("failed to update learned routes of vpc %s, %v", name, err)
+		return err
+	}
+	return nil
+}
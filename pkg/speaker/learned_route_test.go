@@ -0,0 +1,183 @@
+package speaker
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func mustParseCIDR(t *testing.T, s string) *net.IPNet {
+	_, cidr, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cidr
+}
+
+func TestParsePrefixList(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []kubeovnv1.BgpPrefixListEntry
+		want    [][2]int
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			want: [][2]int{},
+		},
+		{
+			name:    "exact prefix length",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "10.0.0.0/8"}},
+			want:    [][2]int{{8, 8}},
+		},
+		{
+			name:    "ge only",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "10.0.0.0/8", Ge: 16}},
+			want:    [][2]int{{16, 32}},
+		},
+		{
+			name:    "le only",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "fd00::/64", Le: 96}},
+			want:    [][2]int{{64, 96}},
+		},
+		{
+			name: "ge and le",
+			entries: []kubeovnv1.BgpPrefixListEntry{
+				{Prefix: "10.0.0.0/8", Ge: 16, Le: 24},
+				{Prefix: "fd00::/64", Ge: 128, Le: 128},
+			},
+			want: [][2]int{{16, 24}, {128, 128}},
+		},
+		{
+			name:    "invalid prefix",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "10.0.0.0"}},
+			wantErr: true,
+		},
+		{
+			name:    "ge shorter than prefix",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "10.0.0.0/16", Ge: 8}},
+			wantErr: true,
+		},
+		{
+			name:    "le longer than address",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "10.0.0.0/16", Le: 33}},
+			wantErr: true,
+		},
+		{
+			name:    "ge greater than le",
+			entries: []kubeovnv1.BgpPrefixListEntry{{Prefix: "10.0.0.0/8", Ge: 24, Le: 16}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePrefixList(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrefixList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			lens := make([][2]int, 0, len(got))
+			for i, entry := range got {
+				if want := mustParseCIDR(t, tt.entries[i].Prefix); entry.cidr.String() != want.String() {
+					t.Errorf("parsePrefixList() cidr = %s, want %s", entry.cidr, want)
+				}
+				lens = append(lens, [2]int{entry.minLen, entry.maxLen})
+			}
+			if !reflect.DeepEqual(lens, tt.want) {
+				t.Errorf("parsePrefixList() lengths = %v, want %v", lens, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPrefixList(t *testing.T) {
+	prefixList, err := parsePrefixList([]kubeovnv1.BgpPrefixListEntry{
+		{Prefix: "10.0.0.0/8", Ge: 16, Le: 24},
+		{Prefix: "192.168.0.0/16"},
+		{Prefix: "fd00::/64", Le: 128},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		prefixList []prefixListEntry
+		route      string
+		want       bool
+	}{
+		{"empty list matches all", nil, "172.16.0.0/12", true},
+		{"in range", prefixList, "10.1.0.0/16", true},
+		{"longest in range", prefixList, "10.1.2.0/24", true},
+		{"shorter than ge", prefixList, "10.0.0.0/8", false},
+		{"longer than le", prefixList, "10.1.2.0/25", false},
+		{"exact prefix", prefixList, "192.168.0.0/16", true},
+		{"longer than exact prefix", prefixList, "192.168.1.0/24", false},
+		{"outside prefixes", prefixList, "172.16.0.0/16", false},
+		{"ipv6 host route", prefixList, "fd00::1/128", true},
+		{"ipv6 outside prefix", prefixList, "fd01::/64", false},
+		{"ipv4 mapped route not matching ipv6 entry", prefixList, "0.0.0.0/0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchPrefixList(tt.prefixList, mustParseCIDR(t, tt.route)); got != tt.want {
+				t.Errorf("matchPrefixList(%s) = %v, want %v", tt.route, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortLearnedRoutes(t *testing.T) {
+	routes := []*kubeovnv1.LearnedRoute{
+		{Node: "node1", CIDR: "10.2.0.0/16", NextHopIP: "192.168.0.1"},
+		{Node: "node1", CIDR: "10.1.0.0/16", NextHopIP: "192.168.0.2"},
+		{Node: "node1", CIDR: "10.1.0.0/16", NextHopIP: "192.168.0.1"},
+		{Node: "node1", CIDR: "10.2.0.0/16", NextHopIP: "192.168.0.1"},
+	}
+	want := []*kubeovnv1.LearnedRoute{
+		{Node: "node1", CIDR: "10.1.0.0/16", NextHopIP: "192.168.0.1"},
+		{Node: "node1", CIDR: "10.1.0.0/16", NextHopIP: "192.168.0.2"},
+		{Node: "node1", CIDR: "10.2.0.0/16", NextHopIP: "192.168.0.1"},
+	}
+	if got := sortLearnedRoutes(routes); !reflect.DeepEqual(got, want) {
+		t.Errorf("sortLearnedRoutes() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeLearnedRoutes(t *testing.T) {
+	existing := []*kubeovnv1.LearnedRoute{
+		{Node: "node1", Source: LearnedRouteSourceBgpPeer, CIDR: "10.1.0.0/16", NextHopIP: "192.168.0.1"},
+		{Node: "node1", Source: "evpn", CIDR: "10.2.0.0/16", NextHopIP: "192.168.0.1"},
+		{Node: "node2", Source: LearnedRouteSourceBgpPeer, CIDR: "10.1.0.0/16", NextHopIP: "192.168.0.2"},
+	}
+	routes := []*kubeovnv1.LearnedRoute{
+		{Node: "node1", Source: LearnedRouteSourceBgpPeer, CIDR: "10.3.0.0/16", NextHopIP: "192.168.0.1"},
+	}
+
+	tests := []struct {
+		name   string
+		routes []*kubeovnv1.LearnedRoute
+		want   []*kubeovnv1.LearnedRoute
+	}{
+		{
+			name:   "routes of the node and source replaced",
+			routes: routes,
+			want:   []*kubeovnv1.LearnedRoute{existing[1], existing[2], routes[0]},
+		},
+		{
+			name: "routes of the node and source removed",
+			want: []*kubeovnv1.LearnedRoute{existing[1], existing[2]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeLearnedRoutes(existing, "node1", LearnedRouteSourceBgpPeer, tt.routes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeLearnedRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package speaker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricBgpPeerEstablished = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_established",
+			Help: "Whether the session with the bgp neighbor is established",
+		},
+		[]string{"node", "neighbor", "peer_as"},
+	)
+
+	metricBgpPeerSessionState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_session_state",
+			Help: "The session state with the bgp neighbor, 1 idle, 2 connect, 3 active, 4 opensent, 5 openconfirm, 6 established",
+		},
+		[]string{"node", "neighbor", "peer_as"},
+	)
+
+	metricBgpPeerUptime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_uptime_seconds",
+			Help: "The seconds since the session with the bgp neighbor is established",
+		},
+		[]string{"node", "neighbor", "peer_as"},
+	)
+
+	metricBgpPeerFlaps = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_flaps",
+			Help: "The number of times the session with the bgp neighbor went down",
+		},
+		[]string{"node", "neighbor", "peer_as"},
+	)
+
+	metricBgpPeerReceivedPrefixes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_received_prefixes",
+			Help: "The number of prefixes received from the bgp neighbor",
+		},
+		[]string{"node", "neighbor", "peer_as", "family"},
+	)
+
+	metricBgpPeerAcceptedPrefixes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_accepted_prefixes",
+			Help: "The number of prefixes received from the bgp neighbor and accepted",
+		},
+		[]string{"node", "neighbor", "peer_as", "family"},
+	)
+
+	metricBgpPeerAdvertisedPrefixes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_peer_advertised_prefixes",
+			Help: "The number of prefixes advertised to the bgp neighbor",
+		},
+		[]string{"node", "neighbor", "peer_as", "family"},
+	)
+
+	metricBgpAnnouncedRoutes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "speaker_bgp_announced_routes",
+			Help: "The number of routes announced by the speaker",
+		},
+		[]string{"node", "family"},
+	)
+)
+
+func InitSpeakerMetrics() {
+	prometheus.MustRegister(metricBgpPeerEstablished)
+	prometheus.MustRegister(metricBgpPeerSessionState)
+	prometheus.MustRegister(metricBgpPeerUptime)
+	prometheus.MustRegister(metricBgpPeerFlaps)
+	prometheus.MustRegister(metricBgpPeerReceivedPrefixes)
+	prometheus.MustRegister(metricBgpPeerAcceptedPrefixes)
+	prometheus.MustRegister(metricBgpPeerAdvertisedPrefixes)
+	prometheus.MustRegister(metricBgpAnnouncedRoutes)
+}
@@ -0,0 +1,142 @@
+package speaker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	bgpapi "github.com/osrg/gobgp/v3/api"
+	"github.com/osrg/gobgp/v3/pkg/packet/bgp"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// routeAttrs are the optional path attributes of a route set by annotations of the announced resource
+type routeAttrs struct {
+	Communities      []uint32
+	LargeCommunities []bgp.LargeCommunity
+	LocalPref        uint32
+	// AsPathPrepend is the number of times the cluster as is prepended to the as path
+	AsPathPrepend uint32
+}
+
+func parseCommunity(value string) (uint32, error) {
+	if community, ok := bgp.WellKnownCommunityValueMap[value]; ok {
+		return uint32(community), nil
+	}
+	elems := strings.Split(value, ":")
+	if len(elems) != 2 {
+		return 0, fmt.Errorf("invalid community %s", value)
+	}
+	asn, err := strconv.ParseUint(elems[0], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid community %s", value)
+	}
+	local, err := strconv.ParseUint(elems[1], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid community %s", value)
+	}
+	return uint32(asn<<16 | local), nil
+}
+
+// parseRouteAttrs parses the path attributes from annotations, communities are separated by comma
+func parseRouteAttrs(annotations map[string]string) (routeAttrs, error) {
+	var attrs routeAttrs
+	if value := annotations[util.BgpCommunityAnnotation]; value != "" {
+		for _, v := range strings.Split(value, ",") {
+			community, err := parseCommunity(strings.TrimSpace(v))
+			if err != nil {
+				return attrs, err
+			}
+			attrs.Communities = append(attrs.Communities, community)
+		}
+		sort.Slice(attrs.Communities, func(i, j int) bool { return attrs.Communities[i] < attrs.Communities[j] })
+	}
+	if value := annotations[util.BgpLargeCommunityAnnotation]; value != "" {
+		for _, v := range strings.Split(value, ",") {
+			community, err := bgp.ParseLargeCommunity(strings.TrimSpace(v))
+			if err != nil {
+				return attrs, fmt.Errorf("invalid large community %s", v)
+			}
+			attrs.LargeCommunities = append(attrs.LargeCommunities, *community)
+		}
+		sort.Slice(attrs.LargeCommunities, func(i, j int) bool {
+			return attrs.LargeCommunities[i].String() < attrs.LargeCommunities[j].String()
+		})
+	}
+	if value := annotations[util.BgpLocalPrefAnnotation]; value != "" {
+		localPref, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return attrs, fmt.Errorf("invalid local preference %s", value)
+		}
+		attrs.LocalPref = uint32(localPref)
+	}
+	if value := annotations[util.BgpAsPathPrependAnnotation]; value != "" {
+		prepend, err := strconv.ParseUint(value, 10, 8)
+		if err != nil || prepend > 32 {
+			return attrs, fmt.Errorf("invalid as path prepend %s, must be in the range 0 to 32", value)
+		}
+		attrs.AsPathPrepend = uint32(prepend)
+	}
+	return attrs, nil
+}
+
+// getRouteAttrs returns the path attributes set by the speaker from the attributes of a path
+func getRouteAttrs(pathAttrs []bgp.PathAttributeInterface) routeAttrs {
+	var attrs routeAttrs
+	for _, attr := range pathAttrs {
+		switch a := attr.(type) {
+		case *bgp.PathAttributeCommunities:
+			attrs.Communities = append(attrs.Communities, a.Value...)
+			sort.Slice(attrs.Communities, func(i, j int) bool { return attrs.Communities[i] < attrs.Communities[j] })
+		case *bgp.PathAttributeLargeCommunities:
+			for _, community := range a.Values {
+				attrs.LargeCommunities = append(attrs.LargeCommunities, *community)
+			}
+			sort.Slice(attrs.LargeCommunities, func(i, j int) bool {
+				return attrs.LargeCommunities[i].String() < attrs.LargeCommunities[j].String()
+			})
+		case *bgp.PathAttributeLocalPref:
+			attrs.LocalPref = a.Value
+		case *bgp.PathAttributeAsPath:
+			for _, param := range a.Value {
+				attrs.AsPathPrepend += uint32(len(param.GetAS()))
+			}
+		}
+	}
+	return attrs
+}
+
+// pathAttributes returns the optional path attributes to announce
+func (attrs routeAttrs) pathAttributes(clusterAs uint32) []*anypb.Any {
+	var result []*anypb.Any
+	if len(attrs.Communities) != 0 {
+		a, _ := anypb.New(&bgpapi.CommunitiesAttribute{Communities: attrs.Communities})
+		result = append(result, a)
+	}
+	if len(attrs.LargeCommunities) != 0 {
+		communities := make([]*bgpapi.LargeCommunity, 0, len(attrs.LargeCommunities))
+		for _, c := range attrs.LargeCommunities {
+			communities = append(communities, &bgpapi.LargeCommunity{GlobalAdmin: c.ASN, LocalData1: c.LocalData1, LocalData2: c.LocalData2})
+		}
+		a, _ := anypb.New(&bgpapi.LargeCommunitiesAttribute{Communities: communities})
+		result = append(result, a)
+	}
+	if attrs.LocalPref != 0 {
+		a, _ := anypb.New(&bgpapi.LocalPrefAttribute{LocalPref: attrs.LocalPref})
+		result = append(result, a)
+	}
+	if attrs.AsPathPrepend != 0 {
+		numbers := make([]uint32, attrs.AsPathPrepend)
+		for i := range numbers {
+			numbers[i] = clusterAs
+		}
+		a, _ := anypb.New(&bgpapi.AsPathAttribute{
+			Segments: []*bgpapi.AsSegment{{Type: bgpapi.AsSegment_AS_SEQUENCE, Numbers: numbers}},
+		})
+		result = append(result, a)
+	}
+	return result
+}
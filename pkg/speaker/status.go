@@ -0,0 +1,127 @@
+package speaker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bgpapi "github.com/osrg/gobgp/v3/api"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// BgpNodeConditionType is the node condition reporting the bgp sessions of the speaker on the node
+const BgpNodeConditionType v1.NodeConditionType = "BgpSessionsEstablished"
+
+func familyName(family *bgpapi.Family) string {
+	if family == nil {
+		return ""
+	}
+	switch family.Afi {
+	case bgpapi.Family_AFI_IP:
+		return kubeovnv1.ProtocolIPv4
+	case bgpapi.Family_AFI_IP6:
+		return kubeovnv1.ProtocolIPv6
+	case bgpapi.Family_AFI_L2VPN:
+		return EvpnFamily
+	}
+	return family.String()
+}
+
+// syncBgpStatus exports the states of bgp sessions to metrics and the condition of the node
+func (c *Controller) syncBgpStatus() {
+	var peers []*bgpapi.Peer
+	err := c.config.BgpServer.ListPeer(context.Background(), &bgpapi.ListPeerRequest{EnableAdvertised: true}, func(p *bgpapi.Peer) {
+		if p.Conf != nil {
+			peers = append(peers, p)
+		}
+	})
+	if err != nil {
+		klog.Errorf("failed to list bgp neighbors, %v", err)
+		return
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Conf.NeighborAddress < peers[j].Conf.NeighborAddress })
+
+	metricBgpPeerEstablished.Reset()
+	metricBgpPeerSessionState.Reset()
+	metricBgpPeerUptime.Reset()
+	metricBgpPeerFlaps.Reset()
+	metricBgpPeerReceivedPrefixes.Reset()
+	metricBgpPeerAcceptedPrefixes.Reset()
+	metricBgpPeerAdvertisedPrefixes.Reset()
+
+	established := 0
+	sessions := make([]string, 0, len(peers))
+	for _, p := range peers {
+		node, neighbor, peerAs := c.config.NodeName, p.Conf.NeighborAddress, strconv.FormatUint(uint64(p.Conf.PeerAsn), 10)
+		state := bgpapi.PeerState_UNKNOWN
+		if p.State != nil {
+			state = p.State.SessionState
+			metricBgpPeerFlaps.WithLabelValues(node, neighbor, peerAs).Set(float64(p.State.Flops))
+		}
+
+		var uptime float64
+		if state == bgpapi.PeerState_ESTABLISHED {
+			established++
+			if p.Timers != nil && p.Timers.State != nil && p.Timers.State.Uptime != nil {
+				uptime = time.Since(p.Timers.State.Uptime.AsTime()).Seconds()
+			}
+			metricBgpPeerEstablished.WithLabelValues(node, neighbor, peerAs).Set(1)
+		} else {
+			metricBgpPeerEstablished.WithLabelValues(node, neighbor, peerAs).Set(0)
+		}
+		metricBgpPeerSessionState.WithLabelValues(node, neighbor, peerAs).Set(float64(state))
+		metricBgpPeerUptime.WithLabelValues(node, neighbor, peerAs).Set(uptime)
+
+		var received, advertised uint64
+		for _, afiSafi := range p.AfiSafis {
+			if afiSafi.State == nil {
+				continue
+			}
+			family := familyName(afiSafi.State.Family)
+			metricBgpPeerReceivedPrefixes.WithLabelValues(node, neighbor, peerAs, family).Set(float64(afiSafi.State.Received))
+			metricBgpPeerAcceptedPrefixes.WithLabelValues(node, neighbor, peerAs, family).Set(float64(afiSafi.State.Accepted))
+			metricBgpPeerAdvertisedPrefixes.WithLabelValues(node, neighbor, peerAs, family).Set(float64(afiSafi.State.Advertised))
+			received += afiSafi.State.Received
+			advertised += afiSafi.State.Advertised
+		}
+		sessions = append(sessions, fmt.Sprintf("%s (AS %s): %s, %d received, %d advertised", neighbor, peerAs, state, received, advertised))
+	}
+
+	condition := v1.NodeCondition{
+		Type:    BgpNodeConditionType,
+		Message: strings.Join(sessions, "; "),
+	}
+	switch {
+	case len(peers) == 0:
+		condition.Status, condition.Reason, condition.Message = v1.ConditionUnknown, "NoNeighbors", "no bgp neighbors configured"
+	case established == len(peers):
+		condition.Status, condition.Reason = v1.ConditionTrue, "AllSessionsEstablished"
+	default:
+		condition.Status, condition.Reason = v1.ConditionFalse, "SessionsNotEstablished"
+	}
+	if err = c.updateNodeCondition(condition); err != nil {
+		klog.Errorf("failed to update condition %s of node %s, %v", condition.Type, c.config.NodeName, err)
+	}
+}
+
+func (c *Controller) updateNodeCondition(condition v1.NodeCondition) error {
+	node, err := c.nodesLister.Get(c.config.NodeName)
+	if err != nil {
+		return err
+	}
+	patch, err := util.GenNodeConditionPatch(node, condition)
+	if err != nil || patch == nil {
+		return err
+	}
+	_, err = c.config.KubeClient.CoreV1().Nodes().Patch(context.Background(), node.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
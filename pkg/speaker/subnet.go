@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -14,9 +15,11 @@ import (
 	"github.com/vishvananda/netlink"
 	"google.golang.org/protobuf/types/known/anypb"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
@@ -41,9 +44,22 @@ func isClusterIPService(svc *v1.Service) bool {
 		len(svc.Spec.ClusterIP) != 0
 }
 
-// TODO: ipv4 only, need ipv6/dual-stack support later
+// bgpFamilies are the address families of announced routes
+var bgpFamilies = map[string]*bgpapi.Family{
+	kubeovnv1.ProtocolIPv4: {Afi: bgpapi.Family_AFI_IP, Safi: bgpapi.Family_SAFI_UNICAST},
+	kubeovnv1.ProtocolIPv6: {Afi: bgpapi.Family_AFI_IP6, Safi: bgpapi.Family_SAFI_UNICAST},
+}
+
+// hostRoute returns the host route of the ip
+func hostRoute(ip string) string {
+	if util.CheckProtocol(ip) == kubeovnv1.ProtocolIPv6 {
+		return fmt.Sprintf("%s/128", ip)
+	}
+	return fmt.Sprintf("%s/32", ip)
+}
+
 func (c *Controller) syncSubnetRoutes() {
-	bgpExpected, bgpExists := []string{}, []string{}
+	bgpExpected := map[string]map[string]routeAttrs{}
 	subnets, err := c.subnetsLister.List(labels.Everything())
 	if err != nil {
 		klog.Errorf("failed to list subnets, %v", err)
@@ -55,85 +71,207 @@ func (c *Controller) syncSubnetRoutes() {
 		return
 	}
 
-	if c.config.AnnounceClusterIP {
+	if c.config.AnnounceClusterIP || c.config.AnnounceLbIP || c.config.AnnounceExternalIP {
 		services, err := c.servicesLister.List(labels.Everything())
 		if err != nil {
 			klog.Errorf("failed to list services, %v", err)
 			return
 		}
+		var localServices map[string]bool
+		if c.config.AnnounceSvcMode == AnnounceSvcModeLocal {
+			if localServices, err = c.servicesWithLocalEndpoints(); err != nil {
+				return
+			}
+		}
 		for _, svc := range services {
-			if svc.Annotations != nil && svc.Annotations[util.BgpAnnotation] == "true" && isClusterIPService(svc) {
-				bgpExpected = append(bgpExpected, fmt.Sprintf("%s/32", svc.Spec.ClusterIP))
+			if svc.Annotations[util.BgpAnnotation] != "true" {
+				continue
+			}
+			if localServices != nil && !localServices[svc.Namespace+"/"+svc.Name] {
+				continue
+			}
+			attrs := annotationRouteAttrs("service", svc.Namespace+"/"+svc.Name, svc.Annotations)
+			for _, ip := range serviceAnnouncedIPs(svc, c.config.AnnounceClusterIP, c.config.AnnounceLbIP, c.config.AnnounceExternalIP) {
+				addExpectedRoute(bgpExpected, ip, attrs)
 			}
 		}
 	}
 
 	for _, subnet := range subnets {
 		if subnet.Status.IsReady() && subnet.Annotations != nil && subnet.Annotations[util.BgpAnnotation] == "true" {
-			bgpExpected = append(bgpExpected, subnet.Spec.CIDRBlock)
+			attrs := annotationRouteAttrs("subnet", subnet.Name, subnet.Annotations)
+			for _, cidrBlock := range strings.Split(subnet.Spec.CIDRBlock, ",") {
+				addExpectedRoute(bgpExpected, cidrBlock, attrs)
+			}
 		}
 	}
 
 	for _, pod := range pods {
-		if isPodAlive(pod) && !pod.Spec.HostNetwork && pod.Annotations[util.BgpAnnotation] == "true" && pod.Status.PodIP != "" {
-			bgpExpected = append(bgpExpected, fmt.Sprintf("%s/32", pod.Status.PodIP))
+		if !isPodAlive(pod) || pod.Spec.HostNetwork || pod.Annotations[util.BgpAnnotation] != "true" {
+			continue
+		}
+		podIPs := pod.Status.PodIPs
+		if len(podIPs) == 0 && pod.Status.PodIP != "" {
+			podIPs = []v1.PodIP{{IP: pod.Status.PodIP}}
+		}
+		attrs := annotationRouteAttrs("pod", pod.Namespace+"/"+pod.Name, pod.Annotations)
+		for _, podIP := range podIPs {
+			addExpectedRoute(bgpExpected, podIP.IP, attrs)
+		}
+	}
+
+	if err = c.addNatGwEipRoutes(bgpExpected); err != nil {
+		return
+	}
+
+	for protocol := range bgpFamilies {
+		c.syncFamilyRoutes(protocol, bgpExpected[protocol])
+	}
+}
+
+// serviceAnnouncedIPs returns the ips of the service to announce
+func serviceAnnouncedIPs(svc *v1.Service, clusterIP, lbIP, externalIP bool) []string {
+	var ips []string
+	if clusterIP && isClusterIPService(svc) {
+		if len(svc.Spec.ClusterIPs) != 0 {
+			ips = append(ips, svc.Spec.ClusterIPs...)
+		} else {
+			ips = append(ips, svc.Spec.ClusterIP)
+		}
+	}
+	if lbIP && svc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				ips = append(ips, ingress.IP)
+			}
 		}
 	}
+	if externalIP {
+		ips = append(ips, svc.Spec.ExternalIPs...)
+	}
+	return ips
+}
+
+// servicesWithLocalEndpoints returns the keys of services with ready endpoints on the node
+func (c *Controller) servicesWithLocalEndpoints() (map[string]bool, error) {
+	slices, err := c.endpointSlicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list endpoint slices, %v", err)
+		return nil, err
+	}
+	return localServiceKeys(slices, c.config.NodeName), nil
+}
 
-	klog.V(5).Infof("expected routes %v", bgpExpected)
+// localServiceKeys returns the keys of services with ready endpoints on the node in the endpoint slices
+func localServiceKeys(slices []*discoveryv1.EndpointSlice, nodeName string) map[string]bool {
+	services := make(map[string]bool)
+	for _, slice := range slices {
+		svcName := slice.Labels[discoveryv1.LabelServiceName]
+		if svcName == "" {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.NodeName == nil || *endpoint.NodeName != nodeName {
+				continue
+			}
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				services[slice.Namespace+"/"+svcName] = true
+				break
+			}
+		}
+	}
+	return services
+}
+
+// annotationRouteAttrs returns the path attributes set by annotations of the resource,
+// routes are announced without these attributes if the annotations are invalid
+func annotationRouteAttrs(kind, name string, annotations map[string]string) routeAttrs {
+	attrs, err := parseRouteAttrs(annotations)
+	if err != nil {
+		klog.Errorf("invalid bgp path attribute annotations of %s %s, %v", kind, name, err)
+		return routeAttrs{}
+	}
+	return attrs
+}
+
+// addExpectedRoute adds the route of a cidr or the host route of an ip to the expected routes of its ip family
+func addExpectedRoute(expected map[string]map[string]routeAttrs, route string, attrs routeAttrs) {
+	if !strings.Contains(route, "/") {
+		route = hostRoute(route)
+	}
+	_, cidr, err := net.ParseCIDR(route)
+	if err != nil {
+		return
+	}
+	// prefixes listed by gobgp are in canonical form
+	route = cidr.String()
+	protocol := util.CheckProtocol(route)
+	if expected[protocol] == nil {
+		expected[protocol] = make(map[string]routeAttrs)
+	}
+	if _, ok := expected[protocol][route]; !ok {
+		expected[protocol][route] = attrs
+	}
+}
+
+func (c *Controller) syncFamilyRoutes(protocol string, bgpExpected map[string]routeAttrs) {
+	bgpExists := map[string]routeAttrs{}
+	nextHop := c.getNextHop(protocol)
+	if nextHop == "" {
+		if len(bgpExpected) != 0 {
+			klog.Warningf("no %s next hop to announce routes %v", protocol, bgpExpected)
+		}
+		return
+	}
+
+	klog.V(5).Infof("expected %s routes %v", protocol, bgpExpected)
 	listPathRequest := &bgpapi.ListPathRequest{
 		TableType: bgpapi.TableType_GLOBAL,
-		Family:    &bgpapi.Family{Afi: bgpapi.Family_AFI_IP, Safi: bgpapi.Family_SAFI_UNICAST},
+		Family:    bgpFamilies[protocol],
 	}
 	fn := func(d *bgpapi.Destination) {
 		for _, path := range d.Paths {
 			attrInterfaces, _ := bgpapiutil.UnmarshalPathAttributes(path.Pattrs)
-			nextHop := getNextHopFromPathAttributes(attrInterfaces)
-			klog.V(5).Infof("nexthop is %s, routerID is %s", nextHop.String(), c.config.RouterId)
-			if nextHop.String() == c.config.RouterId {
-				bgpExists = append(bgpExists, d.Prefix)
+			pathNextHop := getNextHopFromPathAttributes(attrInterfaces)
+			klog.V(5).Infof("nexthop is %s, expected nexthop is %s", pathNextHop.String(), nextHop)
+			if pathNextHop.String() == nextHop {
+				bgpExists[d.Prefix] = getRouteAttrs(attrInterfaces)
 				return
 			}
 		}
 	}
 	if err := c.config.BgpServer.ListPath(context.Background(), listPathRequest, fn); err != nil {
-		klog.Errorf("failed to list exist route, %v", err)
+		klog.Errorf("failed to list exist %s route, %v", protocol, err)
 		return
 	}
 
-	klog.V(5).Infof("exists routes %v", bgpExists)
+	klog.V(5).Infof("exists %s routes %v", protocol, bgpExists)
+	metricBgpAnnouncedRoutes.WithLabelValues(c.config.NodeName, protocol).Set(float64(len(bgpExpected)))
 	toAdd, toDel := routeDiff(bgpExpected, bgpExists)
 	klog.V(5).Infof("toAdd routes %v", toAdd)
 	for _, route := range toAdd {
-		if err := c.addRoute(route); err != nil {
+		if err := c.addRoute(route, nextHop, bgpExpected[route]); err != nil {
 			klog.Error(err)
 		}
 	}
 	klog.V(5).Infof("toDel routes %v", toDel)
 	for _, route := range toDel {
-		if err := c.delRoute(route); err != nil {
+		if err := c.delRoute(route, nextHop, routeAttrs{}); err != nil {
 			klog.Error(err)
 		}
 	}
 }
 
-func routeDiff(expected, exists []string) (toAdd []string, toDel []string) {
-	expectedMap, existsMap := map[string]bool{}, map[string]bool{}
-	for _, e := range expected {
-		expectedMap[e] = true
-	}
-	for _, e := range exists {
-		existsMap[e] = true
-	}
-
-	for e := range expectedMap {
-		if !existsMap[e] {
+// routeDiff returns the routes to add, including routes whose path attributes changed, and the routes to delete
+func routeDiff(expected, exists map[string]routeAttrs) (toAdd []string, toDel []string) {
+	for e, attrs := range expected {
+		if existAttrs, ok := exists[e]; !ok || !reflect.DeepEqual(attrs, existAttrs) {
 			toAdd = append(toAdd, e)
 		}
 	}
 
-	for e := range existsMap {
-		if !expectedMap[e] {
+	for e := range exists {
+		if _, ok := expected[e]; !ok {
 			toDel = append(toDel, e)
 		}
 	}
@@ -142,6 +280,9 @@ func routeDiff(expected, exists []string) (toAdd []string, toDel []string) {
 
 func parseRoute(route string) (string, uint32, error) {
 	var prefixLen uint32 = 32
+	if util.CheckProtocol(route) == kubeovnv1.ProtocolIPv6 {
+		prefixLen = 128
+	}
 	prefix := route
 	if strings.Contains(route, "/") {
 		prefix = strings.Split(route, "/")[0]
@@ -155,14 +296,14 @@ func parseRoute(route string) (string, uint32, error) {
 	return prefix, prefixLen, nil
 }
 
-func (c *Controller) addRoute(route string) error {
-	nlri, attrs, err := c.getNlriAndAttrs(route)
+func (c *Controller) addRoute(route, nextHop string, routeAttrs routeAttrs) error {
+	nlri, attrs, err := c.getNlriAndAttrs(route, nextHop, routeAttrs)
 	if err != nil {
 		return err
 	}
 	_, err = c.config.BgpServer.AddPath(context.Background(), &bgpapi.AddPathRequest{
 		Path: &bgpapi.Path{
-			Family: &bgpapi.Family{Afi: bgpapi.Family_AFI_IP, Safi: bgpapi.Family_SAFI_UNICAST},
+			Family: bgpFamilies[util.CheckProtocol(route)],
 			Nlri:   nlri,
 			Pattrs: attrs,
 		},
@@ -174,7 +315,9 @@ func (c *Controller) addRoute(route string) error {
 	return nil
 }
 
-func (c *Controller) getNlriAndAttrs(route string) (*anypb.Any, []*anypb.Any, error) {
+// getNlriAndAttrs returns the nlri and path attributes of the route, gobgp converts the next hop
+// attribute of non ipv4 routes to the MP_REACH_NLRI attribute of MP-BGP
+func (c *Controller) getNlriAndAttrs(route, nextHop string, routeAttrs routeAttrs) (*anypb.Any, []*anypb.Any, error) {
 	prefix, prefixLen, err := parseRoute(route)
 	if err != nil {
 		return nil, nil, err
@@ -187,20 +330,20 @@ func (c *Controller) getNlriAndAttrs(route string) (*anypb.Any, []*anypb.Any, er
 		Origin: 0,
 	})
 	a2, _ := anypb.New(&bgpapi.NextHopAttribute{
-		NextHop: getNextHopAttribute(c.config.NeighborAddress, c.config.RouterId),
+		NextHop: nextHop,
 	})
-	attrs := []*anypb.Any{a1, a2}
+	attrs := append([]*anypb.Any{a1, a2}, routeAttrs.pathAttributes(c.config.ClusterAs)...)
 	return nlri, attrs, err
 }
 
-func (c *Controller) delRoute(route string) error {
-	nlri, attrs, err := c.getNlriAndAttrs(route)
+func (c *Controller) delRoute(route, nextHop string, routeAttrs routeAttrs) error {
+	nlri, attrs, err := c.getNlriAndAttrs(route, nextHop, routeAttrs)
 	if err != nil {
 		return err
 	}
 	err = c.config.BgpServer.DeletePath(context.Background(), &bgpapi.DeletePathRequest{
 		Path: &bgpapi.Path{
-			Family: &bgpapi.Family{Afi: bgpapi.Family_AFI_IP, Safi: bgpapi.Family_SAFI_UNICAST},
+			Family: bgpFamilies[util.CheckProtocol(route)],
 			Nlri:   nlri,
 			Pattrs: attrs,
 		},
@@ -223,6 +366,21 @@ func getNextHopFromPathAttributes(attrs []bgp.PathAttributeInterface) net.IP {
 	}
 	return nil
 }
+
+// getNextHop returns the next hop of routes of the ip family, which is the source address
+// to the neighbor of the same family or the node ip of the family
+func (c *Controller) getNextHop(protocol string) string {
+	nextHop := c.config.NodeIPs[protocol]
+	if protocol == kubeovnv1.ProtocolIPv4 {
+		nextHop = c.config.RouterId
+	}
+	neighborAddress := c.config.neighborAddress(protocol)
+	if util.CheckProtocol(neighborAddress) != protocol {
+		return nextHop
+	}
+	return getNextHopAttribute(neighborAddress, nextHop)
+}
+
 func getNextHopAttribute(NeighborAddress string, RouteId string) string {
 	nextHop := RouteId
 	routes, err := netlink.RouteGet(net.ParseIP(NeighborAddress))
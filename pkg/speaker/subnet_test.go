@@ -0,0 +1,171 @@
+package speaker
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestEndpointSlice(namespace, service string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Labels: map[string]string{}},
+		Endpoints:  endpoints,
+	}
+	if service != "" {
+		slice.Labels[discoveryv1.LabelServiceName] = service
+	}
+	return slice
+}
+
+func newTestEndpoint(node string, ready *bool) discoveryv1.Endpoint {
+	endpoint := discoveryv1.Endpoint{Conditions: discoveryv1.EndpointConditions{Ready: ready}}
+	if node != "" {
+		endpoint.NodeName = &node
+	}
+	return endpoint
+}
+
+func TestLocalServiceKeys(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		slices []*discoveryv1.EndpointSlice
+		want   map[string]bool
+	}{
+		{
+			name: "no slices",
+			want: map[string]bool{},
+		},
+		{
+			name: "ready local endpoint",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("default", "svc1", newTestEndpoint("node2", &yes), newTestEndpoint("node1", &yes)),
+			},
+			want: map[string]bool{"default/svc1": true},
+		},
+		{
+			name: "unknown readiness is ready",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("default", "svc1", newTestEndpoint("node1", nil)),
+			},
+			want: map[string]bool{"default/svc1": true},
+		},
+		{
+			name: "not ready local endpoint",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("default", "svc1", newTestEndpoint("node1", &no)),
+			},
+			want: map[string]bool{},
+		},
+		{
+			name: "endpoints on other nodes or without node",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("default", "svc1", newTestEndpoint("node2", &yes), newTestEndpoint("", &yes)),
+			},
+			want: map[string]bool{},
+		},
+		{
+			name: "slice without service",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("default", "", newTestEndpoint("node1", &yes)),
+			},
+			want: map[string]bool{},
+		},
+		{
+			name: "several slices of services",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("default", "svc1", newTestEndpoint("node2", &yes)),
+				newTestEndpointSlice("default", "svc1", newTestEndpoint("node1", &yes)),
+				newTestEndpointSlice("kube-system", "svc1", newTestEndpoint("node1", &no)),
+				newTestEndpointSlice("kube-system", "svc2", newTestEndpoint("node1", &yes)),
+			},
+			want: map[string]bool{"default/svc1": true, "kube-system/svc2": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localServiceKeys(tt.slices, "node1"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("localServiceKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceAnnouncedIPs(t *testing.T) {
+	clusterIPSvc := &v1.Service{Spec: v1.ServiceSpec{
+		Type:        v1.ServiceTypeClusterIP,
+		ClusterIP:   "10.96.0.10",
+		ClusterIPs:  []string{"10.96.0.10", "fd00:10:96::10"},
+		ExternalIPs: []string{"192.168.0.10"},
+	}}
+	lbSvc := &v1.Service{
+		Spec: v1.ServiceSpec{
+			Type:       v1.ServiceTypeLoadBalancer,
+			ClusterIP:  "10.96.0.11",
+			ClusterIPs: []string{"10.96.0.11"},
+		},
+		Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+			Ingress: []v1.LoadBalancerIngress{{IP: "172.18.0.100"}, {Hostname: "lb.example.com"}},
+		}},
+	}
+	headlessSvc := &v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: v1.ClusterIPNone}}
+
+	tests := []struct {
+		name                        string
+		svc                         *v1.Service
+		clusterIP, lbIP, externalIP bool
+		want                        []string
+	}{
+		{
+			name:      "cluster ips",
+			svc:       clusterIPSvc,
+			clusterIP: true,
+			want:      []string{"10.96.0.10", "fd00:10:96::10"},
+		},
+		{
+			name:      "cluster ip without cluster ips",
+			svc:       &v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: "10.96.0.12"}},
+			clusterIP: true,
+			want:      []string{"10.96.0.12"},
+		},
+		{
+			name:       "cluster and external ips",
+			svc:        clusterIPSvc,
+			clusterIP:  true,
+			externalIP: true,
+			want:       []string{"10.96.0.10", "fd00:10:96::10", "192.168.0.10"},
+		},
+		{
+			name:       "external ips only",
+			svc:        clusterIPSvc,
+			externalIP: true,
+			want:       []string{"192.168.0.10"},
+		},
+		{
+			name:      "headless service",
+			svc:       headlessSvc,
+			clusterIP: true,
+		},
+		{
+			name:      "load balancer ingress ips",
+			svc:       lbSvc,
+			clusterIP: true,
+			lbIP:      true,
+			want:      []string{"172.18.0.100"},
+		},
+		{
+			name: "nothing announced",
+			svc:  lbSvc,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serviceAnnouncedIPs(tt.svc, tt.clusterIP, tt.lbIP, tt.externalIP); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serviceAnnouncedIPs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
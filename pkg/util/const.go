@@ -18,6 +18,11 @@ const (
 	VipAnnotation        = "ovn.kubernetes.io/vip"
 	ChassisAnnotation    = "ovn.kubernetes.io/chassis"
 
+	BgpCommunityAnnotation      = "ovn.kubernetes.io/bgp_community"
+	BgpLargeCommunityAnnotation = "ovn.kubernetes.io/bgp_large_community"
+	BgpLocalPrefAnnotation      = "ovn.kubernetes.io/bgp_local_pref"
+	BgpAsPathPrependAnnotation  = "ovn.kubernetes.io/bgp_as_path_prepend"
+
 	VpcNatGatewayAnnotation     = "ovn.kubernetes.io/vpc_nat_gw"
 	VpcNatGatewayInitAnnotation = "ovn.kubernetes.io/vpc_nat_gw_init"
 	VpcEipsAnnotation           = "ovn.kubernetes.io/vpc_eips"
@@ -31,7 +36,14 @@ const (
 	VpcDnatEPortLabel           = "ovn.kubernetes.io/vpc_dnat_eport"
 	VpcNatLabel                 = "ovn.kubernetes.io/vpc_nat"
 
-	SwitchLBRuleVipsAnnotation = "ovn.kubernetes.io/switch_lb_vip"
+	SwitchLBRuleVipsAnnotation     = "ovn.kubernetes.io/switch_lb_vip"
+	SwitchLBRuleBackendsAnnotation = "ovn.kubernetes.io/switch_lb_backends"
+
+	ServiceHealthCheckAnnotation             = "ovn.kubernetes.io/service_health_check"
+	ServiceHealthCheckIntervalAnnotation     = "ovn.kubernetes.io/service_health_check_interval"
+	ServiceHealthCheckTimeoutAnnotation      = "ovn.kubernetes.io/service_health_check_timeout"
+	ServiceHealthCheckSuccessCountAnnotation = "ovn.kubernetes.io/service_health_check_success_count"
+	ServiceHealthCheckFailureCountAnnotation = "ovn.kubernetes.io/service_health_check_failure_count"
 
 	LogicalRouterAnnotation = "ovn.kubernetes.io/logical_router"
 	VpcAnnotation           = "ovn.kubernetes.io/vpc"
@@ -88,6 +100,7 @@ const (
 	VpcNatGatewayNameLabel     = "ovn.kubernetes.io/vpc-nat-gw-name"
 	VpcLbLabel                 = "ovn.kubernetes.io/vpc_lb"
 	VpcDnsNameLabel            = "ovn.kubernetes.io/vpc-dns"
+	HealthCheckVipLabel        = "ovn.kubernetes.io/health_check_vip"
 	NetworkPolicyLogAnnotation = "ovn.kubernetes.io/enable_log"
 
 	ProtocolTCP  = "tcp"
@@ -182,6 +195,10 @@ const (
 
 	AttachmentProvider = "ovn.kubernetes.io/attachmentprovider"
 	LbSvcPodImg        = "ovn.kubernetes.io/lb_svc_img"
+	LbIPPoolAnnotation = "ovn.kubernetes.io/lb_ip_pool"
+
+	LbSvcModePod    = "pod"
+	LbSvcModeNative = "native"
 
 	OvnICKey   = "origin"
 	OvnICValue = "connected"
@@ -1,9 +1,11 @@
 package util
 
 import (
+	"encoding/json"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func GetNodeInternalIP(node v1.Node) (ipv4, ipv6 string) {
@@ -16,3 +18,31 @@ func GetNodeInternalIP(node v1.Node) (ipv4, ipv6 string) {
 
 	return SplitStringIP(strings.Join(ips, ","))
 }
+
+// GenNodeConditionPatch returns the strategic merge patch of node status to set the condition,
+// or nil if the status, reason and message of the condition do not change.
+// The transition time is kept unless the status changes.
+func GenNodeConditionPatch(node *v1.Node, condition v1.NodeCondition) ([]byte, error) {
+	now := metav1.Now()
+	condition.LastHeartbeatTime = now
+	condition.LastTransitionTime = now
+	for _, c := range node.Status.Conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status && c.Reason == condition.Reason && c.Message == condition.Message {
+			return nil, nil
+		}
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+		break
+	}
+
+	patch := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []v1.NodeCondition{condition},
+		},
+	}
+	return json.Marshal(patch)
+}
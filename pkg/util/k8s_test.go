@@ -0,0 +1,83 @@
+package util
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenNodeConditionPatch(t *testing.T) {
+	transitionTime := metav1.NewTime(metav1.Now().Add(-3600e9).Truncate(1e9))
+	node := &v1.Node{
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{
+				Type:               "Test",
+				Status:             v1.ConditionTrue,
+				Reason:             "Ready",
+				Message:            "ready",
+				LastTransitionTime: transitionTime,
+			}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		condition      v1.NodeCondition
+		wantPatch      bool
+		keepTransition bool
+	}{
+		{
+			name:      "unchanged",
+			condition: v1.NodeCondition{Type: "Test", Status: v1.ConditionTrue, Reason: "Ready", Message: "ready"},
+		},
+		{
+			name:           "message changed",
+			condition:      v1.NodeCondition{Type: "Test", Status: v1.ConditionTrue, Reason: "Ready", Message: "still ready"},
+			wantPatch:      true,
+			keepTransition: true,
+		},
+		{
+			name:      "status changed",
+			condition: v1.NodeCondition{Type: "Test", Status: v1.ConditionFalse, Reason: "NotReady", Message: "not ready"},
+			wantPatch: true,
+		},
+		{
+			name:      "new condition",
+			condition: v1.NodeCondition{Type: "Other", Status: v1.ConditionTrue},
+			wantPatch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GenNodeConditionPatch(node, tt.condition)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantPatch {
+				if data != nil {
+					t.Errorf("expected no patch, got %s", string(data))
+				}
+				return
+			}
+
+			var patch struct {
+				Status v1.NodeStatus `json:"status"`
+			}
+			if err = json.Unmarshal(data, &patch); err != nil {
+				t.Fatalf("failed to unmarshal patch %s: %v", string(data), err)
+			}
+			if len(patch.Status.Conditions) != 1 {
+				t.Fatalf("expected one condition, got %s", string(data))
+			}
+			c := patch.Status.Conditions[0]
+			if c.Type != tt.condition.Type || c.Status != tt.condition.Status || c.Message != tt.condition.Message {
+				t.Errorf("unexpected condition %+v", c)
+			}
+			if keep := c.LastTransitionTime.Equal(&transitionTime); keep != tt.keepTransition {
+				t.Errorf("expected transition time kept %v, got %v", tt.keepTransition, keep)
+			}
+		})
+	}
+}
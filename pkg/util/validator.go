@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -12,6 +13,9 @@ import (
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 )
 
+// addressSetNameRegex matches the names of OVN address sets which can be referenced in ACL matches
+var addressSetNameRegex = regexp.MustCompile(`^[a-zA-Z_.][a-zA-Z_.0-9]*$`)
+
 func ValidateSubnet(subnet kubeovnv1.Subnet) error {
 	if subnet.Spec.Gateway != "" && !CIDRContainIP(subnet.Spec.CIDRBlock, subnet.Spec.Gateway) {
 		return fmt.Errorf(" gateway %s is not in cidr %s", subnet.Spec.Gateway, subnet.Spec.CIDRBlock)
@@ -200,3 +204,81 @@ func ValidateCidrConflict(subnet kubeovnv1.Subnet, subnetList []kubeovnv1.Subnet
 	}
 	return nil
 }
+
+// ValidateSgRule checks a security group rule, the priority is validated by ValidateSgRulePriority
+func ValidateSgRule(rule *kubeovnv1.SgRule) error {
+	if rule.IPVersion != "ipv4" && rule.IPVersion != "ipv6" {
+		return fmt.Errorf("IPVersion should be 'ipv4' or 'ipv6'")
+	}
+
+	switch rule.RemoteType {
+	case kubeovnv1.SgRemoteTypeAddress:
+		if strings.Contains(rule.RemoteAddress, "/") {
+			if _, _, err := net.ParseCIDR(rule.RemoteAddress); err != nil {
+				return fmt.Errorf("invalid CIDR '%s'", rule.RemoteAddress)
+			}
+		} else {
+			if net.ParseIP(rule.RemoteAddress) == nil {
+				return fmt.Errorf("invalid ip address '%s'", rule.RemoteAddress)
+			}
+		}
+	case kubeovnv1.SgRemoteTypeSg:
+		if rule.RemoteSecurityGroup == "" {
+			return fmt.Errorf("remoteSecurityGroup is required for sgRemoteType '%s'", rule.RemoteType)
+		}
+	case kubeovnv1.SgRemoteTypeAddressSet:
+		if rule.RemoteAddressSet == "" {
+			return fmt.Errorf("remoteAddressSet is required for sgRemoteType '%s'", rule.RemoteType)
+		}
+		if !addressSetNameRegex.MatchString(rule.RemoteAddressSet) {
+			return fmt.Errorf("invalid remoteAddressSet '%s', must match %s", rule.RemoteAddressSet, addressSetNameRegex.String())
+		}
+	default:
+		return fmt.Errorf("not support sgRemoteType '%s'", rule.RemoteType)
+	}
+
+	switch rule.Protocol {
+	case kubeovnv1.ProtocolTCP, kubeovnv1.ProtocolUDP, kubeovnv1.ProtocolSCTP:
+		if len(rule.Ports) != 0 {
+			for _, port := range rule.Ports {
+				if port < 1 || port > 65535 {
+					return fmt.Errorf("port '%d' is out of range", port)
+				}
+			}
+			break
+		}
+		if rule.PortRangeMin < 1 || rule.PortRangeMin > 65535 || rule.PortRangeMax < 1 || rule.PortRangeMax > 65535 {
+			return fmt.Errorf("portRange is out of range")
+		}
+		if rule.PortRangeMin > rule.PortRangeMax {
+			return fmt.Errorf("portRange err, range Minimum value greater than maximum value")
+		}
+	case kubeovnv1.ProtocolICMP:
+		if rule.IcmpType != nil && (*rule.IcmpType < 0 || *rule.IcmpType > 255) {
+			return fmt.Errorf("icmpType '%d' is not in the range of 0 to 255", *rule.IcmpType)
+		}
+		if rule.IcmpCode != nil {
+			if rule.IcmpType == nil {
+				return fmt.Errorf("icmpCode requires icmpType")
+			}
+			if *rule.IcmpCode < 0 || *rule.IcmpCode > 255 {
+				return fmt.Errorf("icmpCode '%d' is not in the range of 0 to 255", *rule.IcmpCode)
+			}
+		}
+	}
+
+	if rule.Protocol != kubeovnv1.ProtocolICMP && (rule.IcmpType != nil || rule.IcmpCode != nil) {
+		return fmt.Errorf("icmpType and icmpCode are only supported by protocol '%s'", kubeovnv1.ProtocolICMP)
+	}
+	if rule.Stateless && rule.Policy != kubeovnv1.PolicyAllow {
+		return fmt.Errorf("stateless is only supported by policy '%s'", kubeovnv1.PolicyAllow)
+	}
+	return nil
+}
+
+func ValidateSgRulePriority(rule *kubeovnv1.SgRule) error {
+	if rule.Priority < 1 || rule.Priority > 200 {
+		return fmt.Errorf("priority '%d' is not in the range of 1 to 200", rule.Priority)
+	}
+	return nil
+}
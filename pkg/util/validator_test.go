@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func TestValidateSgRule(t *testing.T) {
+	icmpType, icmpCode, badCode := 8, 0, 256
+	cases := []struct {
+		name    string
+		rule    kubeovnv1.SgRule
+		wantErr bool
+	}{
+		{"tcp range", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolTCP, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.0/8", PortRangeMin: 80, PortRangeMax: 90, Policy: kubeovnv1.PolicyAllow}, false},
+		{"tcp invalid range", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolTCP, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.1", PortRangeMin: 90, PortRangeMax: 80, Policy: kubeovnv1.PolicyAllow}, true},
+		{"udp ports", kubeovnv1.SgRule{IPVersion: "ipv6", Protocol: kubeovnv1.ProtocolUDP, RemoteType: kubeovnv1.SgRemoteTypeAddressSet, RemoteAddressSet: "trusted", Ports: []int{53, 123}, Policy: kubeovnv1.PolicyAllow}, false},
+		{"udp invalid port", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolUDP, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.1", Ports: []int{0}, Policy: kubeovnv1.PolicyAllow}, true},
+		{"sctp ports", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolSCTP, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.0/8", Ports: []int{3868, 2905}, Policy: kubeovnv1.PolicyAllow}, false},
+		{"icmp type and code", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolICMP, RemoteType: kubeovnv1.SgRemoteTypeSg, RemoteSecurityGroup: "sg", IcmpType: &icmpType, IcmpCode: &icmpCode, Policy: kubeovnv1.PolicyAllow}, false},
+		{"icmp code without type", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolICMP, RemoteType: kubeovnv1.SgRemoteTypeSg, RemoteSecurityGroup: "sg", IcmpCode: &icmpCode, Policy: kubeovnv1.PolicyAllow}, true},
+		{"icmp invalid code", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolICMP, RemoteType: kubeovnv1.SgRemoteTypeSg, RemoteSecurityGroup: "sg", IcmpType: &icmpType, IcmpCode: &badCode, Policy: kubeovnv1.PolicyAllow}, true},
+		{"icmp type with tcp", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolTCP, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.1", Ports: []int{22}, IcmpType: &icmpType, Policy: kubeovnv1.PolicyAllow}, true},
+		{"stateless drop", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.1", Policy: kubeovnv1.PolicyDrop, Stateless: true}, true},
+		{"empty address set", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddressSet, Policy: kubeovnv1.PolicyAllow}, true},
+		{"address set with dots", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddressSet, RemoteAddressSet: "ovn.sg.trusted_v4", Policy: kubeovnv1.PolicyAllow}, false},
+		{"address set injection", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddressSet, RemoteAddressSet: "x || ip4", Policy: kubeovnv1.PolicyAllow}, true},
+		{"address set with dash", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddressSet, RemoteAddressSet: "trusted-v4", Policy: kubeovnv1.PolicyAllow}, true},
+		{"address set leading digit", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddressSet, RemoteAddressSet: "1trusted", Policy: kubeovnv1.PolicyAllow}, true},
+		{"address injection", kubeovnv1.SgRule{IPVersion: "ipv4", Protocol: kubeovnv1.ProtocolALL, RemoteType: kubeovnv1.SgRemoteTypeAddress, RemoteAddress: "10.0.0.1 || ip4", Policy: kubeovnv1.PolicyAllow}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateSgRule(&c.rule)
+			if (err != nil) != c.wantErr {
+				t.Errorf("ValidateSgRule() error = %v, wantErr %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func (v *ValidatingHook) SecurityGroupCreateHook(ctx context.Context, req admission.Request) admission.Response {
+	o := ovnv1.SecurityGroup{}
+	if err := v.decoder.Decode(req, &o); err != nil {
+		return ctrlwebhook.Errored(http.StatusBadRequest, err)
+	}
+	return v.validateSecurityGroup(ctx, &o)
+}
+
+func (v *ValidatingHook) SecurityGroupUpdateHook(ctx context.Context, req admission.Request) admission.Response {
+	o := ovnv1.SecurityGroup{}
+	if err := v.decoder.Decode(req, &o); err != nil {
+		return ctrlwebhook.Errored(http.StatusBadRequest, err)
+	}
+	return v.validateSecurityGroup(ctx, &o)
+}
+
+func (v *ValidatingHook) validateSecurityGroup(ctx context.Context, sg *ovnv1.SecurityGroup) admission.Response {
+	allRules := append(sg.Spec.IngressRules, sg.Spec.EgressRules...)
+	for _, rule := range allRules {
+		if err := util.ValidateSgRulePriority(rule); err != nil {
+			return ctrlwebhook.Denied(err.Error())
+		}
+		if err := util.ValidateSgRule(rule); err != nil {
+			return ctrlwebhook.Denied(err.Error())
+		}
+		if rule.RemoteType == ovnv1.SgRemoteTypeSg && rule.RemoteSecurityGroup != sg.Name {
+			remoteSg := ovnv1.SecurityGroup{}
+			if err := v.cache.Get(ctx, types.NamespacedName{Name: rule.RemoteSecurityGroup}, &remoteSg); err != nil {
+				if k8serrors.IsNotFound(err) {
+					return ctrlwebhook.Denied(fmt.Sprintf("remote sg '%s' not found", rule.RemoteSecurityGroup))
+				}
+				return ctrlwebhook.Errored(http.StatusBadRequest, err)
+			}
+		}
+	}
+	return ctrlwebhook.Allowed("by pass")
+}
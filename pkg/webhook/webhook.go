@@ -44,8 +44,10 @@ func NewValidatingHook(c cache.Cache) (*ValidatingHook, error) {
 	createHooks[daemonSetGVK] = v.DaemonSetCreateHook
 	createHooks[podGVK] = v.PodCreateHook
 	createHooks[subnetGVK] = v.SubnetCreateHook
+	createHooks[sgGVK] = v.SecurityGroupCreateHook
 
 	updateHooks[subnetGVK] = v.SubnetUpdateHook
+	updateHooks[sgGVK] = v.SecurityGroupUpdateHook
 
 	deleteHooks[subnetGVK] = v.SubnetDeleteHook
 
@@ -0,0 +1,109 @@
+package slr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kubeovn "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+	"github.com/kubeovn/kube-ovn/test/e2e/framework"
+)
+
+// lbBackends returns the backends of the vip in ovn nb, as listed by lb-list
+func lbBackends(vip string) ([]string, error) {
+	output, err := exec.Command("kubectl", "ko", "nbctl", "lb-list").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, string(output))
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if field == vip && i+1 < len(fields) {
+				return strings.Split(fields[i+1], ","), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+var _ = Describe("[SwitchLBRule]", func() {
+	f := framework.NewFramework("slr", fmt.Sprintf("%s/.kube/config", os.Getenv("HOME")))
+
+	isIPv6 := strings.EqualFold(os.Getenv("IPV6"), "true")
+	vip, backend1, backend2 := "192.168.255.250", "192.168.255.251", "192.168.255.252"
+	if isIPv6 {
+		vip, backend1, backend2 = "fd00:ff::250", "fd00:ff::251", "fd00:ff::252"
+	}
+	name := f.GetName()
+
+	AfterEach(func() {
+		err := f.OvnClientSet.KubeovnV1().SwitchLBRules().Delete(context.Background(), name, metav1.DeleteOptions{})
+		if err != nil {
+			Expect(err.Error()).To(ContainSubstring("not found"))
+		}
+	})
+
+	It("weighted static backends", func() {
+		slr := &kubeovn.SwitchLBRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kubeovn.SwitchLBRuleSpec{
+				Vip:       vip,
+				Namespace: "default",
+				Ports:     []kubeovn.SlrPort{{Name: "http", Port: 80, TargetPort: 8080, Protocol: "TCP"}},
+				Backends: []kubeovn.SlrBackend{
+					{IP: backend1, Weight: 4},
+					{IP: backend2, Weight: 2},
+				},
+			},
+		}
+		_, err := f.OvnClientSet.KubeovnV1().SwitchLBRules().Create(context.Background(), slr, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// weights are reduced by their gcd and backends are repeated in the vips of ovn nb,
+		// which must keep the duplicates for the weights to take effect
+		want := []string{util.JoinHostPort(backend1, 8080), util.JoinHostPort(backend1, 8080), util.JoinHostPort(backend2, 8080)}
+		var backends []string
+		for i := 0; i < 30; i++ {
+			if backends, err = lbBackends(util.JoinHostPort(vip, 80)); err == nil && len(backends) == len(want) {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backends).To(ConsistOf(want))
+
+		rule, err := f.OvnClientSet.KubeovnV1().SwitchLBRules().Get(context.Background(), name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rule.Status.Backends).To(HaveLen(1))
+		Expect(rule.Status.Backends[0].Backends).To(ConsistOf(util.JoinHostPort(backend1, 8080), util.JoinHostPort(backend2, 8080)))
+	})
+
+	It("different target protocol", func() {
+		slr := &kubeovn.SwitchLBRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kubeovn.SwitchLBRuleSpec{
+				Vip:       vip,
+				Namespace: "default",
+				Ports:     []kubeovn.SlrPort{{Name: "dns", Port: 53, Protocol: "UDP", TargetProtocol: "TCP"}},
+				Backends:  []kubeovn.SlrBackend{{IP: backend1}},
+			},
+		}
+		_, err := f.OvnClientSet.KubeovnV1().SwitchLBRules().Create(context.Background(), slr, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// the rule is rejected and no load balancer vip is created
+		time.Sleep(5 * time.Second)
+		backends, err := lbBackends(util.JoinHostPort(vip, 53))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backends).To(BeEmpty())
+	})
+})